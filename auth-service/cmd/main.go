@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 	_ "github.com/luckermt/forum-app/auth-service/docs"
 	"github.com/luckermt/forum-app/auth-service/internal/grpc"
 	"github.com/luckermt/forum-app/auth-service/internal/handler"
+	"github.com/luckermt/forum-app/auth-service/internal/oidc"
 	"github.com/luckermt/forum-app/auth-service/internal/repository"
 	"github.com/luckermt/forum-app/auth-service/internal/service"
 	"github.com/luckermt/forum-app/shared/pkg/config"
@@ -50,7 +54,27 @@ func main() {
 	}
 
 	authService := service.NewAuthService(repo, cfg.JWT.SecretKey)
-	grpcServer := grpc.NewAuthServer(authService)
+	oauthService := service.NewOAuthService(repo, cfg.JWT.SecretKey)
+	adminService := service.NewAdminService(repo, cfg.JWT.SecretKey)
+	communityService := service.NewCommunityService(repo, cfg.JWT.SecretKey)
+
+	// Карма для профилей берется из forum-service, если задан его gRPC порт
+	var karmaClient service.KarmaClient
+	if port := os.Getenv("FORUM_GRPC_PORT"); port != "" {
+		client, err := grpc.NewKarmaClient("localhost:" + port)
+		if err != nil {
+			logger.Log.Fatal("Failed to initialize karma client", zap.Error(err))
+		}
+		defer client.Close()
+		karmaClient = client
+	}
+	profileService := service.NewProfileService(repo, karmaClient)
+
+	serviceCreds, err := grpc.LoadServiceCredentials()
+	if err != nil {
+		logger.Log.Fatal("Invalid admin service credentials", zap.Error(err))
+	}
+	grpcServer := grpc.NewAuthServer(authService, adminService, communityService, profileService, serviceCreds)
 
 	go func() {
 		if err := grpcServer.Start(cfg.GRPC.AuthServicePort); err != nil {
@@ -59,9 +83,44 @@ func main() {
 	}()
 	defer grpcServer.Stop()
 
+	providerConfigs, err := oidc.LoadProviderConfigs()
+	if err != nil {
+		logger.Log.Fatal("Invalid OIDC configuration", zap.Error(err))
+	}
+	var providers []*oidc.Provider
+	for _, providerCfg := range providerConfigs {
+		provider, err := oidc.NewProvider(context.Background(), providerCfg, nil)
+		if err != nil {
+			logger.Log.Fatal("Failed to initialize OIDC provider",
+				zap.String("provider", providerCfg.Name),
+				zap.Error(err))
+		}
+		providers = append(providers, provider)
+	}
+
 	authHandler := handler.NewAuthHandler(authService)
+	oidcHandler := handler.NewOIDCHandler(authService, providers, oidc.NewStateStore(10*time.Minute))
 	http.HandleFunc("/register", authHandler.Register)
 	http.HandleFunc("/login", authHandler.Login)
+	http.HandleFunc("GET /oidc/{provider}/login", oidcHandler.Login)
+	http.HandleFunc("GET /oidc/{provider}/callback", oidcHandler.Callback)
+
+	oauthHandler := handler.NewOAuthHandler(oauthService)
+	http.Handle("POST /oauth/clients", authHandler.AuthMiddleware(http.HandlerFunc(oauthHandler.RegisterClient)))
+	http.Handle("POST /oauth/clients/{id}/revoke", authHandler.AuthMiddleware(http.HandlerFunc(oauthHandler.RevokeClientTokens)))
+	http.HandleFunc("GET /oauth/authorize", oauthHandler.Authorize)
+	http.HandleFunc("POST /oauth/authorize", oauthHandler.Consent)
+	http.HandleFunc("POST /oauth/token", oauthHandler.Token)
+	http.HandleFunc("POST /oauth/revoke", oauthHandler.Revoke)
+
+	profileHandler := handler.NewProfileHandler(profileService)
+	http.HandleFunc("GET /users/{id}", profileHandler.GetProfile)
+
+	communityHandler := handler.NewCommunityHandler(communityService)
+	http.Handle("POST /communities", authHandler.AuthMiddleware(http.HandlerFunc(communityHandler.CreateCommunity)))
+	http.Handle("GET /communities/mine", authHandler.AuthMiddleware(http.HandlerFunc(communityHandler.ListMyCommunities)))
+	http.Handle("POST /communities/{slug}/join", authHandler.AuthMiddleware(http.HandlerFunc(communityHandler.JoinCommunity)))
+	http.Handle("PUT /communities/{slug}/members/{user_id}/role", authHandler.AuthMiddleware(http.HandlerFunc(communityHandler.SetMemberRole)))
 	http.Handle("/swagger/", httpSwagger.Handler(
 		httpSwagger.URL("/swagger/doc.json"),
 	))
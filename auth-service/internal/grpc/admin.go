@@ -0,0 +1,105 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/luckermt/forum-app/auth-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/models"
+	"github.com/luckermt/forum-app/shared/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// BlockUser реализует gRPC метод блокировки пользователя
+func (s *AuthServer) BlockUser(ctx context.Context, req *proto.BlockUserRequest) (*proto.AdminUser, error) {
+	user, err := s.adminService.BlockUser(req.UserId)
+	if err != nil {
+		return nil, adminError(ctx, "BlockUser", err)
+	}
+	return toAdminUser(user), nil
+}
+
+// UnblockUser реализует gRPC метод разблокировки пользователя
+func (s *AuthServer) UnblockUser(ctx context.Context, req *proto.UnblockUserRequest) (*proto.AdminUser, error) {
+	user, err := s.adminService.UnblockUser(req.UserId)
+	if err != nil {
+		return nil, adminError(ctx, "UnblockUser", err)
+	}
+	return toAdminUser(user), nil
+}
+
+// SetRole реализует gRPC метод смены роли пользователя
+func (s *AuthServer) SetRole(ctx context.Context, req *proto.SetRoleRequest) (*proto.AdminUser, error) {
+	user, err := s.adminService.SetRole(req.UserId, req.Role)
+	if err != nil {
+		return nil, adminError(ctx, "SetRole", err)
+	}
+	return toAdminUser(user), nil
+}
+
+// ListUsers реализует gRPC метод постраничного списка пользователей
+func (s *AuthServer) ListUsers(ctx context.Context, req *proto.ListUsersRequest) (*proto.ListUsersResponse, error) {
+	users, next, err := s.adminService.ListUsers(service.ListUsersRequest{
+		PageSize:  int(req.PageSize),
+		PageToken: req.PageToken,
+		Role:      req.Role,
+		Blocked:   req.Blocked,
+		Query:     req.Query,
+	})
+	if err != nil {
+		return nil, adminError(ctx, "ListUsers", err)
+	}
+
+	resp := &proto.ListUsersResponse{
+		Users:         make([]*proto.AdminUser, 0, len(users)),
+		NextPageToken: next,
+	}
+	for _, user := range users {
+		resp.Users = append(resp.Users, toAdminUser(user))
+	}
+	return resp, nil
+}
+
+// RevokeUserTokens реализует gRPC метод отзыва всех токенов пользователя
+func (s *AuthServer) RevokeUserTokens(ctx context.Context, req *proto.RevokeUserTokensRequest) (*proto.RevokeUserTokensResponse, error) {
+	revokedAt, err := s.adminService.RevokeUserTokens(req.UserId)
+	if err != nil {
+		return nil, adminError(ctx, "RevokeUserTokens", err)
+	}
+	return &proto.RevokeUserTokensResponse{
+		RevokedAt: timestamppb.New(revokedAt),
+	}, nil
+}
+
+func toAdminUser(user *models.User) *proto.AdminUser {
+	return &proto.AdminUser{
+		Id:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Role:      user.Role,
+		Blocked:   user.Blocked,
+		CreatedAt: timestamppb.New(user.CreatedAt),
+	}
+}
+
+// adminError переводит ошибки сервиса в gRPC статусы
+func adminError(ctx context.Context, method string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, service.ErrInvalidRole):
+		return status.Error(codes.InvalidArgument, "invalid role")
+	case errors.Is(err, service.ErrInvalidPageToken):
+		return status.Error(codes.InvalidArgument, "invalid page token")
+	default:
+		logger.Log.Error("Admin call failed",
+			zap.String("service", serviceName(ctx)),
+			zap.String("method", method),
+			zap.Error(err))
+		return status.Error(codes.Internal, "internal error")
+	}
+}
@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/luckermt/forum-app/auth-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ResolveCommunity реализует gRPC метод определения сообщества по хосту или slug
+func (s *AuthServer) ResolveCommunity(ctx context.Context, req *proto.ResolveCommunityRequest) (*proto.CommunityResponse, error) {
+	community, err := s.communityService.ResolveCommunity(req.Host, req.Slug)
+	if errors.Is(err, service.ErrCommunityNotFound) {
+		return nil, status.Error(codes.NotFound, "community not found")
+	}
+	if err != nil {
+		logger.Log.Error("Failed to resolve community",
+			zap.String("host", req.Host),
+			zap.String("slug", req.Slug),
+			zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &proto.CommunityResponse{
+		Id:   community.ID,
+		Slug: community.Slug,
+		Name: community.Name,
+	}, nil
+}
+
+// GetMemberRole реализует gRPC метод получения роли пользователя в сообществе
+func (s *AuthServer) GetMemberRole(ctx context.Context, req *proto.MemberRoleRequest) (*proto.MemberRoleResponse, error) {
+	role, blocked, err := s.communityService.GetMemberRole(req.CommunityId, req.UserId)
+	if errors.Is(err, service.ErrCommunityNotFound) {
+		return nil, status.Error(codes.NotFound, "community not found")
+	}
+	if err != nil {
+		logger.Log.Error("Failed to get member role",
+			zap.String("community_id", req.CommunityId),
+			zap.String("user_id", req.UserId),
+			zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	return &proto.MemberRoleResponse{
+		Role:    role,
+		Blocked: blocked,
+	}, nil
+}
@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var adminServicePrefix = "/" + proto.AuthAdminService_ServiceDesc.ServiceName + "/"
+
+// ServiceCredentials сопоставляет ключи служебных сервисов с их именами
+type ServiceCredentials struct {
+	keys map[[sha256.Size]byte]string
+}
+
+type serviceNameKey struct{}
+
+// LoadServiceCredentials читает ключи из AUTH_ADMIN_SERVICE_KEYS
+// в формате "name:key,name2:key2"
+func LoadServiceCredentials() (*ServiceCredentials, error) {
+	creds := &ServiceCredentials{keys: make(map[[sha256.Size]byte]string)}
+	for _, entry := range strings.Split(os.Getenv("AUTH_ADMIN_SERVICE_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, key, ok := strings.Cut(entry, ":")
+		if !ok || name == "" || len(key) < 16 {
+			return nil, fmt.Errorf("invalid service key entry %q: expected name:key with key of at least 16 characters", name)
+		}
+		creds.Add(name, key)
+	}
+	return creds, nil
+}
+
+// Add регистрирует ключ служебного сервиса
+func (c *ServiceCredentials) Add(name, key string) {
+	c.keys[sha256.Sum256([]byte(key))] = name
+}
+
+// authenticate возвращает имя сервиса, которому принадлежит ключ
+func (c *ServiceCredentials) authenticate(key string) (string, bool) {
+	sum := sha256.Sum256([]byte(key))
+	for known, name := range c.keys {
+		if subtle.ConstantTimeCompare(known[:], sum[:]) == 1 {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// UnaryInterceptor требует ключ сервиса для методов AuthAdminService.
+// Остальные методы пропускаются без проверки.
+func (c *ServiceCredentials) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !strings.HasPrefix(info.FullMethod, adminServicePrefix) {
+		return handler(ctx, req)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "service credentials required")
+	}
+
+	key, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "service credentials required")
+	}
+
+	name, ok := c.authenticate(key)
+	if !ok {
+		logger.Log.Warn("Rejected admin call with unknown service key",
+			zap.String("method", info.FullMethod))
+		return nil, status.Error(codes.Unauthenticated, "invalid service credentials")
+	}
+
+	logger.Log.Info("Admin call",
+		zap.String("service", name),
+		zap.String("method", info.FullMethod))
+	return handler(context.WithValue(ctx, serviceNameKey{}, name), req)
+}
+
+func serviceName(ctx context.Context) string {
+	name, _ := ctx.Value(serviceNameKey{}).(string)
+	return name
+}
@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	authmodels "github.com/luckermt/forum-app/auth-service/internal/models"
+	"github.com/luckermt/forum-app/shared/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// karmaTimeout ограничивает ожидание forum-service при отдаче профиля
+const karmaTimeout = 2 * time.Second
+
+// KarmaClient получает карму пользователей из forum-service по gRPC
+type KarmaClient struct {
+	conn   *grpc.ClientConn
+	client proto.KarmaServiceClient
+}
+
+func NewKarmaClient(addr string) (*KarmaClient, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &KarmaClient{
+		conn:   conn,
+		client: proto.NewKarmaServiceClient(conn),
+	}, nil
+}
+
+func (c *KarmaClient) GetUserKarma(userID string) (*authmodels.Karma, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), karmaTimeout)
+	defer cancel()
+
+	resp, err := c.client.GetUserKarma(ctx, &proto.UserKarmaRequest{UserId: userID})
+	if err != nil {
+		return nil, err
+	}
+	return &authmodels.Karma{
+		Karma:     resp.Karma,
+		Upvotes:   resp.Upvotes,
+		Downvotes: resp.Downvotes,
+	}, nil
+}
+
+func (c *KarmaClient) Close() error {
+	return c.conn.Close()
+}
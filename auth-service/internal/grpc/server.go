@@ -13,19 +13,34 @@ import (
 )
 
 // AuthServer реализует gRPC сервер для аутентификации
+// и внутренний административный API
 type AuthServer struct {
-	grpcServer  *grpc.Server
-	authService service.AuthService
+	grpcServer       *grpc.Server
+	authService      service.AuthService
+	adminService     service.AdminService
+	communityService service.CommunityService
+	profileService   service.ProfileService
 	proto.UnimplementedAuthServiceServer
+	proto.UnimplementedAuthAdminServiceServer
+	proto.UnimplementedCommunityServiceServer
+	proto.UnimplementedUserDirectoryServiceServer
 }
 
 // NewAuthServer создает новый экземпляр AuthServer
-func NewAuthServer(authService service.AuthService) *AuthServer {
-	srv := grpc.NewServer()
-	return &AuthServer{
-		grpcServer:  srv,
-		authService: authService,
+func NewAuthServer(authService service.AuthService, adminService service.AdminService, communityService service.CommunityService, profileService service.ProfileService, creds *ServiceCredentials) *AuthServer {
+	srv := grpc.NewServer(grpc.UnaryInterceptor(creds.UnaryInterceptor))
+	s := &AuthServer{
+		grpcServer:       srv,
+		authService:      authService,
+		adminService:     adminService,
+		communityService: communityService,
+		profileService:   profileService,
 	}
+	proto.RegisterAuthServiceServer(srv, s)
+	proto.RegisterAuthAdminServiceServer(srv, s)
+	proto.RegisterCommunityServiceServer(srv, s)
+	proto.RegisterUserDirectoryServiceServer(srv, s)
+	return s
 }
 
 // Start запускает gRPC сервер
@@ -52,13 +67,15 @@ func (s *AuthServer) Stop() {
 
 // ValidateToken реализует gRPC метод проверки токена
 func (s *AuthServer) ValidateToken(ctx context.Context, req *proto.TokenRequest) (*proto.TokenResponse, error) {
-	userID, err := s.authService.ValidateToken(req.Token)
+	info, err := s.authService.ValidateToken(req.Token)
 	if err != nil {
 		return &proto.TokenResponse{Valid: false}, nil
 	}
 	return &proto.TokenResponse{
-		Valid:  true,
-		UserId: userID,
+		Valid:    true,
+		UserId:   info.UserID,
+		ClientId: info.ClientID,
+		Scopes:   info.Scopes,
 	}, nil
 }
 
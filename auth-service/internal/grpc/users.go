@@ -0,0 +1,45 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ResolveUsernames реализует gRPC метод поиска пользователей по именам.
+// С community_id отдаются только незаблокированные участники сообщества.
+func (s *AuthServer) ResolveUsernames(ctx context.Context, req *proto.ResolveUsernamesRequest) (*proto.ResolveUsernamesResponse, error) {
+	users, err := s.profileService.ResolveUsernames(req.Usernames)
+	if err != nil {
+		logger.Log.Error("Failed to resolve usernames",
+			zap.Int("count", len(req.Usernames)),
+			zap.Error(err))
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	resp := &proto.ResolveUsernamesResponse{}
+	for _, user := range users {
+		if req.CommunityId != "" {
+			role, blocked, err := s.communityService.GetMemberRole(req.CommunityId, user.ID)
+			if err != nil {
+				logger.Log.Error("Failed to get member role",
+					zap.String("community_id", req.CommunityId),
+					zap.String("user_id", user.ID),
+					zap.Error(err))
+				return nil, status.Error(codes.Internal, "internal error")
+			}
+			if role == "" || blocked {
+				continue
+			}
+		}
+		resp.Users = append(resp.Users, &proto.ResolvedUser{
+			Id:       user.ID,
+			Username: user.Username,
+		})
+	}
+	return resp, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/luckermt/forum-app/auth-service/internal/service"
 	"github.com/luckermt/forum-app/shared/pkg/logger"
 	"github.com/luckermt/forum-app/shared/pkg/models"
+	"github.com/luckermt/forum-app/shared/pkg/validator"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -30,7 +31,7 @@ func NewAuthHandler(authService service.AuthService) *AuthHandler {
 type RegisterRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50" example:"john_doe"`
 	Email    string `json:"email" binding:"required,email" example:"user@example.com"`
-	Password string `json:"password" binding:"required,min=8,max=72" example:"SecurePass123!"`
+	Password string `json:"password" binding:"required,min=8,maxbytes=72" example:"SecurePass123!"`
 }
 
 // LoginRequest модель запроса входа
@@ -59,7 +60,8 @@ type UserResponse struct {
 // ErrorResponse модель ошибки
 // swagger:model
 type ErrorResponse struct {
-	Message string `json:"message" example:"error message"`
+	Message string                 `json:"message" example:"error message"`
+	Errors  []validator.FieldError `json:"errors,omitempty"`
 }
 
 // Register обработчик регистрации пользователя
@@ -83,9 +85,9 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := validatePassword(req.Password); err != nil {
-		logger.Log.Warn("Password validation failed", zap.Error(err))
-		writeError(w, err.Error(), http.StatusBadRequest)
+	if err := validator.Validate(&req); err != nil {
+		logger.Log.Warn("Registration validation failed", zap.Error(err))
+		writeValidationError(w, err)
 		return
 	}
 
@@ -148,6 +150,11 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := validator.Validate(&req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
 	token, err := h.authService.Login(req.Email, req.Password)
 	if err != nil {
 		logger.Log.Warn("Login attempt failed",
@@ -175,12 +182,6 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		Token: token,
 	})
 }
-func validatePassword(password string) error {
-	if len(password) < 8 {
-		return errors.New("password must be at least 8 characters long")
-	}
-	return nil
-}
 
 func writeError(w http.ResponseWriter, message string, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
@@ -188,6 +189,21 @@ func writeError(w http.ResponseWriter, message string, statusCode int) {
 	json.NewEncoder(w).Encode(ErrorResponse{Message: message})
 }
 
+func writeValidationError(w http.ResponseWriter, err error) {
+	var fieldErrors validator.Errors
+	if !errors.As(err, &fieldErrors) {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(ErrorResponse{
+		Message: "Validation failed",
+		Errors:  fieldErrors,
+	})
+}
+
 func handleServiceError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, service.ErrEmailExists):
@@ -207,13 +223,18 @@ func (h *AuthHandler) AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		userID, err := h.authService.ValidateToken(tokenString)
+		info, err := h.authService.ValidateToken(tokenString)
 		if err != nil {
 			writeError(w, "Invalid token", http.StatusUnauthorized)
 			return
 		}
+		// Эти маршруты доступны только самому пользователю, но не сторонним приложениям
+		if info.ThirdParty() {
+			writeError(w, "Third-party application tokens are not accepted", http.StatusForbidden)
+			return
+		}
 
-		ctx := context.WithValue(r.Context(), "userID", userID)
+		ctx := context.WithValue(r.Context(), "userID", info.UserID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	authmodels "github.com/luckermt/forum-app/auth-service/internal/models"
+	"github.com/luckermt/forum-app/auth-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/validator"
+	"go.uber.org/zap"
+)
+
+type CommunityHandler struct {
+	communityService service.CommunityService
+}
+
+func NewCommunityHandler(communityService service.CommunityService) *CommunityHandler {
+	return &CommunityHandler{
+		communityService: communityService,
+	}
+}
+
+// CreateCommunity создает сообщество
+// @Summary Создание сообщества
+// @Description Создает сообщество, создатель становится его администратором
+// @Tags communities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body authmodels.CommunityRequest true "Данные сообщества"
+// @Success 201 {object} authmodels.Community
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /communities [post]
+func (h *CommunityHandler) CreateCommunity(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	var req authmodels.CommunityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log.Error("Failed to decode request", zap.Error(err))
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := validator.Validate(&req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	community, err := h.communityService.CreateCommunity(userID, req)
+	if err != nil {
+		handleCommunityError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(community)
+}
+
+// ListMyCommunities возвращает сообщества текущего пользователя
+// @Summary Мои сообщества
+// @Description Список сообществ, в которых состоит пользователь, с его ролями
+// @Tags communities
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} authmodels.CommunityMember
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /communities/mine [get]
+func (h *CommunityHandler) ListMyCommunities(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	members, err := h.communityService.ListUserCommunities(userID)
+	if err != nil {
+		handleCommunityError(w, err)
+		return
+	}
+	if members == nil {
+		members = []*authmodels.CommunityMember{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(members)
+}
+
+// JoinCommunity добавляет текущего пользователя в открытое сообщество
+// @Summary Вступление в сообщество
+// @Tags communities
+// @Produce json
+// @Security BearerAuth
+// @Param slug path string true "Slug сообщества"
+// @Success 201 {object} authmodels.CommunityMember
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /communities/{slug}/join [post]
+func (h *CommunityHandler) JoinCommunity(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	member, err := h.communityService.JoinCommunity(r.PathValue("slug"), userID)
+	if err != nil {
+		handleCommunityError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(member)
+}
+
+// SetMemberRole меняет роль участника сообщества
+// @Summary Смена роли участника
+// @Description Доступно администраторам сообщества
+// @Tags communities
+// @Accept json
+// @Security BearerAuth
+// @Param slug path string true "Slug сообщества"
+// @Param user_id path string true "ID пользователя"
+// @Param request body authmodels.MemberRoleRequest true "Новая роль"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /communities/{slug}/members/{user_id}/role [put]
+func (h *CommunityHandler) SetMemberRole(w http.ResponseWriter, r *http.Request) {
+	actorID, _ := r.Context().Value("userID").(string)
+
+	var req authmodels.MemberRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log.Error("Failed to decode request", zap.Error(err))
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := validator.Validate(&req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	err := h.communityService.SetMemberRole(actorID, r.PathValue("slug"), r.PathValue("user_id"), req.Role)
+	if err != nil {
+		handleCommunityError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func handleCommunityError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrCommunityNotFound):
+		writeError(w, "Community not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrNotMember):
+		writeError(w, "User is not a member of the community", http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidSlug):
+		writeError(w, "Slug may contain only lowercase letters, digits and dashes", http.StatusBadRequest)
+	case errors.Is(err, service.ErrInvalidRole):
+		writeError(w, "Invalid role", http.StatusBadRequest)
+	case errors.Is(err, service.ErrSlugExists):
+		writeError(w, "Slug already taken", http.StatusConflict)
+	case errors.Is(err, service.ErrHostExists):
+		writeError(w, "Host already taken", http.StatusConflict)
+	case errors.Is(err, service.ErrAlreadyMember):
+		writeError(w, "Already a member", http.StatusConflict)
+	case errors.Is(err, service.ErrForbidden):
+		writeError(w, "Forbidden", http.StatusForbidden)
+	default:
+		logger.Log.Error("Community request failed", zap.Error(err))
+		writeError(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
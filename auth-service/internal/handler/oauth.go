@@ -0,0 +1,374 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+
+	authmodels "github.com/luckermt/forum-app/auth-service/internal/models"
+	"github.com/luckermt/forum-app/auth-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/validator"
+	"go.uber.org/zap"
+)
+
+type OAuthHandler struct {
+	oauthService service.OAuthService
+}
+
+func NewOAuthHandler(oauthService service.OAuthService) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: oauthService,
+	}
+}
+
+// OAuthErrorResponse модель ошибки OAuth2 (RFC 6749, раздел 5.2)
+// swagger:model
+type OAuthErrorResponse struct {
+	Error            string `json:"error" example:"invalid_grant"`
+	ErrorDescription string `json:"error_description,omitempty" example:"authorization code is invalid or expired"`
+}
+
+var consentTemplate = template.Must(template.New("consent").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Authorize {{.ClientName}}</title></head>
+<body>
+<h1>{{.ClientName}} wants to access your forum account</h1>
+<p>The application is requesting:</p>
+<ul>{{range .Scopes}}<li>{{.}}</li>{{end}}</ul>
+{{if .Error}}<p style="color:red">{{.Error}}</p>{{end}}
+<form method="post" action="/oauth/authorize">
+  <input type="hidden" name="client_id" value="{{.Request.ClientID}}">
+  <input type="hidden" name="redirect_uri" value="{{.Request.RedirectURI}}">
+  <input type="hidden" name="scope" value="{{.Request.Scope}}">
+  <input type="hidden" name="state" value="{{.Request.State}}">
+  <input type="hidden" name="code_challenge" value="{{.Request.CodeChallenge}}">
+  <input type="hidden" name="code_challenge_method" value="{{.Request.CodeChallengeMethod}}">
+  <p><label>Email <input type="email" name="email" required></label></p>
+  <p><label>Password <input type="password" name="password" required></label></p>
+  <button type="submit" name="action" value="allow">Allow</button>
+  <button type="submit" name="action" value="deny" formnovalidate>Deny</button>
+</form>
+</body>
+</html>`))
+
+type consentPage struct {
+	ClientName string
+	Scopes     []string
+	Request    service.AuthorizeRequest
+	Error      string
+}
+
+// RegisterClient регистрирует стороннее приложение
+// @Summary Регистрация OAuth2 клиента
+// @Description Создает клиента для стороннего приложения. Секрет возвращается только один раз
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body authmodels.OAuthClientRequest true "Данные клиента"
+// @Success 201 {object} authmodels.OAuthClientResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /oauth/clients [post]
+func (h *OAuthHandler) RegisterClient(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value("userID").(string)
+
+	var req authmodels.OAuthClientRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log.Error("Failed to decode request", zap.Error(err))
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := validator.Validate(&req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	client, err := h.oauthService.RegisterClient(ownerID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRedirectURI):
+			writeError(w, "Invalid redirect uri", http.StatusBadRequest)
+		case errors.Is(err, service.ErrInvalidScope):
+			writeError(w, "Invalid scope", http.StatusBadRequest)
+		default:
+			logger.Log.Error("Failed to register oauth client", zap.Error(err))
+			writeError(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(client)
+}
+
+// RevokeClientTokens отзывает все токены клиента
+// @Summary Отзыв токенов клиента
+// @Description Отзывает все выданные клиенту токены (только для владельца клиента)
+// @Tags oauth
+// @Security BearerAuth
+// @Param id path string true "ID клиента"
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /oauth/clients/{id}/revoke [post]
+func (h *OAuthHandler) RevokeClientTokens(w http.ResponseWriter, r *http.Request) {
+	ownerID, _ := r.Context().Value("userID").(string)
+
+	if err := h.oauthService.RevokeClientTokens(ownerID, r.PathValue("id")); err != nil {
+		if errors.Is(err, service.ErrInvalidClient) {
+			writeError(w, "Client not found", http.StatusNotFound)
+			return
+		}
+		logger.Log.Error("Failed to revoke client tokens", zap.Error(err))
+		writeError(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Authorize показывает экран согласия
+// @Summary Экран согласия OAuth2
+// @Description Authorization endpoint: показывает пользователю запрошенные приложением права
+// @Tags oauth
+// @Produce html
+// @Param response_type query string true "Должен быть code"
+// @Param client_id query string true "ID клиента"
+// @Param redirect_uri query string true "Адрес возврата"
+// @Param scope query string false "Области доступа через пробел"
+// @Param state query string false "Состояние клиента"
+// @Param code_challenge query string true "PKCE challenge"
+// @Param code_challenge_method query string true "Должен быть S256"
+// @Success 200
+// @Failure 400 {object} ErrorResponse
+// @Router /oauth/authorize [get]
+func (h *OAuthHandler) Authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := authorizeRequestFrom(query)
+
+	client, scopes, err := h.oauthService.ValidateAuthorizeRequest(req)
+	if !h.checkAuthorizeRequest(w, r, req, err) {
+		return
+	}
+	if query.Get("response_type") != "code" {
+		redirectWithError(w, r, req, "unsupported_response_type")
+		return
+	}
+
+	renderConsent(w, http.StatusOK, consentPage{
+		ClientName: client.Name,
+		Scopes:     scopes,
+		Request:    req,
+	})
+}
+
+// Consent обрабатывает решение пользователя на экране согласия
+// @Summary Подтверждение доступа OAuth2
+// @Description Проверяет учетные данные пользователя и перенаправляет с кодом авторизации
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Success 302
+// @Failure 400 {object} ErrorResponse
+// @Router /oauth/authorize [post]
+func (h *OAuthHandler) Consent(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req := authorizeRequestFrom(r.PostForm)
+
+	client, scopes, err := h.oauthService.ValidateAuthorizeRequest(req)
+	if !h.checkAuthorizeRequest(w, r, req, err) {
+		return
+	}
+
+	if r.PostForm.Get("action") != "allow" {
+		redirectWithError(w, r, req, "access_denied")
+		return
+	}
+
+	user, err := h.oauthService.Authenticate(r.PostForm.Get("email"), r.PostForm.Get("password"))
+	if err != nil {
+		message := "Invalid email or password"
+		if errors.Is(err, service.ErrUserBlocked) {
+			message = "Account is blocked"
+		}
+		renderConsent(w, http.StatusUnauthorized, consentPage{
+			ClientName: client.Name,
+			Scopes:     scopes,
+			Request:    req,
+			Error:      message,
+		})
+		return
+	}
+
+	code, err := h.oauthService.Authorize(user.ID, req)
+	if err != nil {
+		logger.Log.Error("Failed to issue authorization code", zap.Error(err))
+		redirectWithError(w, r, req, "server_error")
+		return
+	}
+
+	target, _ := url.Parse(req.RedirectURI)
+	params := target.Query()
+	params.Set("code", code)
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+	target.RawQuery = params.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+// Token обменивает код авторизации на токен доступа
+// @Summary Выдача токена OAuth2
+// @Description Token endpoint: grant_type=authorization_code с PKCE
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Success 200 {object} authmodels.OAuthTokenResponse
+// @Failure 400 {object} OAuthErrorResponse
+// @Failure 401 {object} OAuthErrorResponse
+// @Router /oauth/token [post]
+func (h *OAuthHandler) Token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, "invalid_request", "malformed form body", http.StatusBadRequest)
+		return
+	}
+
+	clientID, clientSecret := clientCredentials(r)
+	resp, err := h.oauthService.ExchangeCode(service.TokenRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		Code:         r.PostForm.Get("code"),
+		RedirectURI:  r.PostForm.Get("redirect_uri"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnsupportedGrantType):
+			writeOAuthError(w, "unsupported_grant_type", "", http.StatusBadRequest)
+		case errors.Is(err, service.ErrInvalidClient):
+			writeOAuthError(w, "invalid_client", "", http.StatusUnauthorized)
+		case errors.Is(err, service.ErrInvalidGrant), errors.Is(err, service.ErrUserBlocked):
+			writeOAuthError(w, "invalid_grant", "authorization code is invalid or expired", http.StatusBadRequest)
+		default:
+			logger.Log.Error("Failed to exchange authorization code", zap.Error(err))
+			writeOAuthError(w, "server_error", "", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Revoke отзывает токен доступа
+// @Summary Отзыв токена OAuth2
+// @Description Revocation endpoint (RFC 7009): клиент отзывает выданный ему токен
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Success 200
+// @Failure 401 {object} OAuthErrorResponse
+// @Router /oauth/revoke [post]
+func (h *OAuthHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, "invalid_request", "malformed form body", http.StatusBadRequest)
+		return
+	}
+
+	clientID, clientSecret := clientCredentials(r)
+	if err := h.oauthService.RevokeToken(clientID, clientSecret, r.PostForm.Get("token")); err != nil {
+		if errors.Is(err, service.ErrInvalidClient) {
+			writeOAuthError(w, "invalid_client", "", http.StatusUnauthorized)
+			return
+		}
+		logger.Log.Error("Failed to revoke token", zap.Error(err))
+		writeOAuthError(w, "server_error", "", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// checkAuthorizeRequest обрабатывает ошибки проверки запроса авторизации.
+// При неверном клиенте или redirect_uri перенаправлять нельзя, поэтому
+// ошибка показывается пользователю напрямую.
+func (h *OAuthHandler) checkAuthorizeRequest(w http.ResponseWriter, r *http.Request, req service.AuthorizeRequest, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, service.ErrInvalidClient):
+		writeError(w, "Unknown client", http.StatusBadRequest)
+	case errors.Is(err, service.ErrInvalidRedirectURI):
+		writeError(w, "Invalid redirect uri", http.StatusBadRequest)
+	case errors.Is(err, service.ErrInvalidScope):
+		redirectWithError(w, r, req, "invalid_scope")
+	case errors.Is(err, service.ErrPKCERequired):
+		redirectWithError(w, r, req, "invalid_request")
+	default:
+		logger.Log.Error("Failed to validate authorize request", zap.Error(err))
+		writeError(w, "Internal server error", http.StatusInternalServerError)
+	}
+	return false
+}
+
+func authorizeRequestFrom(values url.Values) service.AuthorizeRequest {
+	return service.AuthorizeRequest{
+		ClientID:            values.Get("client_id"),
+		RedirectURI:         values.Get("redirect_uri"),
+		Scope:               values.Get("scope"),
+		State:               values.Get("state"),
+		CodeChallenge:       values.Get("code_challenge"),
+		CodeChallengeMethod: values.Get("code_challenge_method"),
+	}
+}
+
+func clientCredentials(r *http.Request) (string, string) {
+	if clientID, clientSecret, ok := r.BasicAuth(); ok {
+		return clientID, clientSecret
+	}
+	return r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+}
+
+func redirectWithError(w http.ResponseWriter, r *http.Request, req service.AuthorizeRequest, code string) {
+	target, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		writeError(w, "Invalid redirect uri", http.StatusBadRequest)
+		return
+	}
+	params := target.Query()
+	params.Set("error", code)
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+	target.RawQuery = params.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func renderConsent(w http.ResponseWriter, statusCode int, page consentPage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.WriteHeader(statusCode)
+	if err := consentTemplate.Execute(w, page); err != nil {
+		logger.Log.Error("Failed to render consent page", zap.Error(err))
+	}
+}
+
+func writeOAuthError(w http.ResponseWriter, code, description string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(OAuthErrorResponse{
+		Error:            code,
+		ErrorDescription: description,
+	})
+}
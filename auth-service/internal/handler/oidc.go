@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/luckermt/forum-app/auth-service/internal/oidc"
+	"github.com/luckermt/forum-app/auth-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type OIDCHandler struct {
+	authService service.AuthService
+	providers   map[string]*oidc.Provider
+	states      *oidc.StateStore
+}
+
+func NewOIDCHandler(authService service.AuthService, providers []*oidc.Provider, states *oidc.StateStore) *OIDCHandler {
+	byName := make(map[string]*oidc.Provider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+	return &OIDCHandler{
+		authService: authService,
+		providers:   byName,
+		states:      states,
+	}
+}
+
+// Login перенаправляет пользователя на страницу входа внешнего провайдера
+// @Summary Вход через внешний провайдер
+// @Description Начинает authorization code flow с PKCE у OIDC-провайдера
+// @Tags auth
+// @Param provider path string true "Имя провайдера"
+// @Success 302
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /oidc/{provider}/login [get]
+func (h *OIDCHandler) Login(w http.ResponseWriter, r *http.Request) {
+	provider, ok := h.providers[r.PathValue("provider")]
+	if !ok {
+		writeError(w, "Unknown identity provider", http.StatusNotFound)
+		return
+	}
+
+	state, auth, err := h.states.Begin(provider.Name())
+	if err != nil {
+		logger.Log.Error("Failed to create auth state", zap.Error(err))
+		writeError(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, provider.AuthCodeURL(state, auth), http.StatusFound)
+}
+
+// Callback завершает вход через внешний провайдер и выдает JWT форума
+// @Summary Возврат от внешнего провайдера
+// @Description Обменивает код авторизации на ID токен, связывает учетную запись и выдает JWT
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Имя провайдера"
+// @Param code query string true "Код авторизации"
+// @Param state query string true "Состояние авторизации"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	provider, ok := h.providers[r.PathValue("provider")]
+	if !ok {
+		writeError(w, "Unknown identity provider", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	if errCode := query.Get("error"); errCode != "" {
+		logger.Log.Warn("Identity provider returned error",
+			zap.String("provider", provider.Name()),
+			zap.String("error", errCode))
+		writeError(w, "Authorization was denied", http.StatusUnauthorized)
+		return
+	}
+
+	auth, ok := h.states.Take(query.Get("state"))
+	if !ok || auth.Provider != provider.Name() {
+		writeError(w, "Invalid or expired state", http.StatusBadRequest)
+		return
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		writeError(w, "Authorization code required", http.StatusBadRequest)
+		return
+	}
+
+	claims, err := provider.Exchange(r.Context(), code, auth)
+	if err != nil {
+		logger.Log.Warn("Failed to complete federated login",
+			zap.String("provider", provider.Name()),
+			zap.Error(err))
+		writeError(w, "Failed to verify identity", http.StatusUnauthorized)
+		return
+	}
+
+	token, err := h.authService.LoginWithIdentity(service.ExternalIdentity{
+		Provider:      provider.Name(),
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Username:      claims.PreferredUsername,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmailNotVerified):
+			writeError(w, "Verified email required", http.StatusForbidden)
+		case errors.Is(err, service.ErrUserBlocked):
+			writeError(w, "Account is blocked", http.StatusForbidden)
+		default:
+			logger.Log.Error("Federated login failed", zap.Error(err))
+			writeError(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(LoginResponse{
+		Token: token,
+	})
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// DefaultCommunityID сообщество, в котором находятся данные,
+// созданные до появления сообществ
+const DefaultCommunityID = "default"
+
+// Community независимое сообщество со своими участниками и ролями
+type Community struct {
+	ID        string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Slug      string    `json:"slug" example:"golang"`
+	Name      string    `json:"name" example:"Go community"`
+	Host      string    `json:"host,omitempty" example:"go.forum.example.com"`
+	Open      bool      `json:"open" example:"true"`
+	OwnerID   string    `json:"owner_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CommunityMember участие пользователя в сообществе
+type CommunityMember struct {
+	CommunityID string    `json:"community_id"`
+	UserID      string    `json:"user_id"`
+	Role        string    `json:"role" example:"moderator"`
+	Blocked     bool      `json:"blocked"`
+	JoinedAt    time.Time `json:"joined_at"`
+}
+
+// CommunityRequest модель запроса создания сообщества
+type CommunityRequest struct {
+	Slug string `json:"slug" binding:"required,min=2,max=50" example:"golang"`
+	Name string `json:"name" binding:"required,max=100" example:"Go community"`
+	Host string `json:"host" binding:"max=255" example:"go.forum.example.com"`
+	Open bool   `json:"open" example:"true"`
+}
+
+// MemberRoleRequest модель запроса смены роли участника
+type MemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user moderator admin" example:"moderator"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// OAuthClient стороннее приложение, зарегистрированное пользователем
+type OAuthClient struct {
+	ID           string    `json:"client_id"`
+	SecretHash   string    `json:"-"`
+	Name         string    `json:"name"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	Scopes       []string  `json:"scopes"`
+	OwnerID      string    `json:"owner_id"`
+	Public       bool      `json:"public"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// OAuthAuthorizationCode одноразовый код, выданный после согласия пользователя
+type OAuthAuthorizationCode struct {
+	CodeHash      string
+	ClientID      string
+	UserID        string
+	RedirectURI   string
+	Scopes        []string
+	CodeChallenge string
+	ExpiresAt     time.Time
+}
+
+// OAuthAccessToken запись о выданном токене доступа, нужна для отзыва
+type OAuthAccessToken struct {
+	ID        string
+	ClientID  string
+	UserID    string
+	Scopes    []string
+	ExpiresAt time.Time
+	Revoked   bool
+	CreatedAt time.Time
+}
+
+// OAuthClientRequest модель запроса регистрации клиента
+type OAuthClientRequest struct {
+	Name         string   `json:"name" binding:"required,max=100" example:"Forum Mobile"`
+	RedirectURIs []string `json:"redirect_uris" binding:"required" example:"https://app.example.com/callback"`
+	Scopes       []string `json:"scopes" example:"read"`
+	Public       bool     `json:"public" example:"false"`
+}
+
+// OAuthClientResponse модель ответа на регистрацию клиента.
+// Секрет возвращается только один раз.
+type OAuthClientResponse struct {
+	OAuthClient
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// OAuthTokenResponse модель ответа token endpoint
+type OAuthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
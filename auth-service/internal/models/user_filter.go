@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// UserFilter параметры выборки пользователей для административного API
+type UserFilter struct {
+	Role    string
+	Blocked *bool
+	Query   string
+	// Ключ последнего пользователя предыдущей страницы
+	AfterCreatedAt time.Time
+	AfterID        string
+	Limit          int
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// UserProfile публичный профиль пользователя
+type UserProfile struct {
+	ID        string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Username  string    `json:"username" example:"john_doe"`
+	Role      string    `json:"role" example:"user"`
+	CreatedAt time.Time `json:"created_at"`
+	// Karma отсутствует, если forum-service недоступен
+	Karma *Karma `json:"karma,omitempty"`
+}
+
+// Karma репутация пользователя на форуме
+type Karma struct {
+	Karma     int64 `json:"karma" example:"42"`
+	Upvotes   int64 `json:"upvotes" example:"50"`
+	Downvotes int64 `json:"downvotes" example:"8"`
+}
@@ -0,0 +1,52 @@
+package oidc
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const defaultScopes = "openid email profile"
+
+// ProviderConfig описывает настройки одного внешнего OIDC-провайдера
+type ProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// LoadProviderConfigs читает настройки провайдеров из окружения.
+// Список имен задается в OIDC_PROVIDERS через запятую, а параметры
+// каждого провайдера — в переменных OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID,
+// OIDC_<NAME>_CLIENT_SECRET, OIDC_<NAME>_REDIRECT_URL и OIDC_<NAME>_SCOPES.
+func LoadProviderConfigs() ([]ProviderConfig, error) {
+	var configs []ProviderConfig
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		cfg := ProviderConfig{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		if len(cfg.Scopes) == 0 {
+			cfg.Scopes = strings.Fields(defaultScopes)
+		}
+		if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+			return nil, fmt.Errorf("oidc provider %q: issuer, client id and redirect url are required", name)
+		}
+
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
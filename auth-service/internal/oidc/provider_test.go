@@ -0,0 +1,158 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/luckermt/forum-app/auth-service/internal/oidc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider — минимальный OIDC-провайдер для тестов
+type fakeProvider struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	clientID string
+
+	// данные последнего запроса авторизации
+	challenge string
+	nonce     string
+}
+
+func newFakeProvider(t *testing.T, clientID string) *fakeProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	fp := &fakeProvider{key: key, clientID: clientID}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 fp.server.URL,
+			"authorization_endpoint": fp.server.URL + "/authorize",
+			"token_endpoint":         fp.server.URL + "/token",
+			"jwks_uri":               fp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test-key",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("code") != "valid-code" || oidc.CodeChallenge(r.Form.Get("code_verifier")) != fp.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "opaque",
+			"id_token":     fp.idToken(t, fp.nonce),
+		})
+	})
+	fp.server = httptest.NewServer(mux)
+	t.Cleanup(fp.server.Close)
+	return fp
+}
+
+func (fp *fakeProvider) idToken(t *testing.T, nonce string) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":                fp.server.URL,
+		"aud":                fp.clientID,
+		"sub":                "external-user-1",
+		"email":              "user@example.com",
+		"email_verified":     true,
+		"preferred_username": "john_doe",
+		"nonce":              nonce,
+		"exp":                time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "test-key"
+	signed, err := token.SignedString(fp.key)
+	require.NoError(t, err)
+	return signed
+}
+
+// authorize имитирует редирект пользователя на страницу входа
+func (fp *fakeProvider) authorize(t *testing.T, authURL string) {
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "S256", u.Query().Get("code_challenge_method"))
+	fp.challenge = u.Query().Get("code_challenge")
+	fp.nonce = u.Query().Get("nonce")
+}
+
+func newTestProvider(t *testing.T, fp *fakeProvider) *oidc.Provider {
+	provider, err := oidc.NewProvider(context.Background(), oidc.ProviderConfig{
+		Name:        "corp",
+		Issuer:      fp.server.URL,
+		ClientID:    fp.clientID,
+		RedirectURL: "http://localhost:8080/oidc/corp/callback",
+		Scopes:      []string{"openid", "email"},
+	}, fp.server.Client())
+	require.NoError(t, err)
+	return provider
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	fp := newFakeProvider(t, "forum")
+	provider := newTestProvider(t, fp)
+	states := oidc.NewStateStore(time.Minute)
+
+	state, auth, err := states.Begin("corp")
+	require.NoError(t, err)
+	fp.authorize(t, provider.AuthCodeURL(state, auth))
+
+	stored, ok := states.Take(state)
+	require.True(t, ok)
+
+	claims, err := provider.Exchange(context.Background(), "valid-code", stored)
+	require.NoError(t, err)
+	assert.Equal(t, "external-user-1", claims.Subject)
+	assert.Equal(t, "user@example.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+
+	_, ok = states.Take(state)
+	assert.False(t, ok, "state must be single-use")
+}
+
+func TestProvider_RejectsWrongVerifier(t *testing.T) {
+	fp := newFakeProvider(t, "forum")
+	provider := newTestProvider(t, fp)
+	states := oidc.NewStateStore(time.Minute)
+
+	state, auth, err := states.Begin("corp")
+	require.NoError(t, err)
+	fp.authorize(t, provider.AuthCodeURL(state, auth))
+
+	auth.CodeVerifier = "tampered"
+	_, err = provider.Exchange(context.Background(), "valid-code", auth)
+	assert.Error(t, err)
+}
+
+func TestProvider_VerifyIDToken(t *testing.T) {
+	fp := newFakeProvider(t, "forum")
+	provider := newTestProvider(t, fp)
+
+	_, err := provider.VerifyIDToken(context.Background(), fp.idToken(t, "nonce-1"), "nonce-2")
+	assert.ErrorIs(t, err, oidc.ErrNonceMismatch)
+
+	other := newFakeProvider(t, "forum")
+	_, err = provider.VerifyIDToken(context.Background(), other.idToken(t, "nonce-1"), "nonce-1")
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+}
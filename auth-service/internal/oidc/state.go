@@ -0,0 +1,102 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"sync"
+	"time"
+)
+
+// AuthState хранит данные незавершенной авторизации между редиректами
+type AuthState struct {
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	ExpiresAt    time.Time
+}
+
+// StateStore хранит состояния авторизации в памяти процесса.
+// Каждое состояние можно использовать только один раз.
+type StateStore struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	states map[string]AuthState
+}
+
+// NewStateStore создает хранилище состояний с заданным временем жизни
+func NewStateStore(ttl time.Duration) *StateStore {
+	return &StateStore{
+		ttl:    ttl,
+		states: make(map[string]AuthState),
+	}
+}
+
+// Begin создает новое состояние для провайдера и возвращает его ключ
+func (s *StateStore) Begin(provider string) (string, AuthState, error) {
+	key, err := randomString(32)
+	if err != nil {
+		return "", AuthState{}, err
+	}
+	verifier, err := randomString(32)
+	if err != nil {
+		return "", AuthState{}, err
+	}
+	nonce, err := randomString(16)
+	if err != nil {
+		return "", AuthState{}, err
+	}
+
+	state := AuthState{
+		Provider:     provider,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(s.ttl),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evictExpired()
+	s.states[key] = state
+	return key, state, nil
+}
+
+// Take извлекает и удаляет состояние по ключу
+func (s *StateStore) Take(key string) (AuthState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[key]
+	if !ok {
+		return AuthState{}, false
+	}
+	delete(s.states, key)
+
+	if time.Now().After(state.ExpiresAt) {
+		return AuthState{}, false
+	}
+	return state, true
+}
+
+func (s *StateStore) evictExpired() {
+	now := time.Now()
+	for key, state := range s.states {
+		if now.After(state.ExpiresAt) {
+			delete(s.states, key)
+		}
+	}
+}
+
+// CodeChallenge вычисляет S256 challenge для PKCE
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
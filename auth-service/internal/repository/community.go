@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"database/sql"
+
+	authmodels "github.com/luckermt/forum-app/auth-service/internal/models"
+)
+
+func (r *PostgresRepository) CreateCommunity(community *authmodels.Community, owner *authmodels.CommunityMember) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO communities (id, slug, name, host, open, owner_id, created_at)
+              VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7)`
+	_, err = tx.Exec(query,
+		community.ID,
+		community.Slug,
+		community.Name,
+		community.Host,
+		community.Open,
+		community.OwnerID,
+		community.CreatedAt,
+	)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if err := addMember(tx, owner); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresRepository) GetCommunityBySlug(slug string) (*authmodels.Community, error) {
+	return r.getCommunity(`WHERE slug = $1`, slug)
+}
+
+func (r *PostgresRepository) GetCommunityByHost(host string) (*authmodels.Community, error) {
+	return r.getCommunity(`WHERE host = $1`, host)
+}
+
+func (r *PostgresRepository) GetCommunityByID(communityID string) (*authmodels.Community, error) {
+	return r.getCommunity(`WHERE id = $1`, communityID)
+}
+
+func (r *PostgresRepository) getCommunity(where string, arg interface{}) (*authmodels.Community, error) {
+	query := `SELECT id, slug, name, COALESCE(host, ''), open, COALESCE(owner_id, ''), created_at
+              FROM communities ` + where
+	row := r.db.QueryRow(query, arg)
+
+	var community authmodels.Community
+	err := row.Scan(
+		&community.ID,
+		&community.Slug,
+		&community.Name,
+		&community.Host,
+		&community.Open,
+		&community.OwnerID,
+		&community.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrCommunityNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &community, nil
+}
+
+func (r *PostgresRepository) ListUserCommunities(userID string) ([]*authmodels.CommunityMember, error) {
+	query := `SELECT community_id, user_id, role, blocked, joined_at
+              FROM community_members WHERE user_id = $1
+              ORDER BY joined_at`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*authmodels.CommunityMember
+	for rows.Next() {
+		var member authmodels.CommunityMember
+		err := rows.Scan(
+			&member.CommunityID,
+			&member.UserID,
+			&member.Role,
+			&member.Blocked,
+			&member.JoinedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, &member)
+	}
+
+	return members, rows.Err()
+}
+
+func (r *PostgresRepository) AddCommunityMember(member *authmodels.CommunityMember) error {
+	return addMember(r.db, member)
+}
+
+func (r *PostgresRepository) GetCommunityMember(communityID, userID string) (*authmodels.CommunityMember, error) {
+	query := `SELECT community_id, user_id, role, blocked, joined_at
+              FROM community_members WHERE community_id = $1 AND user_id = $2`
+	row := r.db.QueryRow(query, communityID, userID)
+
+	var member authmodels.CommunityMember
+	err := row.Scan(
+		&member.CommunityID,
+		&member.UserID,
+		&member.Role,
+		&member.Blocked,
+		&member.JoinedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrMemberNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func (r *PostgresRepository) SetCommunityMemberRole(communityID, userID, role string) error {
+	query := `UPDATE community_members SET role = $3 WHERE community_id = $1 AND user_id = $2`
+	res, err := r.db.Exec(query, communityID, userID, role)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func addMember(db execer, member *authmodels.CommunityMember) error {
+	query := `INSERT INTO community_members (community_id, user_id, role, blocked, joined_at)
+              VALUES ($1, $2, $3, $4, $5)`
+	_, err := db.Exec(query,
+		member.CommunityID,
+		member.UserID,
+		member.Role,
+		member.Blocked,
+		member.JoinedAt,
+	)
+	return translateError(err)
+}
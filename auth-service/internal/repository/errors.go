@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrEmailExists    = errors.New("email already exists")
+	ErrUsernameExists = errors.New("username already exists")
+	ErrAlreadyExists  = errors.New("already exists")
+	ErrUserNotFound   = errors.New("user not found")
+	ErrClientNotFound = errors.New("oauth client not found")
+	ErrCodeNotFound   = errors.New("authorization code not found")
+	ErrTokenNotFound  = errors.New("access token not found")
+
+	ErrCommunityNotFound = errors.New("community not found")
+	ErrMemberNotFound    = errors.New("community member not found")
+	ErrSlugExists        = errors.New("community slug already exists")
+	ErrHostExists        = errors.New("community host already exists")
+)
+
+// Код ошибки Postgres unique_violation
+const uniqueViolation = "23505"
+
+// translateError превращает нарушения уникальности в типизированные ошибки
+func translateError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != uniqueViolation {
+		return err
+	}
+
+	switch pqErr.Constraint {
+	case "users_email_key":
+		return ErrEmailExists
+	case "users_username_key":
+		return ErrUsernameExists
+	case "communities_slug_key":
+		return ErrSlugExists
+	case "communities_host_key":
+		return ErrHostExists
+	default:
+		return ErrAlreadyExists
+	}
+}
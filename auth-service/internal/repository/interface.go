@@ -1,10 +1,45 @@
 package repository
 
-import "github.com/luckermt/forum-app/shared/pkg/models"
+import (
+	"time"
+
+	authmodels "github.com/luckermt/forum-app/auth-service/internal/models"
+	"github.com/luckermt/forum-app/shared/pkg/models"
+)
 
 type Repository interface {
 	CreateUser(user *models.User) error
 	GetUserByEmail(email string) (*models.User, error)
 	GetUserByID(userID string) (*models.User, error) // Добавлен отсутствующий метод
 	BlockUser(userID string) error
+	UnblockUser(userID string) error
+	SetUserRole(userID, role string) error
+	ListUsers(filter authmodels.UserFilter) ([]*models.User, error)
+	GetUsersByUsernames(usernames []string) ([]*models.User, error)
+	RevokeUserTokens(userID string, revokedAt time.Time) error
+	GetTokensRevokedAt(userID string) (time.Time, error)
+
+	// Внешние учетные записи (OIDC)
+	GetUserByIdentity(provider, subject string) (*models.User, error)
+	LinkIdentity(userID, provider, subject, email string) error
+
+	// OAuth2 клиенты и токены
+	CreateOAuthClient(client *authmodels.OAuthClient) error
+	GetOAuthClient(clientID string) (*authmodels.OAuthClient, error)
+	CreateAuthorizationCode(code *authmodels.OAuthAuthorizationCode) error
+	ConsumeAuthorizationCode(codeHash string) (*authmodels.OAuthAuthorizationCode, error)
+	CreateAccessToken(token *authmodels.OAuthAccessToken) error
+	GetAccessToken(tokenID string) (*authmodels.OAuthAccessToken, error)
+	RevokeAccessToken(clientID, tokenID string) error
+	RevokeClientTokens(clientID string) error
+
+	// Сообщества
+	CreateCommunity(community *authmodels.Community, owner *authmodels.CommunityMember) error
+	GetCommunityBySlug(slug string) (*authmodels.Community, error)
+	GetCommunityByHost(host string) (*authmodels.Community, error)
+	GetCommunityByID(communityID string) (*authmodels.Community, error)
+	ListUserCommunities(userID string) ([]*authmodels.CommunityMember, error)
+	AddCommunityMember(member *authmodels.CommunityMember) error
+	GetCommunityMember(communityID, userID string) (*authmodels.CommunityMember, error)
+	SetCommunityMemberRole(communityID, userID, role string) error
 }
@@ -1,6 +1,9 @@
 package mocks
 
 import (
+	"time"
+
+	authmodels "github.com/luckermt/forum-app/auth-service/internal/models"
 	"github.com/luckermt/forum-app/shared/pkg/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -28,3 +31,156 @@ func (m *Repository) BlockUser(userID string) error {
 	args := m.Called(userID)
 	return args.Error(0)
 }
+
+func (m *Repository) UnblockUser(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *Repository) SetUserRole(userID, role string) error {
+	args := m.Called(userID, role)
+	return args.Error(0)
+}
+
+func (m *Repository) GetUsersByUsernames(usernames []string) ([]*models.User, error) {
+	args := m.Called(usernames)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
+func (m *Repository) ListUsers(filter authmodels.UserFilter) ([]*models.User, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
+func (m *Repository) RevokeUserTokens(userID string, revokedAt time.Time) error {
+	args := m.Called(userID, revokedAt)
+	return args.Error(0)
+}
+
+func (m *Repository) GetTokensRevokedAt(userID string) (time.Time, error) {
+	args := m.Called(userID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *Repository) GetUserByIdentity(provider, subject string) (*models.User, error) {
+	args := m.Called(provider, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *Repository) LinkIdentity(userID, provider, subject, email string) error {
+	args := m.Called(userID, provider, subject, email)
+	return args.Error(0)
+}
+
+func (m *Repository) CreateOAuthClient(client *authmodels.OAuthClient) error {
+	args := m.Called(client)
+	return args.Error(0)
+}
+
+func (m *Repository) GetOAuthClient(clientID string) (*authmodels.OAuthClient, error) {
+	args := m.Called(clientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authmodels.OAuthClient), args.Error(1)
+}
+
+func (m *Repository) CreateAuthorizationCode(code *authmodels.OAuthAuthorizationCode) error {
+	args := m.Called(code)
+	return args.Error(0)
+}
+
+func (m *Repository) ConsumeAuthorizationCode(codeHash string) (*authmodels.OAuthAuthorizationCode, error) {
+	args := m.Called(codeHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authmodels.OAuthAuthorizationCode), args.Error(1)
+}
+
+func (m *Repository) CreateAccessToken(token *authmodels.OAuthAccessToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *Repository) GetAccessToken(tokenID string) (*authmodels.OAuthAccessToken, error) {
+	args := m.Called(tokenID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authmodels.OAuthAccessToken), args.Error(1)
+}
+
+func (m *Repository) RevokeAccessToken(clientID, tokenID string) error {
+	args := m.Called(clientID, tokenID)
+	return args.Error(0)
+}
+
+func (m *Repository) RevokeClientTokens(clientID string) error {
+	args := m.Called(clientID)
+	return args.Error(0)
+}
+
+func (m *Repository) CreateCommunity(community *authmodels.Community, owner *authmodels.CommunityMember) error {
+	args := m.Called(community, owner)
+	return args.Error(0)
+}
+
+func (m *Repository) GetCommunityBySlug(slug string) (*authmodels.Community, error) {
+	args := m.Called(slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authmodels.Community), args.Error(1)
+}
+
+func (m *Repository) GetCommunityByHost(host string) (*authmodels.Community, error) {
+	args := m.Called(host)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authmodels.Community), args.Error(1)
+}
+
+func (m *Repository) GetCommunityByID(communityID string) (*authmodels.Community, error) {
+	args := m.Called(communityID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authmodels.Community), args.Error(1)
+}
+
+func (m *Repository) ListUserCommunities(userID string) ([]*authmodels.CommunityMember, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*authmodels.CommunityMember), args.Error(1)
+}
+
+func (m *Repository) AddCommunityMember(member *authmodels.CommunityMember) error {
+	args := m.Called(member)
+	return args.Error(0)
+}
+
+func (m *Repository) GetCommunityMember(communityID, userID string) (*authmodels.CommunityMember, error) {
+	args := m.Called(communityID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authmodels.CommunityMember), args.Error(1)
+}
+
+func (m *Repository) SetCommunityMemberRole(communityID, userID, role string) error {
+	args := m.Called(communityID, userID, role)
+	return args.Error(0)
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+	authmodels "github.com/luckermt/forum-app/auth-service/internal/models"
+)
+
+func (r *PostgresRepository) CreateOAuthClient(client *authmodels.OAuthClient) error {
+	query := `INSERT INTO oauth_clients (id, secret_hash, name, redirect_uris, scopes, owner_id, public, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := r.db.Exec(query,
+		client.ID,
+		client.SecretHash,
+		client.Name,
+		pq.Array(client.RedirectURIs),
+		pq.Array(client.Scopes),
+		client.OwnerID,
+		client.Public,
+		client.CreatedAt,
+	)
+	return translateError(err)
+}
+
+func (r *PostgresRepository) GetOAuthClient(clientID string) (*authmodels.OAuthClient, error) {
+	query := `SELECT id, secret_hash, name, redirect_uris, scopes, owner_id, public, created_at
+              FROM oauth_clients WHERE id = $1`
+	row := r.db.QueryRow(query, clientID)
+
+	var client authmodels.OAuthClient
+	err := row.Scan(
+		&client.ID,
+		&client.SecretHash,
+		&client.Name,
+		pq.Array(&client.RedirectURIs),
+		pq.Array(&client.Scopes),
+		&client.OwnerID,
+		&client.Public,
+		&client.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrClientNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &client, nil
+}
+
+func (r *PostgresRepository) CreateAuthorizationCode(code *authmodels.OAuthAuthorizationCode) error {
+	query := `INSERT INTO oauth_authorization_codes (code_hash, client_id, user_id, redirect_uri, scopes, code_challenge, expires_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := r.db.Exec(query,
+		code.CodeHash,
+		code.ClientID,
+		code.UserID,
+		code.RedirectURI,
+		pq.Array(code.Scopes),
+		code.CodeChallenge,
+		code.ExpiresAt,
+	)
+	return err
+}
+
+// ConsumeAuthorizationCode удаляет код и возвращает его данные,
+// поэтому повторный обмен того же кода невозможен
+func (r *PostgresRepository) ConsumeAuthorizationCode(codeHash string) (*authmodels.OAuthAuthorizationCode, error) {
+	query := `DELETE FROM oauth_authorization_codes WHERE code_hash = $1
+              RETURNING code_hash, client_id, user_id, redirect_uri, scopes, code_challenge, expires_at`
+	row := r.db.QueryRow(query, codeHash)
+
+	var code authmodels.OAuthAuthorizationCode
+	err := row.Scan(
+		&code.CodeHash,
+		&code.ClientID,
+		&code.UserID,
+		&code.RedirectURI,
+		pq.Array(&code.Scopes),
+		&code.CodeChallenge,
+		&code.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrCodeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &code, nil
+}
+
+func (r *PostgresRepository) CreateAccessToken(token *authmodels.OAuthAccessToken) error {
+	query := `INSERT INTO oauth_access_tokens (id, client_id, user_id, scopes, expires_at, revoked, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := r.db.Exec(query,
+		token.ID,
+		token.ClientID,
+		token.UserID,
+		pq.Array(token.Scopes),
+		token.ExpiresAt,
+		token.Revoked,
+		token.CreatedAt,
+	)
+	return err
+}
+
+func (r *PostgresRepository) GetAccessToken(tokenID string) (*authmodels.OAuthAccessToken, error) {
+	query := `SELECT id, client_id, user_id, scopes, expires_at, revoked, created_at
+              FROM oauth_access_tokens WHERE id = $1`
+	row := r.db.QueryRow(query, tokenID)
+
+	var token authmodels.OAuthAccessToken
+	err := row.Scan(
+		&token.ID,
+		&token.ClientID,
+		&token.UserID,
+		pq.Array(&token.Scopes),
+		&token.ExpiresAt,
+		&token.Revoked,
+		&token.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *PostgresRepository) RevokeAccessToken(clientID, tokenID string) error {
+	query := `UPDATE oauth_access_tokens SET revoked = true WHERE id = $1 AND client_id = $2`
+	_, err := r.db.Exec(query, tokenID, clientID)
+	return err
+}
+
+func (r *PostgresRepository) RevokeClientTokens(clientID string) error {
+	query := `UPDATE oauth_access_tokens SET revoked = true WHERE client_id = $1 AND revoked = false`
+	_, err := r.db.Exec(query, clientID)
+	return err
+}
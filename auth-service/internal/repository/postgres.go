@@ -3,8 +3,11 @@ package repository
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	authmodels "github.com/luckermt/forum-app/auth-service/internal/models"
 	"github.com/luckermt/forum-app/shared/pkg/config"
 	"github.com/luckermt/forum-app/shared/pkg/logger"
 	"github.com/luckermt/forum-app/shared/pkg/models"
@@ -45,7 +48,7 @@ func (r *PostgresRepository) CreateUser(user *models.User) error {
 		user.CreatedAt,
 		user.Blocked,
 	)
-	return err
+	return translateError(err)
 }
 
 func (r *PostgresRepository) GetUserByEmail(email string) (*models.User, error) {
@@ -63,6 +66,9 @@ func (r *PostgresRepository) GetUserByEmail(email string) (*models.User, error)
 		&user.CreatedAt,
 		&user.Blocked,
 	)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -85,6 +91,9 @@ func (r *PostgresRepository) GetUserByID(userID string) (*models.User, error) {
 		&user.CreatedAt,
 		&user.Blocked,
 	)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -94,6 +103,201 @@ func (r *PostgresRepository) GetUserByID(userID string) (*models.User, error) {
 
 func (r *PostgresRepository) BlockUser(userID string) error {
 	query := `UPDATE users SET blocked = true WHERE id = $1`
-	_, err := r.db.Exec(query, userID)
+	return r.execUserUpdate(query, userID)
+}
+
+func (r *PostgresRepository) UnblockUser(userID string) error {
+	query := `UPDATE users SET blocked = false WHERE id = $1`
+	return r.execUserUpdate(query, userID)
+}
+
+func (r *PostgresRepository) SetUserRole(userID, role string) error {
+	query := `UPDATE users SET role = $2 WHERE id = $1`
+	return r.execUserUpdate(query, userID, role)
+}
+
+// RevokeUserTokens делает недействительными все токены пользователя,
+// выпущенные до revokedAt, включая токены сторонних приложений
+func (r *PostgresRepository) RevokeUserTokens(userID string, revokedAt time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE users SET tokens_revoked_at = $2 WHERE id = $1`, userID, revokedAt)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrUserNotFound
+	}
+
+	if _, err := tx.Exec(`UPDATE oauth_access_tokens SET revoked = true WHERE user_id = $1 AND revoked = false`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresRepository) GetTokensRevokedAt(userID string) (time.Time, error) {
+	query := `SELECT tokens_revoked_at FROM users WHERE id = $1`
+	var revokedAt sql.NullTime
+	err := r.db.QueryRow(query, userID).Scan(&revokedAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, ErrUserNotFound
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return revokedAt.Time, nil
+}
+
+// ListUsers возвращает пользователей в порядке (created_at, id) начиная
+// после ключа из фильтра
+func (r *PostgresRepository) ListUsers(filter authmodels.UserFilter) ([]*models.User, error) {
+	var conditions []string
+	var args []interface{}
+	addArg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Role != "" {
+		conditions = append(conditions, "role = "+addArg(filter.Role))
+	}
+	if filter.Blocked != nil {
+		conditions = append(conditions, "blocked = "+addArg(*filter.Blocked))
+	}
+	if filter.Query != "" {
+		pattern := addArg("%" + escapeLike(filter.Query) + "%")
+		conditions = append(conditions, fmt.Sprintf("(username ILIKE %s OR email ILIKE %s)", pattern, pattern))
+	}
+	if filter.AfterID != "" {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) > (%s, %s)",
+			addArg(filter.AfterCreatedAt), addArg(filter.AfterID)))
+	}
+
+	query := `SELECT id, username, email, password, role, created_at, blocked FROM users`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at, id LIMIT " + addArg(filter.Limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.Email,
+			&user.Password,
+			&user.Role,
+			&user.CreatedAt,
+			&user.Blocked,
+		)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+
+	return users, rows.Err()
+}
+
+// GetUsersByUsernames находит пользователей по именам без учета регистра
+func (r *PostgresRepository) GetUsersByUsernames(usernames []string) ([]*models.User, error) {
+	query := `SELECT id, username, email, password, role, created_at, blocked
+	          FROM users WHERE LOWER(username) = ANY($1)`
+	lower := make([]string, len(usernames))
+	for i, username := range usernames {
+		lower[i] = strings.ToLower(username)
+	}
+
+	rows, err := r.db.Query(query, pq.Array(lower))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.Email,
+			&user.Password,
+			&user.Role,
+			&user.CreatedAt,
+			&user.Blocked,
+		)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *PostgresRepository) execUserUpdate(query string, args ...interface{}) error {
+	res, err := r.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+
+func (r *PostgresRepository) GetUserByIdentity(provider, subject string) (*models.User, error) {
+	query := `SELECT u.id, u.username, u.email, u.password, u.role, u.created_at, u.blocked
+              FROM users u
+              JOIN user_identities i ON i.user_id = u.id
+              WHERE i.provider = $1 AND i.subject = $2`
+	row := r.db.QueryRow(query, provider, subject)
+
+	var user models.User
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.Password,
+		&user.Role,
+		&user.CreatedAt,
+		&user.Blocked,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *PostgresRepository) LinkIdentity(userID, provider, subject, email string) error {
+	query := `INSERT INTO user_identities (provider, subject, user_id, email, created_at)
+              VALUES ($1, $2, $3, $4, $5)
+              ON CONFLICT (provider, subject) DO NOTHING`
+	_, err := r.db.Exec(query, provider, subject, userID, email, time.Now())
 	return err
 }
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	authmodels "github.com/luckermt/forum-app/auth-service/internal/models"
+	"github.com/luckermt/forum-app/auth-service/internal/repository"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/models"
+	"go.uber.org/zap"
+)
+
+// Роли пользователей
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+const (
+	defaultUsersPageSize = 50
+	maxUsersPageSize     = 200
+)
+
+var (
+	ErrUserNotFound     = repository.ErrUserNotFound
+	ErrInvalidRole      = errors.New("invalid role")
+	ErrInvalidPageToken = errors.New("invalid page token")
+)
+
+// AdminService определяет контракт административных операций над пользователями
+type AdminService interface {
+	BlockUser(userID string) (*models.User, error)
+	UnblockUser(userID string) (*models.User, error)
+	SetRole(userID, role string) (*models.User, error)
+	ListUsers(req ListUsersRequest) ([]*models.User, string, error)
+	RevokeUserTokens(userID string) (time.Time, error)
+}
+
+// ListUsersRequest параметры постраничной выборки пользователей
+type ListUsersRequest struct {
+	PageSize  int
+	PageToken string
+	Role      string
+	Blocked   *bool
+	Query     string
+}
+
+func NewAdminService(repo repository.Repository, jwtSecret string) AdminService {
+	if logger.Log == nil {
+		if err := logger.Init(); err != nil {
+			panic("Failed to initialize logger")
+		}
+	}
+
+	return &authServiceImpl{
+		repo:      repo,
+		jwtSecret: jwtSecret,
+	}
+}
+
+func (s *authServiceImpl) BlockUser(userID string) (*models.User, error) {
+	if err := s.repo.BlockUser(userID); err != nil {
+		return nil, err
+	}
+	logger.Log.Info("User blocked", zap.String("user_id", userID))
+	return s.repo.GetUserByID(userID)
+}
+
+func (s *authServiceImpl) UnblockUser(userID string) (*models.User, error) {
+	if err := s.repo.UnblockUser(userID); err != nil {
+		return nil, err
+	}
+	logger.Log.Info("User unblocked", zap.String("user_id", userID))
+	return s.repo.GetUserByID(userID)
+}
+
+func (s *authServiceImpl) SetRole(userID, role string) (*models.User, error) {
+	switch role {
+	case RoleUser, RoleModerator, RoleAdmin:
+	default:
+		return nil, ErrInvalidRole
+	}
+
+	if err := s.repo.SetUserRole(userID, role); err != nil {
+		return nil, err
+	}
+	logger.Log.Info("User role changed",
+		zap.String("user_id", userID),
+		zap.String("role", role))
+	return s.repo.GetUserByID(userID)
+}
+
+// ListUsers возвращает страницу пользователей и курсор следующей страницы.
+// Пустой курсор означает, что страниц больше нет.
+func (s *authServiceImpl) ListUsers(req ListUsersRequest) ([]*models.User, string, error) {
+	limit := req.PageSize
+	if limit <= 0 {
+		limit = defaultUsersPageSize
+	}
+	if limit > maxUsersPageSize {
+		limit = maxUsersPageSize
+	}
+
+	filter := authmodels.UserFilter{
+		Role:    req.Role,
+		Blocked: req.Blocked,
+		Query:   req.Query,
+		// Запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+		Limit: limit + 1,
+	}
+	if req.PageToken != "" {
+		createdAt, id, err := decodeUserCursor(req.PageToken)
+		if err != nil {
+			return nil, "", ErrInvalidPageToken
+		}
+		filter.AfterCreatedAt = createdAt
+		filter.AfterID = id
+	}
+
+	users, err := s.repo.ListUsers(filter)
+	if err != nil {
+		logger.Log.Error("Failed to list users", zap.Error(err))
+		return nil, "", err
+	}
+
+	var next string
+	if len(users) > limit {
+		users = users[:limit]
+		last := users[len(users)-1]
+		next = encodeUserCursor(last.CreatedAt, last.ID)
+	}
+	return users, next, nil
+}
+
+// RevokeUserTokens отзывает все выданные пользователю токены
+func (s *authServiceImpl) RevokeUserTokens(userID string) (time.Time, error) {
+	revokedAt := time.Now().UTC().Truncate(time.Microsecond)
+	if err := s.repo.RevokeUserTokens(userID, revokedAt); err != nil {
+		return time.Time{}, err
+	}
+	logger.Log.Info("User tokens revoked", zap.String("user_id", userID))
+	return revokedAt, nil
+}
+
+func encodeUserCursor(createdAt time.Time, id string) string {
+	raw := strconv.FormatInt(createdAt.UnixNano(), 10) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeUserCursor(token string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	nanos, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return time.Time{}, "", ErrInvalidPageToken
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	// created_at хранится в TIMESTAMP без зоны, поэтому время курсора сравнивается в UTC
+	return time.Unix(0, n).UTC(), id, nil
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserCursor_RoundTripInUTC(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("UTC+3", 3*60*60)
+	defer func() { time.Local = local }()
+
+	createdAt := time.Date(2024, 3, 15, 10, 30, 0, 123456000, time.UTC)
+
+	decoded, id, err := decodeUserCursor(encodeUserCursor(createdAt, "user-1"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", id)
+	assert.Equal(t, createdAt, decoded)
+	assert.Equal(t, time.UTC, decoded.Location())
+}
+
+func TestUserCursor_Invalid(t *testing.T) {
+	_, _, err := decodeUserCursor("bm8tc2VwYXJhdG9y")
+
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+}
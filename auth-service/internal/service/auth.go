@@ -3,6 +3,9 @@ package service
 import (
 	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -17,8 +20,29 @@ import (
 type AuthService interface {
 	Register(user *models.User) error
 	Login(email, password string) (string, error)
-	ValidateToken(token string) (string, error)
+	ValidateToken(token string) (*TokenInfo, error)
 	GetUserRole(userID string) (string, bool, error)
+	LoginWithIdentity(identity ExternalIdentity) (string, error)
+}
+
+// TokenInfo результат проверки токена
+type TokenInfo struct {
+	UserID string
+	// ClientID заполнен только для токенов сторонних OAuth приложений
+	ClientID string
+	// Scopes области доступа, выданные приложению
+	Scopes []string
+}
+
+// ThirdParty сообщает, что токен выдан стороннему приложению
+func (t *TokenInfo) ThirdParty() bool {
+	return t.ClientID != ""
+}
+
+// HasScope разрешает собственным токенам форума любые действия,
+// а токенам приложений только выданные пользователем области доступа
+func (t *TokenInfo) HasScope(scope string) bool {
+	return !t.ThirdParty() || slices.Contains(t.Scopes, scope)
 }
 
 type authServiceImpl struct {
@@ -61,24 +85,38 @@ func (s *authServiceImpl) Register(user *models.User) error {
 }
 
 func (s *authServiceImpl) Login(email, password string) (string, error) {
+	user, err := s.authenticate(email, password)
+	if err != nil {
+		return "", err
+	}
 
+	logger.Log.Info("Successful login", zap.String("email", email))
+	return s.generateJWTToken(user)
+}
+
+// authenticate проверяет email и пароль и возвращает незаблокированного пользователя
+func (s *authServiceImpl) authenticate(email, password string) (*models.User, error) {
 	logger.Log.Debug("Login attempt",
 		zap.String("email", email),
 		zap.Time("timestamp", time.Now()),
 	)
 
 	user, err := s.repo.GetUserByEmail(email)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		logger.Log.Warn("User not found", zap.String("email", email))
+		return nil, ErrInvalidCredentials
+	}
 	if err != nil {
 		logger.Log.Error("Database error",
 			zap.Error(err),
 			zap.String("email", email),
 		)
-		return "", fmt.Errorf("internal server error")
+		return nil, fmt.Errorf("internal server error")
 	}
 
 	if user == nil {
 		logger.Log.Warn("User not found", zap.String("email", email))
-		return "", ErrInvalidCredentials
+		return nil, ErrInvalidCredentials
 	}
 
 	logger.Log.Debug("User data from DB",
@@ -89,7 +127,7 @@ func (s *authServiceImpl) Login(email, password string) (string, error) {
 
 	if user.Blocked {
 		logger.Log.Warn("Blocked user attempt", zap.String("email", email))
-		return "", ErrUserBlocked
+		return nil, ErrUserBlocked
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
@@ -99,11 +137,10 @@ func (s *authServiceImpl) Login(email, password string) (string, error) {
 			zap.String("db_pwd_prefix", user.Password[:10]),
 			zap.Error(err),
 		)
-		return "", ErrInvalidCredentials
+		return nil, ErrInvalidCredentials
 	}
 
-	logger.Log.Info("Successful login", zap.String("email", email))
-	return s.generateJWTToken(user)
+	return user, nil
 }
 
 func (s *authServiceImpl) generateJWTToken(user *models.User) (string, error) {
@@ -111,13 +148,14 @@ func (s *authServiceImpl) generateJWTToken(user *models.User) (string, error) {
 		"user_id": user.ID,
 		"email":   user.Email,
 		"role":    user.Role,
+		"iat":     issuedAtClaim(time.Now()),
 		"exp":     time.Now().Add(time.Hour * 24).Unix(),
 	})
 
 	return token.SignedString([]byte(s.jwtSecret))
 }
 
-func (s *authServiceImpl) ValidateToken(tokenString string) (string, error) {
+func (s *authServiceImpl) ValidateToken(tokenString string) (*TokenInfo, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -127,11 +165,60 @@ func (s *authServiceImpl) ValidateToken(tokenString string) (string, error) {
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 		if userID, ok := claims["user_id"].(string); ok {
-			return userID, nil
+			info := &TokenInfo{UserID: userID}
+			// Токены сторонних приложений дополнительно проверяются на отзыв
+			if clientID, ok := claims["client_id"].(string); ok {
+				if err := s.checkAccessToken(clientID, claims); err != nil {
+					return nil, err
+				}
+				scope, _ := claims["scope"].(string)
+				info.ClientID = clientID
+				info.Scopes = strings.Fields(scope)
+			}
+			if err := s.checkUserRevocation(userID, claims); err != nil {
+				return nil, err
+			}
+			return info, nil
 		}
 	}
 
-	return "", err
+	if err == nil {
+		err = ErrInvalidToken
+	}
+	return nil, err
+}
+
+// checkUserRevocation отклоняет токены, выпущенные до последнего отзыва
+// всех токенов пользователя
+func (s *authServiceImpl) checkUserRevocation(userID string, claims jwt.MapClaims) error {
+	revokedAt, err := s.repo.GetTokensRevokedAt(userID)
+	if err != nil {
+		return err
+	}
+	if revokedAt.IsZero() {
+		return nil
+	}
+
+	issuedAt, ok := claimIssuedAt(claims)
+	if !ok || issuedAt.Before(revokedAt) {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// issuedAtClaim записывает время выпуска с точностью до микросекунд, как у
+// tokens_revoked_at: токен, полученный сразу после отзыва, остается действительным
+func issuedAtClaim(t time.Time) float64 {
+	return float64(t.UnixMicro()) / 1e6
+}
+
+// claimIssuedAt читает iat без округления до секунд, которое делает jwt.MapClaims
+func claimIssuedAt(claims jwt.MapClaims) (time.Time, bool) {
+	iat, ok := claims["iat"].(float64)
+	if !ok || iat <= 0 {
+		return time.Time{}, false
+	}
+	return time.UnixMicro(int64(math.Round(iat * 1e6))), true
 }
 
 func (s *authServiceImpl) GetUserRole(userID string) (string, bool, error) {
@@ -152,8 +239,11 @@ func maskPassword(pwd string) string {
 }
 
 var (
-	ErrEmailExists        = errors.New("email already exists")
-	ErrUsernameExists     = errors.New("username already exists")
+	ErrEmailExists        = repository.ErrEmailExists
+	ErrUsernameExists     = repository.ErrUsernameExists
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrUserBlocked        = errors.New("user is blocked")
+	ErrEmailNotVerified   = errors.New("email is not verified")
+	ErrTokenRevoked       = errors.New("token has been revoked")
+	ErrInvalidToken       = errors.New("invalid token")
 )
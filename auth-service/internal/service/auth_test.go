@@ -2,7 +2,9 @@ package service_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/luckermt/forum-app/auth-service/internal/repository"
 	"github.com/luckermt/forum-app/auth-service/internal/repository/mocks"
 	"github.com/luckermt/forum-app/auth-service/internal/service"
 	"github.com/luckermt/forum-app/shared/pkg/logger"
@@ -67,3 +69,85 @@ func TestAuthService_Login(t *testing.T) {
 	assert.NotEmpty(t, token)
 	repo.AssertExpectations(t)
 }
+
+func TestAuthService_LoginWithIdentity_LinksByVerifiedEmail(t *testing.T) {
+
+	if err := logger.Init(); err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Log.Sync()
+
+	repo := new(mocks.Repository)
+	authSvc := service.NewAuthService(repo, "test-secret-key")
+
+	existing := &models.User{ID: "user-1", Email: "test@example.com", Role: "user"}
+	repo.On("GetUserByIdentity", "corp", "sub-1").Return(nil, repository.ErrUserNotFound)
+	repo.On("GetUserByEmail", "test@example.com").Return(existing, nil)
+	repo.On("LinkIdentity", "user-1", "corp", "sub-1", "test@example.com").Return(nil)
+
+	token, err := authSvc.LoginWithIdentity(service.ExternalIdentity{
+		Provider:      "corp",
+		Subject:       "sub-1",
+		Email:         "test@example.com",
+		EmailVerified: true,
+	})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	repo.AssertExpectations(t)
+}
+
+func TestAuthService_LoginWithIdentity_RequiresVerifiedEmail(t *testing.T) {
+
+	if err := logger.Init(); err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Log.Sync()
+
+	repo := new(mocks.Repository)
+	authSvc := service.NewAuthService(repo, "test-secret-key")
+
+	repo.On("GetUserByIdentity", "corp", "sub-1").Return(nil, repository.ErrUserNotFound)
+
+	_, err := authSvc.LoginWithIdentity(service.ExternalIdentity{
+		Provider: "corp",
+		Subject:  "sub-1",
+		Email:    "test@example.com",
+	})
+
+	assert.ErrorIs(t, err, service.ErrEmailNotVerified)
+	repo.AssertExpectations(t)
+}
+
+func TestAuthService_ValidateToken_UserRevocation(t *testing.T) {
+	if err := logger.Init(); err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Log.Sync()
+
+	repo := new(mocks.Repository)
+	authSvc := service.NewAuthService(repo, "test-secret-key")
+
+	email := "test@example.com"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("correct_password"), bcrypt.MinCost)
+	repo.On("GetUserByEmail", email).Return(&models.User{
+		ID:       "user-1",
+		Email:    email,
+		Password: string(hashedPassword),
+	}, nil)
+
+	// Токен, полученный в ту же секунду, но после отзыва, остается действительным
+	revokedAt := time.Now().UTC()
+	time.Sleep(2 * time.Millisecond)
+	token, err := authSvc.Login(email, "correct_password")
+	assert.NoError(t, err)
+
+	repo.On("GetTokensRevokedAt", "user-1").Return(revokedAt, nil).Once()
+	info, err := authSvc.ValidateToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", info.UserID)
+
+	repo.On("GetTokensRevokedAt", "user-1").Return(time.Now().UTC(), nil).Once()
+	_, err = authSvc.ValidateToken(token)
+	assert.ErrorIs(t, err, service.ErrTokenRevoked)
+}
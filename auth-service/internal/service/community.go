@@ -0,0 +1,210 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	authmodels "github.com/luckermt/forum-app/auth-service/internal/models"
+	"github.com/luckermt/forum-app/auth-service/internal/repository"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+var (
+	ErrCommunityNotFound = repository.ErrCommunityNotFound
+	ErrSlugExists        = repository.ErrSlugExists
+	ErrHostExists        = repository.ErrHostExists
+	ErrInvalidSlug       = errors.New("invalid community slug")
+	ErrAlreadyMember     = errors.New("already a member of the community")
+	ErrNotMember         = errors.New("not a member of the community")
+	ErrForbidden         = errors.New("forbidden")
+)
+
+// CommunityService определяет контракт работы с сообществами и ролями в них
+type CommunityService interface {
+	CreateCommunity(ownerID string, req authmodels.CommunityRequest) (*authmodels.Community, error)
+	GetCommunity(slug string) (*authmodels.Community, error)
+	JoinCommunity(slug, userID string) (*authmodels.CommunityMember, error)
+	ListUserCommunities(userID string) ([]*authmodels.CommunityMember, error)
+	SetMemberRole(actorID, slug, userID, role string) error
+	ResolveCommunity(host, slug string) (*authmodels.Community, error)
+	GetMemberRole(communityID, userID string) (string, bool, error)
+}
+
+func NewCommunityService(repo repository.Repository, jwtSecret string) CommunityService {
+	if logger.Log == nil {
+		if err := logger.Init(); err != nil {
+			panic("Failed to initialize logger")
+		}
+	}
+
+	return &authServiceImpl{
+		repo:      repo,
+		jwtSecret: jwtSecret,
+	}
+}
+
+// CreateCommunity создает сообщество, создатель становится его администратором
+func (s *authServiceImpl) CreateCommunity(ownerID string, req authmodels.CommunityRequest) (*authmodels.Community, error) {
+	slug := strings.ToLower(req.Slug)
+	if !slugPattern.MatchString(slug) {
+		return nil, ErrInvalidSlug
+	}
+
+	now := time.Now()
+	community := &authmodels.Community{
+		ID:        uuid.New().String(),
+		Slug:      slug,
+		Name:      req.Name,
+		Host:      strings.ToLower(req.Host),
+		Open:      req.Open,
+		OwnerID:   ownerID,
+		CreatedAt: now,
+	}
+	owner := &authmodels.CommunityMember{
+		CommunityID: community.ID,
+		UserID:      ownerID,
+		Role:        RoleAdmin,
+		JoinedAt:    now,
+	}
+
+	if err := s.repo.CreateCommunity(community, owner); err != nil {
+		if !errors.Is(err, ErrSlugExists) && !errors.Is(err, ErrHostExists) {
+			logger.Log.Error("Failed to create community",
+				zap.String("slug", slug),
+				zap.Error(err))
+		}
+		return nil, err
+	}
+
+	logger.Log.Info("Community created",
+		zap.String("community_id", community.ID),
+		zap.String("owner_id", ownerID))
+	return community, nil
+}
+
+func (s *authServiceImpl) GetCommunity(slug string) (*authmodels.Community, error) {
+	return s.repo.GetCommunityBySlug(strings.ToLower(slug))
+}
+
+func (s *authServiceImpl) JoinCommunity(slug, userID string) (*authmodels.CommunityMember, error) {
+	community, err := s.repo.GetCommunityBySlug(strings.ToLower(slug))
+	if err != nil {
+		return nil, err
+	}
+	if !community.Open {
+		return nil, ErrForbidden
+	}
+
+	member := &authmodels.CommunityMember{
+		CommunityID: community.ID,
+		UserID:      userID,
+		Role:        RoleUser,
+		JoinedAt:    time.Now(),
+	}
+	if err := s.repo.AddCommunityMember(member); err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, ErrAlreadyMember
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *authServiceImpl) ListUserCommunities(userID string) ([]*authmodels.CommunityMember, error) {
+	return s.repo.ListUserCommunities(userID)
+}
+
+// SetMemberRole меняет роль участника. Доступно администраторам сообщества
+// и глобальным администраторам.
+func (s *authServiceImpl) SetMemberRole(actorID, slug, userID, role string) error {
+	switch role {
+	case RoleUser, RoleModerator, RoleAdmin:
+	default:
+		return ErrInvalidRole
+	}
+
+	community, err := s.repo.GetCommunityBySlug(strings.ToLower(slug))
+	if err != nil {
+		return err
+	}
+
+	actorRole, _, err := s.GetMemberRole(community.ID, actorID)
+	if err != nil {
+		return err
+	}
+	if actorRole != RoleAdmin {
+		return ErrForbidden
+	}
+
+	err = s.repo.SetCommunityMemberRole(community.ID, userID, role)
+	if errors.Is(err, repository.ErrMemberNotFound) {
+		return ErrNotMember
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Log.Info("Community member role changed",
+		zap.String("community_id", community.ID),
+		zap.String("user_id", userID),
+		zap.String("role", role),
+		zap.String("actor_id", actorID))
+	return nil
+}
+
+// ResolveCommunity находит сообщество по slug, а если он не указан — по имени хоста.
+// Неизвестный хост соответствует сообществу по умолчанию.
+func (s *authServiceImpl) ResolveCommunity(host, slug string) (*authmodels.Community, error) {
+	if slug != "" {
+		return s.repo.GetCommunityBySlug(strings.ToLower(slug))
+	}
+
+	if host != "" {
+		community, err := s.repo.GetCommunityByHost(strings.ToLower(host))
+		if err == nil {
+			return community, nil
+		}
+		if !errors.Is(err, repository.ErrCommunityNotFound) {
+			return nil, err
+		}
+	}
+
+	return s.repo.GetCommunityByID(authmodels.DefaultCommunityID)
+}
+
+// GetMemberRole возвращает роль пользователя в сообществе и признак блокировки.
+// Глобальные администраторы считаются администраторами любого сообщества,
+// а в открытых сообществах любой пользователь участвует с ролью user.
+// Пустая роль означает, что пользователь не участник.
+func (s *authServiceImpl) GetMemberRole(communityID, userID string) (string, bool, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return "", false, err
+	}
+	if user.Role == RoleAdmin {
+		return RoleAdmin, user.Blocked, nil
+	}
+
+	member, err := s.repo.GetCommunityMember(communityID, userID)
+	if err == nil {
+		return member.Role, user.Blocked || member.Blocked, nil
+	}
+	if !errors.Is(err, repository.ErrMemberNotFound) {
+		return "", false, err
+	}
+
+	community, err := s.repo.GetCommunityByID(communityID)
+	if err != nil {
+		return "", false, err
+	}
+	if community.Open {
+		return RoleUser, user.Blocked, nil
+	}
+	return "", user.Blocked, nil
+}
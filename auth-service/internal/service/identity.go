@@ -0,0 +1,146 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/luckermt/forum-app/auth-service/internal/repository"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/models"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// maxUsernameLength — длина колонки users.username в символах
+const maxUsernameLength = 50
+
+// ExternalIdentity описывает пользователя, прошедшего вход у внешнего провайдера
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+}
+
+// LoginWithIdentity находит или создает пользователя для внешней учетной записи
+// и выпускает обычный JWT форума. Связывание с существующим аккаунтом
+// выполняется только по подтвержденному email.
+func (s *authServiceImpl) LoginWithIdentity(identity ExternalIdentity) (string, error) {
+	user, err := s.repo.GetUserByIdentity(identity.Provider, identity.Subject)
+	if err != nil && !errors.Is(err, repository.ErrUserNotFound) {
+		logger.Log.Error("Failed to get user by identity",
+			zap.String("provider", identity.Provider),
+			zap.Error(err))
+		return "", err
+	}
+
+	if user == nil {
+		user, err = s.linkIdentity(identity)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if user.Blocked {
+		logger.Log.Warn("Blocked user federated login attempt",
+			zap.String("user_id", user.ID),
+			zap.String("provider", identity.Provider))
+		return "", ErrUserBlocked
+	}
+
+	logger.Log.Info("Successful federated login",
+		zap.String("user_id", user.ID),
+		zap.String("provider", identity.Provider))
+	return s.generateJWTToken(user)
+}
+
+func (s *authServiceImpl) linkIdentity(identity ExternalIdentity) (*models.User, error) {
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
+	user, err := s.repo.GetUserByEmail(identity.Email)
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		user, err = s.createExternalUser(identity)
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		logger.Log.Error("Failed to get user by email",
+			zap.String("email", identity.Email),
+			zap.Error(err))
+		return nil, err
+	}
+
+	if err := s.repo.LinkIdentity(user.ID, identity.Provider, identity.Subject, identity.Email); err != nil {
+		logger.Log.Error("Failed to link identity",
+			zap.String("user_id", user.ID),
+			zap.String("provider", identity.Provider),
+			zap.Error(err))
+		return nil, err
+	}
+
+	logger.Log.Info("External identity linked",
+		zap.String("user_id", user.ID),
+		zap.String("provider", identity.Provider))
+	return user, nil
+}
+
+// createExternalUser создает аккаунт со случайным паролем: войти в него
+// можно только через внешнего провайдера
+func (s *authServiceImpl) createExternalUser(identity ExternalIdentity) (*models.User, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(secret)), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	username := identity.Username
+	if username == "" {
+		username, _, _ = strings.Cut(identity.Email, "@")
+	}
+	username = truncateRunes(username, maxUsernameLength)
+
+	user := &models.User{
+		ID:        uuid.New().String(),
+		Username:  username,
+		Email:     identity.Email,
+		Password:  string(hashedPassword),
+		Role:      "user",
+		CreatedAt: time.Now(),
+		Blocked:   false,
+	}
+	err = s.repo.CreateUser(user)
+	if errors.Is(err, repository.ErrUsernameExists) {
+		// Имя у провайдера может совпасть с уже занятым — добавляем суффикс
+		suffix := uuid.New().String()[:6]
+		username = truncateRunes(username, maxUsernameLength-len(suffix)-1)
+		user.Username = username + "_" + suffix
+		err = s.repo.CreateUser(user)
+	}
+	if err != nil {
+		logger.Log.Error("Failed to create external user",
+			zap.String("email", identity.Email),
+			zap.Error(err))
+		return nil, err
+	}
+	return user, nil
+}
+
+// truncateRunes обрезает строку до limit символов, не разрезая многобайтовые руны
+func truncateRunes(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit])
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateRunes_KeepsMultibyteNamesValid(t *testing.T) {
+	name := strings.Repeat("Пользователь", 5)
+
+	truncated := truncateRunes(name, maxUsernameLength)
+
+	assert.True(t, utf8.ValidString(truncated))
+	assert.Equal(t, maxUsernameLength, utf8.RuneCountInString(truncated))
+	assert.True(t, strings.HasPrefix(name, truncated))
+}
+
+func TestTruncateRunes_ShortName(t *testing.T) {
+	assert.Equal(t, "Иван", truncateRunes("Иван", maxUsernameLength))
+}
@@ -0,0 +1,398 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	authmodels "github.com/luckermt/forum-app/auth-service/internal/models"
+	"github.com/luckermt/forum-app/auth-service/internal/repository"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/models"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Области доступа, которые может запросить стороннее приложение.
+// Каждая из них проверяется forum-service при обращении к API.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+var supportedScopes = []string{ScopeRead, ScopeWrite}
+
+const (
+	authorizationCodeTTL = 5 * time.Minute
+	accessTokenTTL       = time.Hour
+)
+
+var (
+	ErrInvalidClient        = errors.New("invalid client")
+	ErrInvalidRedirectURI   = errors.New("invalid redirect uri")
+	ErrInvalidScope         = errors.New("invalid scope")
+	ErrInvalidGrant         = errors.New("invalid grant")
+	ErrUnsupportedGrantType = errors.New("unsupported grant type")
+	ErrPKCERequired         = errors.New("pkce with S256 is required")
+)
+
+// OAuthService определяет контракт OAuth2 сервера авторизации
+type OAuthService interface {
+	RegisterClient(ownerID string, req authmodels.OAuthClientRequest) (*authmodels.OAuthClientResponse, error)
+	ValidateAuthorizeRequest(req AuthorizeRequest) (*authmodels.OAuthClient, []string, error)
+	Authenticate(email, password string) (*models.User, error)
+	Authorize(userID string, req AuthorizeRequest) (string, error)
+	ExchangeCode(req TokenRequest) (*authmodels.OAuthTokenResponse, error)
+	RevokeToken(clientID, clientSecret, token string) error
+	RevokeClientTokens(ownerID, clientID string) error
+}
+
+// AuthorizeRequest параметры запроса к authorization endpoint
+type AuthorizeRequest struct {
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// TokenRequest параметры запроса к token endpoint
+type TokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	ClientID     string
+	ClientSecret string
+	CodeVerifier string
+}
+
+func NewOAuthService(repo repository.Repository, jwtSecret string) OAuthService {
+	if logger.Log == nil {
+		if err := logger.Init(); err != nil {
+			panic("Failed to initialize logger")
+		}
+	}
+
+	return &authServiceImpl{
+		repo:      repo,
+		jwtSecret: jwtSecret,
+	}
+}
+
+func (s *authServiceImpl) RegisterClient(ownerID string, req authmodels.OAuthClientRequest) (*authmodels.OAuthClientResponse, error) {
+	if len(req.RedirectURIs) == 0 {
+		return nil, ErrInvalidRedirectURI
+	}
+	for _, uri := range req.RedirectURIs {
+		parsed, err := url.Parse(uri)
+		if err != nil || !parsed.IsAbs() || parsed.Fragment != "" {
+			return nil, ErrInvalidRedirectURI
+		}
+	}
+
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{ScopeRead}
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(supportedScopes, scope) {
+			return nil, ErrInvalidScope
+		}
+	}
+
+	client := &authmodels.OAuthClient{
+		ID:           uuid.New().String(),
+		Name:         req.Name,
+		RedirectURIs: req.RedirectURIs,
+		Scopes:       scopes,
+		OwnerID:      ownerID,
+		Public:       req.Public,
+		CreatedAt:    time.Now(),
+	}
+
+	var secret string
+	if !client.Public {
+		var err error
+		secret, err = randomToken(32)
+		if err != nil {
+			return nil, err
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		client.SecretHash = string(hash)
+	}
+
+	if err := s.repo.CreateOAuthClient(client); err != nil {
+		logger.Log.Error("Failed to create oauth client",
+			zap.String("owner_id", ownerID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	logger.Log.Info("OAuth client registered",
+		zap.String("client_id", client.ID),
+		zap.String("owner_id", ownerID))
+	return &authmodels.OAuthClientResponse{
+		OAuthClient:  *client,
+		ClientSecret: secret,
+	}, nil
+}
+
+// ValidateAuthorizeRequest проверяет клиента, redirect_uri, области доступа и PKCE
+// и возвращает клиента вместе с итоговым списком областей
+func (s *authServiceImpl) ValidateAuthorizeRequest(req AuthorizeRequest) (*authmodels.OAuthClient, []string, error) {
+	client, err := s.repo.GetOAuthClient(req.ClientID)
+	if errors.Is(err, repository.ErrClientNotFound) {
+		return nil, nil, ErrInvalidClient
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !slices.Contains(client.RedirectURIs, req.RedirectURI) {
+		return nil, nil, ErrInvalidRedirectURI
+	}
+	if req.CodeChallenge == "" || req.CodeChallengeMethod != "S256" {
+		return client, nil, ErrPKCERequired
+	}
+
+	scopes := strings.Fields(req.Scope)
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(client.Scopes, scope) {
+			return client, nil, ErrInvalidScope
+		}
+	}
+
+	return client, scopes, nil
+}
+
+func (s *authServiceImpl) Authenticate(email, password string) (*models.User, error) {
+	return s.authenticate(email, password)
+}
+
+// Authorize выдает код авторизации после согласия пользователя
+func (s *authServiceImpl) Authorize(userID string, req AuthorizeRequest) (string, error) {
+	client, scopes, err := s.ValidateAuthorizeRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	code, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.repo.CreateAuthorizationCode(&authmodels.OAuthAuthorizationCode{
+		CodeHash:      hashToken(code),
+		ClientID:      client.ID,
+		UserID:        userID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		CodeChallenge: req.CodeChallenge,
+		ExpiresAt:     time.Now().Add(authorizationCodeTTL),
+	})
+	if err != nil {
+		logger.Log.Error("Failed to store authorization code",
+			zap.String("client_id", client.ID),
+			zap.Error(err))
+		return "", err
+	}
+
+	logger.Log.Info("Authorization granted",
+		zap.String("client_id", client.ID),
+		zap.String("user_id", userID),
+		zap.Strings("scopes", scopes))
+	return code, nil
+}
+
+// ExchangeCode обменивает код авторизации на токен доступа
+func (s *authServiceImpl) ExchangeCode(req TokenRequest) (*authmodels.OAuthTokenResponse, error) {
+	if req.GrantType != "authorization_code" {
+		return nil, ErrUnsupportedGrantType
+	}
+
+	client, err := s.authenticateClient(req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := s.repo.ConsumeAuthorizationCode(hashToken(req.Code))
+	if errors.Is(err, repository.ErrCodeNotFound) {
+		return nil, ErrInvalidGrant
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if code.ClientID != client.ID || code.RedirectURI != req.RedirectURI || time.Now().After(code.ExpiresAt) {
+		return nil, ErrInvalidGrant
+	}
+	if subtle.ConstantTimeCompare([]byte(pkceChallenge(req.CodeVerifier)), []byte(code.CodeChallenge)) != 1 {
+		return nil, ErrInvalidGrant
+	}
+
+	user, err := s.repo.GetUserByID(code.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Blocked {
+		return nil, ErrUserBlocked
+	}
+
+	accessToken := &authmodels.OAuthAccessToken{
+		ID:        uuid.New().String(),
+		ClientID:  client.ID,
+		UserID:    user.ID,
+		Scopes:    code.Scopes,
+		ExpiresAt: time.Now().Add(accessTokenTTL),
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.CreateAccessToken(accessToken); err != nil {
+		logger.Log.Error("Failed to store access token",
+			zap.String("client_id", client.ID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	signed, err := s.generateAccessToken(user, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authmodels.OAuthTokenResponse{
+		AccessToken: signed,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(accessTokenTTL.Seconds()),
+		Scope:       strings.Join(accessToken.Scopes, " "),
+	}, nil
+}
+
+// RevokeToken отзывает токен по запросу выпустившего его клиента (RFC 7009)
+func (s *authServiceImpl) RevokeToken(clientID, clientSecret, token string) error {
+	client, err := s.authenticateClient(clientID, clientSecret)
+	if err != nil {
+		return err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(s.jwtSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithoutClaimsValidation())
+	if err != nil {
+		// По RFC 7009 неизвестный токен не является ошибкой
+		return nil
+	}
+
+	tokenID, _ := claims["jti"].(string)
+	if tokenID == "" {
+		return nil
+	}
+	return s.repo.RevokeAccessToken(client.ID, tokenID)
+}
+
+// RevokeClientTokens отзывает все токены клиента по запросу его владельца
+func (s *authServiceImpl) RevokeClientTokens(ownerID, clientID string) error {
+	client, err := s.repo.GetOAuthClient(clientID)
+	if errors.Is(err, repository.ErrClientNotFound) {
+		return ErrInvalidClient
+	}
+	if err != nil {
+		return err
+	}
+	if client.OwnerID != ownerID {
+		return ErrInvalidClient
+	}
+
+	if err := s.repo.RevokeClientTokens(client.ID); err != nil {
+		logger.Log.Error("Failed to revoke client tokens",
+			zap.String("client_id", client.ID),
+			zap.Error(err))
+		return err
+	}
+
+	logger.Log.Info("Client tokens revoked", zap.String("client_id", client.ID))
+	return nil
+}
+
+func (s *authServiceImpl) authenticateClient(clientID, clientSecret string) (*authmodels.OAuthClient, error) {
+	client, err := s.repo.GetOAuthClient(clientID)
+	if errors.Is(err, repository.ErrClientNotFound) {
+		return nil, ErrInvalidClient
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !client.Public {
+		if bcrypt.CompareHashAndPassword([]byte(client.SecretHash), []byte(clientSecret)) != nil {
+			return nil, ErrInvalidClient
+		}
+	}
+	return client, nil
+}
+
+func (s *authServiceImpl) generateAccessToken(user *models.User, accessToken *authmodels.OAuthAccessToken) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id":   user.ID,
+		"client_id": accessToken.ClientID,
+		"scope":     strings.Join(accessToken.Scopes, " "),
+		"jti":       accessToken.ID,
+		"iat":       issuedAtClaim(accessToken.CreatedAt),
+		"exp":       accessToken.ExpiresAt.Unix(),
+	})
+
+	return token.SignedString([]byte(s.jwtSecret))
+}
+
+// checkAccessToken проверяет, что токен стороннего приложения не отозван
+func (s *authServiceImpl) checkAccessToken(clientID string, claims jwt.MapClaims) error {
+	tokenID, _ := claims["jti"].(string)
+	if tokenID == "" {
+		return ErrTokenRevoked
+	}
+
+	token, err := s.repo.GetAccessToken(tokenID)
+	if errors.Is(err, repository.ErrTokenNotFound) {
+		return ErrTokenRevoked
+	}
+	if err != nil {
+		return err
+	}
+	if token.Revoked || token.ClientID != clientID {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	authmodels "github.com/luckermt/forum-app/auth-service/internal/models"
+	"github.com/luckermt/forum-app/auth-service/internal/repository/mocks"
+	"github.com/luckermt/forum-app/auth-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOAuthService_AuthorizationCodeFlow(t *testing.T) {
+
+	if err := logger.Init(); err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Log.Sync()
+
+	repo := new(mocks.Repository)
+	jwtSecret := "test-secret-key"
+	oauthSvc := service.NewOAuthService(repo, jwtSecret)
+	authSvc := service.NewAuthService(repo, jwtSecret)
+
+	verifier := "a-sufficiently-long-pkce-code-verifier-value"
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	client := &authmodels.OAuthClient{
+		ID:           "client-1",
+		RedirectURIs: []string{"https://app.example.com/callback"},
+		Scopes:       []string{service.ScopeRead, service.ScopeWrite},
+		Public:       true,
+	}
+	repo.On("GetOAuthClient", "client-1").Return(client, nil)
+
+	var stored *authmodels.OAuthAuthorizationCode
+	repo.On("CreateAuthorizationCode", mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*authmodels.OAuthAuthorizationCode)
+	}).Return(nil)
+
+	req := service.AuthorizeRequest{
+		ClientID:            "client-1",
+		RedirectURI:         "https://app.example.com/callback",
+		Scope:               "read",
+		CodeChallenge:       challenge,
+		CodeChallengeMethod: "S256",
+	}
+	code, err := oauthSvc.Authorize("user-1", req)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, code)
+	assert.Equal(t, []string{service.ScopeRead}, stored.Scopes)
+
+	repo.On("ConsumeAuthorizationCode", stored.CodeHash).Return(stored, nil)
+	repo.On("GetUserByID", "user-1").Return(&models.User{ID: "user-1", Role: "user"}, nil)
+
+	var issued *authmodels.OAuthAccessToken
+	repo.On("CreateAccessToken", mock.Anything).Run(func(args mock.Arguments) {
+		issued = args.Get(0).(*authmodels.OAuthAccessToken)
+	}).Return(nil)
+
+	resp, err := oauthSvc.ExchangeCode(service.TokenRequest{
+		GrantType:    "authorization_code",
+		Code:         code,
+		RedirectURI:  "https://app.example.com/callback",
+		ClientID:     "client-1",
+		CodeVerifier: verifier,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "read", resp.Scope)
+
+	repo.On("GetTokensRevokedAt", "user-1").Return(time.Time{}, nil)
+	repo.On("GetAccessToken", issued.ID).Return(issued, nil).Once()
+	info, err := authSvc.ValidateToken(resp.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", info.UserID)
+	assert.Equal(t, "client-1", info.ClientID)
+	assert.Equal(t, []string{"read"}, info.Scopes)
+	assert.True(t, info.HasScope(service.ScopeRead))
+	assert.False(t, info.HasScope(service.ScopeWrite))
+
+	claims := jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(resp.AccessToken, claims)
+	assert.NoError(t, err)
+	assert.NotContains(t, claims, "role")
+
+	revoked := *issued
+	revoked.Revoked = true
+	repo.On("GetAccessToken", issued.ID).Return(&revoked, nil).Once()
+	_, err = authSvc.ValidateToken(resp.AccessToken)
+	assert.ErrorIs(t, err, service.ErrTokenRevoked)
+}
+
+func TestOAuthService_ExchangeCode_RejectsWrongVerifier(t *testing.T) {
+
+	if err := logger.Init(); err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Log.Sync()
+
+	repo := new(mocks.Repository)
+	oauthSvc := service.NewOAuthService(repo, "test-secret-key")
+
+	repo.On("GetOAuthClient", "client-1").Return(&authmodels.OAuthClient{ID: "client-1", Public: true}, nil)
+	repo.On("ConsumeAuthorizationCode", mock.Anything).Return(&authmodels.OAuthAuthorizationCode{
+		ClientID:      "client-1",
+		RedirectURI:   "https://app.example.com/callback",
+		CodeChallenge: "expected-challenge",
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}, nil)
+
+	_, err := oauthSvc.ExchangeCode(service.TokenRequest{
+		GrantType:    "authorization_code",
+		Code:         "code",
+		RedirectURI:  "https://app.example.com/callback",
+		ClientID:     "client-1",
+		CodeVerifier: "wrong",
+	})
+	assert.ErrorIs(t, err, service.ErrInvalidGrant)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	authmodels "github.com/luckermt/forum-app/auth-service/internal/models"
+	"github.com/luckermt/forum-app/auth-service/internal/repository"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/models"
+	"go.uber.org/zap"
+)
+
+// KarmaClient получает карму пользователей из forum-service
+type KarmaClient interface {
+	GetUserKarma(userID string) (*authmodels.Karma, error)
+}
+
+// maxResolveUsernames ограничивает число имен в одном запросе ResolveUsernames
+const maxResolveUsernames = 50
+
+// ProfileService определяет контракт получения публичных данных пользователей
+type ProfileService interface {
+	GetProfile(userID string) (*authmodels.UserProfile, error)
+	ResolveUsernames(usernames []string) ([]*models.User, error)
+}
+
+type profileServiceImpl struct {
+	repo  repository.Repository
+	karma KarmaClient
+}
+
+// NewProfileService создает сервис профилей. karma может быть nil,
+// тогда профили отдаются без кармы.
+func NewProfileService(repo repository.Repository, karma KarmaClient) ProfileService {
+	if logger.Log == nil {
+		if err := logger.Init(); err != nil {
+			panic("Failed to initialize logger")
+		}
+	}
+
+	return &profileServiceImpl{
+		repo:  repo,
+		karma: karma,
+	}
+}
+
+// GetProfile возвращает профиль пользователя вместе с кармой.
+// Ошибка forum-service не мешает отдать профиль.
+func (s *profileServiceImpl) GetProfile(userID string) (*authmodels.UserProfile, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &authmodels.UserProfile{
+		ID:        user.ID,
+		Username:  user.Username,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+	}
+
+	if s.karma != nil {
+		karma, err := s.karma.GetUserKarma(userID)
+		if err != nil {
+			logger.Log.Warn("Failed to get user karma",
+				zap.String("user_id", userID),
+				zap.Error(err))
+		} else {
+			profile.Karma = karma
+		}
+	}
+	return profile, nil
+}
+
+// ResolveUsernames находит пользователей по именам; неизвестные имена пропускаются
+func (s *profileServiceImpl) ResolveUsernames(usernames []string) ([]*models.User, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+	if len(usernames) > maxResolveUsernames {
+		usernames = usernames[:maxResolveUsernames]
+	}
+	return s.repo.GetUsersByUsernames(usernames)
+}
@@ -0,0 +1,53 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	authmodels "github.com/luckermt/forum-app/auth-service/internal/models"
+	"github.com/luckermt/forum-app/auth-service/internal/repository/mocks"
+	"github.com/luckermt/forum-app/auth-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type karmaClientStub struct {
+	karma *authmodels.Karma
+	err   error
+}
+
+func (c karmaClientStub) GetUserKarma(userID string) (*authmodels.Karma, error) {
+	return c.karma, c.err
+}
+
+func TestProfileService_GetProfile(t *testing.T) {
+	if err := logger.Init(); err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	repo := new(mocks.Repository)
+	repo.On("GetUserByID", "user-1").Return(&models.User{ID: "user-1", Username: "john", Role: "user"}, nil)
+
+	karma := &authmodels.Karma{Karma: 7, Upvotes: 9, Downvotes: 2}
+	profile, err := service.NewProfileService(repo, karmaClientStub{karma: karma}).GetProfile("user-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "john", profile.Username)
+	assert.Equal(t, karma, profile.Karma)
+	repo.AssertExpectations(t)
+}
+
+func TestProfileService_GetProfile_KarmaUnavailable(t *testing.T) {
+	if err := logger.Init(); err != nil {
+		t.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	repo := new(mocks.Repository)
+	repo.On("GetUserByID", "user-1").Return(&models.User{ID: "user-1", Username: "john"}, nil)
+
+	profile, err := service.NewProfileService(repo, karmaClientStub{err: errors.New("unavailable")}).GetProfile("user-1")
+
+	assert.NoError(t, err)
+	assert.Nil(t, profile.Karma)
+}
@@ -0,0 +1,10 @@
+CREATE TABLE user_identities (
+    provider   VARCHAR(50) NOT NULL,
+    subject    VARCHAR(255) NOT NULL,
+    user_id    VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email      VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (provider, subject)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
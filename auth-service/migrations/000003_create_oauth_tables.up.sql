@@ -0,0 +1,32 @@
+CREATE TABLE oauth_clients (
+    id            VARCHAR(36) PRIMARY KEY,
+    secret_hash   VARCHAR(100) NOT NULL DEFAULT '',
+    name          VARCHAR(100) NOT NULL,
+    redirect_uris TEXT[] NOT NULL,
+    scopes        TEXT[] NOT NULL,
+    owner_id      VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    public        BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at    TIMESTAMP NOT NULL
+);
+
+CREATE TABLE oauth_authorization_codes (
+    code_hash      VARCHAR(64) PRIMARY KEY,
+    client_id      VARCHAR(36) NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+    user_id        VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    redirect_uri   TEXT NOT NULL,
+    scopes         TEXT[] NOT NULL,
+    code_challenge VARCHAR(128) NOT NULL,
+    expires_at     TIMESTAMP NOT NULL
+);
+
+CREATE TABLE oauth_access_tokens (
+    id         VARCHAR(36) PRIMARY KEY,
+    client_id  VARCHAR(36) NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+    user_id    VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scopes     TEXT[] NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked    BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_oauth_access_tokens_client_id ON oauth_access_tokens(client_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_created_at_id;
+ALTER TABLE users DROP COLUMN IF EXISTS tokens_revoked_at;
\ No newline at end of file
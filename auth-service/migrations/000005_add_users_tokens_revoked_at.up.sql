@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN tokens_revoked_at TIMESTAMP;
+
+CREATE INDEX idx_users_created_at_id ON users(created_at, id);
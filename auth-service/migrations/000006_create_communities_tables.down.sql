@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS community_members;
+DROP TABLE IF EXISTS communities;
\ No newline at end of file
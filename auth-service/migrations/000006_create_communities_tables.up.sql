@@ -0,0 +1,24 @@
+CREATE TABLE communities (
+    id         VARCHAR(36) PRIMARY KEY,
+    slug       VARCHAR(50) NOT NULL UNIQUE,
+    name       VARCHAR(100) NOT NULL,
+    host       VARCHAR(255) UNIQUE,
+    open       BOOLEAN NOT NULL DEFAULT TRUE,
+    owner_id   VARCHAR(36) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE community_members (
+    community_id VARCHAR(36) NOT NULL REFERENCES communities(id) ON DELETE CASCADE,
+    user_id      VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role         VARCHAR(20) NOT NULL DEFAULT 'user',
+    blocked      BOOLEAN NOT NULL DEFAULT FALSE,
+    joined_at    TIMESTAMP NOT NULL,
+    PRIMARY KEY (community_id, user_id)
+);
+
+CREATE INDEX idx_community_members_user_id ON community_members(user_id);
+
+-- Сообщество по умолчанию для данных, созданных до появления сообществ
+INSERT INTO communities (id, slug, name, open, created_at)
+VALUES ('default', 'default', 'Default community', TRUE, NOW());
@@ -2,6 +2,9 @@ package main
 
 import (
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -9,8 +12,11 @@ import (
 	_ "github.com/luckermt/forum-app/forum-service/docs"                 // Важно!
 	authGrpc "github.com/luckermt/forum-app/forum-service/internal/grpc" // Переименованный импорт
 	"github.com/luckermt/forum-app/forum-service/internal/handler"
+	"github.com/luckermt/forum-app/forum-service/internal/imaging"
+	"github.com/luckermt/forum-app/forum-service/internal/markdown"
 	"github.com/luckermt/forum-app/forum-service/internal/repository"
 	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/luckermt/forum-app/forum-service/internal/storage"
 	"github.com/luckermt/forum-app/shared/pkg/config"
 	"github.com/luckermt/forum-app/shared/pkg/logger"
 	httpSwagger "github.com/swaggo/http-swagger" // пакет для UI
@@ -71,22 +77,74 @@ func main() {
 	}
 
 	forumService := service.NewForumService(repo, authClient)
+	if window := os.Getenv("MESSAGE_EDIT_WINDOW"); window != "" {
+		editWindow, err := time.ParseDuration(window)
+		if err != nil {
+			logger.Log.Fatal("Invalid MESSAGE_EDIT_WINDOW", zap.String("value", window), zap.Error(err))
+		}
+		forumService.SetEditWindow(editWindow)
+	}
+
+	markdownOptions, err := markdown.LoadOptions()
+	if err != nil {
+		logger.Log.Fatal("Invalid Markdown settings", zap.Error(err))
+	}
+	forumService.SetMarkdownRenderer(markdown.NewRenderer(markdownOptions))
+
+	blobStore, err := storage.NewBlobStore(storage.LoadConfig())
+	if err != nil {
+		logger.Log.Fatal("Failed to initialize attachment storage", zap.Error(err))
+	}
+	attachmentLimits := service.DefaultAttachmentLimits
+	if maxSize := os.Getenv("ATTACHMENT_MAX_SIZE"); maxSize != "" {
+		size, err := strconv.ParseInt(maxSize, 10, 64)
+		if err != nil || size <= 0 {
+			logger.Log.Fatal("Invalid ATTACHMENT_MAX_SIZE", zap.String("value", maxSize))
+		}
+		attachmentLimits.MaxSize = size
+	}
+	if types := os.Getenv("ATTACHMENT_TYPES"); types != "" {
+		attachmentLimits.AllowedTypes = strings.Split(types, ",")
+	}
+	forumService.SetAttachmentStorage(blobStore, attachmentLimits)
+
+	imageLimits := imaging.DefaultLimits
+	if maxPixels := os.Getenv("IMAGE_MAX_PIXELS"); maxPixels != "" {
+		pixels, err := strconv.Atoi(maxPixels)
+		if err != nil || pixels <= 0 {
+			logger.Log.Fatal("Invalid IMAGE_MAX_PIXELS", zap.String("value", maxPixels))
+		}
+		imageLimits.MaxPixels = pixels
+	}
+	forumService.SetImageLimits(imageLimits)
+	imageInterval := 5 * time.Second
+	if interval := os.Getenv("IMAGE_WORKER_INTERVAL"); interval != "" {
+		imageInterval, err = time.ParseDuration(interval)
+		if err != nil || imageInterval <= 0 {
+			logger.Log.Fatal("Invalid IMAGE_WORKER_INTERVAL", zap.String("value", interval))
+		}
+	}
 
-	forumHandler := handler.NewForumHandler(forumService)
-	chatHandler := handler.NewChatHandler(forumService)
+	// gRPC API форума (карма для профилей auth-service)
+	if port := os.Getenv("FORUM_GRPC_PORT"); port != "" {
+		forumServer := authGrpc.NewForumServer(forumService)
+		go func() {
+			if err := forumServer.Start(port); err != nil {
+				logger.Log.Fatal("Failed to start gRPC server", zap.Error(err))
+			}
+		}()
+		defer forumServer.Stop()
+	}
 
 	// Настройка маршрутов
-	http.HandleFunc("POST /topics", forumHandler.CreateTopic)
-	http.HandleFunc("DELETE /topics/{id}", forumHandler.DeleteTopic)
-	http.HandleFunc("GET /topics", forumHandler.GetTopics)
-	http.HandleFunc("GET /messages", forumHandler.GetMessages)
-	http.HandleFunc("/ws", chatHandler.HandleConnections)
-	http.Handle("/swagger/", httpSwagger.WrapHandler)
+	router := handler.NewRouter(forumService)
+	router.Handle("/swagger/", httpSwagger.WrapHandler)
 
 	go forumService.CleanOldMessages(24 * time.Hour)
+	go forumService.ProcessImages(imageInterval)
 
 	logger.Log.Info("Starting forum service", zap.String("port", cfg.Server.Port))
-	if err := http.ListenAndServe(":"+cfg.Server.Port, nil); err != nil {
+	if err := http.ListenAndServe(":"+cfg.Server.Port, handler.CommunityMiddleware(forumService, handler.AuthMiddleware(forumService, router))); err != nil {
 		logger.Log.Fatal("Failed to start forum service", zap.Error(err))
 	}
 
@@ -4,13 +4,18 @@ import (
 	"context"
 	"errors"
 
+	"github.com/luckermt/forum-app/forum-service/internal/service"
 	"github.com/luckermt/forum-app/shared/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type AuthClient struct {
-	conn   *grpc.ClientConn
-	client proto.AuthServiceClient
+	conn            *grpc.ClientConn
+	client          proto.AuthServiceClient
+	communityClient proto.CommunityServiceClient
+	usersClient     proto.UserDirectoryServiceClient
 }
 
 func NewAuthClient(addr string) (*AuthClient, error) {
@@ -20,22 +25,28 @@ func NewAuthClient(addr string) (*AuthClient, error) {
 	}
 
 	return &AuthClient{
-		conn:   conn,
-		client: proto.NewAuthServiceClient(conn),
+		conn:            conn,
+		client:          proto.NewAuthServiceClient(conn),
+		communityClient: proto.NewCommunityServiceClient(conn),
+		usersClient:     proto.NewUserDirectoryServiceClient(conn),
 	}, nil
 }
 
-func (c *AuthClient) ValidateToken(token string) (string, error) {
+func (c *AuthClient) ValidateToken(token string) (*service.TokenInfo, error) {
 	resp, err := c.client.ValidateToken(context.Background(), &proto.TokenRequest{Token: token})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if !resp.Valid {
-		return "", errors.New("invalid token")
+		return nil, errors.New("invalid token")
 	}
 
-	return resp.UserId, nil
+	return &service.TokenInfo{
+		UserID:   resp.UserId,
+		ClientID: resp.ClientId,
+		Scopes:   resp.Scopes,
+	}, nil
 }
 func (c *AuthClient) IsUserAdmin(userID string) (bool, error) {
 	resp, err := c.client.GetUserRole(context.Background(), &proto.UserRequest{
@@ -46,3 +57,43 @@ func (c *AuthClient) IsUserAdmin(userID string) (bool, error) {
 	}
 	return resp.Role == "admin", nil
 }
+
+func (c *AuthClient) ResolveCommunity(host, slug string) (string, error) {
+	resp, err := c.communityClient.ResolveCommunity(context.Background(), &proto.ResolveCommunityRequest{
+		Host: host,
+		Slug: slug,
+	})
+	if status.Code(err) == codes.NotFound {
+		return "", service.ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return resp.Id, nil
+}
+
+func (c *AuthClient) GetMemberRole(communityID, userID string) (string, bool, error) {
+	resp, err := c.communityClient.GetMemberRole(context.Background(), &proto.MemberRoleRequest{
+		CommunityId: communityID,
+		UserId:      userID,
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return resp.Role, resp.Blocked, nil
+}
+
+func (c *AuthClient) ResolveUsernames(communityID string, usernames []string) (map[string]string, error) {
+	resp, err := c.usersClient.ResolveUsernames(context.Background(), &proto.ResolveUsernamesRequest{
+		Usernames:   usernames,
+		CommunityId: communityID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	users := make(map[string]string, len(resp.Users))
+	for _, user := range resp.Users {
+		users[user.Username] = user.Id
+	}
+	return users, nil
+}
@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ForumServer реализует gRPC API форума для других сервисов
+type ForumServer struct {
+	grpcServer   *grpc.Server
+	forumService service.ForumService
+	proto.UnimplementedKarmaServiceServer
+}
+
+// NewForumServer создает gRPC сервер форума
+func NewForumServer(forumService service.ForumService) *ForumServer {
+	srv := grpc.NewServer()
+	s := &ForumServer{
+		grpcServer:   srv,
+		forumService: forumService,
+	}
+	proto.RegisterKarmaServiceServer(srv, s)
+	return s
+}
+
+// Start запускает gRPC сервер
+func (s *ForumServer) Start(port string) error {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
+	if err != nil {
+		return fmt.Errorf("failed to listen: %v", err)
+	}
+
+	logger.Log.Info("Starting gRPC server",
+		zap.String("port", port),
+		zap.String("service", "forum"))
+
+	return s.grpcServer.Serve(lis)
+}
+
+// Stop останавливает gRPC сервер
+func (s *ForumServer) Stop() {
+	logger.Log.Info("Gracefully stopping gRPC server")
+	s.grpcServer.GracefulStop()
+}
+
+// GetUserKarma реализует gRPC метод получения кармы пользователя
+func (s *ForumServer) GetUserKarma(ctx context.Context, req *proto.UserKarmaRequest) (*proto.UserKarmaResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	karma, err := s.forumService.GetUserKarma(req.UserId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &proto.UserKarmaResponse{
+		Karma:     karma.Karma,
+		Upvotes:   karma.Upvotes,
+		Downvotes: karma.Downvotes,
+	}, nil
+}
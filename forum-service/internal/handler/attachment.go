@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// @Summary Загрузить вложение
+// @Description Файл передается в поле file формы multipart/form-data. Тип определяется по содержимому файла. ID вложения затем указывается в attachment_ids темы или ответа.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param file formData file true "Файл"
+// @Success 201 {object} forummodels.Attachment
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /attachments [post]
+func (h *ForumHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Expected multipart/form-data", http.StatusBadRequest)
+		return
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			http.Error(w, "Missing file field", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			logger.Log.Error("Failed to read multipart body", zap.Error(err))
+			http.Error(w, "Invalid request", http.StatusBadRequest)
+			return
+		}
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		// Файл читается прямо из тела запроса, без буферизации формы в памяти
+		attachment, err := h.service.UploadAttachment(communityIDFromContext(r.Context()), userID, part.FileName(), part)
+		part.Close()
+		if err != nil {
+			writeAttachmentError(w, err, "Failed to upload attachment")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(attachment)
+		return
+	}
+}
+
+// @Summary Скачать вложение
+// @Description Свои вложения доступны всегда, чужие — участникам сообщества, если вложение прикреплено к теме или сообщению. Картинки отдаются для показа в браузере, остальные файлы — для сохранения. Вместо исходной картинки отдается ее копия без метаданных, параметр variant выбирает уменьшенную копию. Пока картинка обрабатывается, ее получает только загрузивший.
+// @Tags attachments
+// @Produce octet-stream
+// @Security ApiKeyAuth
+// @Param id path string true "ID вложения"
+// @Param variant query string false "Вариант картинки: thumb, medium, large или original"
+// @Success 200 {file} file
+// @Success 304
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /attachments/{id} [get]
+func (h *ForumHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	attachmentID := r.PathValue("id")
+	file, content, err := h.service.OpenAttachment(communityIDFromContext(r.Context()), attachmentID, r.URL.Query().Get("variant"), userID)
+	if err != nil {
+		writeAttachmentError(w, err, "Failed to get attachment")
+		return
+	}
+	defer content.Close()
+
+	etag := `"` + file.Key + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	disposition := "attachment"
+	if strings.HasPrefix(file.ContentType, "image/") {
+		disposition = "inline"
+	}
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(file.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": file.Filename}))
+	// Браузер не должен угадывать тип и исполнять содержимое как страницу
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+
+	if _, err := io.Copy(w, content); err != nil {
+		logger.Log.Error("Failed to send attachment",
+			zap.String("attachment_id", attachmentID),
+			zap.Error(err))
+	}
+}
+
+func writeAttachmentError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidAttachment):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, service.ErrAttachmentType):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.Is(err, service.ErrImageRejected):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(err, service.ErrImageProcessing):
+		w.Header().Set("Retry-After", "5")
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case errors.Is(err, service.ErrAttachmentsDisabled):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		writeTopicError(w, err, message)
+	}
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+)
+
+// AuthMiddleware проверяет токен из заголовка Authorization через auth-service
+// и кладет ID пользователя в контекст. Запросы без заголовка пропускаются дальше:
+// публичные маршруты отвечают анонимно, остальные сами возвращают 401.
+// Токены сторонних приложений допускаются только с подходящей областью доступа.
+func AuthMiddleware(forumService service.ForumService, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Токенам сторонних приложений чтение доступно с областью read, изменения с write
+		scope := service.ScopeWrite
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			scope = service.ScopeRead
+		}
+
+		token := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+		userID, err := forumService.ValidateUser(token, scope)
+		if errors.Is(err, service.ErrInsufficientScope) {
+			http.Error(w, "Insufficient scope", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), "userID", userID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/utils"
+	"github.com/luckermt/forum-app/shared/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// @Summary Дерево разделов
+// @Description Разделы сообщества с подразделами и количеством тем и сообщений
+// @Tags categories
+// @Produce json
+// @Success 200 {array} forummodels.Category
+// @Failure 500 {object} map[string]string
+// @Router /categories [get]
+func (h *ForumHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.service.GetCategoryTree(communityIDFromContext(r.Context()))
+	if err != nil {
+		writeCategoryError(w, err, "Failed to get categories")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(categories)
+}
+
+// @Summary Создать раздел
+// @Description Создание раздела или подраздела (только для администраторов сообщества)
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param input body forummodels.CategoryRequest true "Данные раздела"
+// @Success 201 {object} forummodels.Category
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /categories [post]
+func (h *ForumHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	req, userID, ok := decodeCategoryRequest(w, r)
+	if !ok {
+		return
+	}
+
+	category, err := h.service.CreateCategory(communityIDFromContext(r.Context()), userID, req)
+	if err != nil {
+		writeCategoryError(w, err, "Failed to create category")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(category)
+}
+
+// @Summary Изменить раздел
+// @Description Изменение раздела, в том числе перенос в другой родительский раздел (только для администраторов сообщества)
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID раздела"
+// @Param input body forummodels.CategoryRequest true "Данные раздела"
+// @Success 200 {object} forummodels.Category
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /categories/{id} [put]
+func (h *ForumHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	req, userID, ok := decodeCategoryRequest(w, r)
+	if !ok {
+		return
+	}
+
+	category, err := h.service.UpdateCategory(communityIDFromContext(r.Context()), userID, r.PathValue("id"), req)
+	if err != nil {
+		writeCategoryError(w, err, "Failed to update category")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(category)
+}
+
+// @Summary Удалить раздел
+// @Description Удаление пустого раздела (только для администраторов сообщества)
+// @Tags categories
+// @Security ApiKeyAuth
+// @Param id path string true "ID раздела"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /categories/{id} [delete]
+func (h *ForumHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteCategory(communityIDFromContext(r.Context()), userID, r.PathValue("id")); err != nil {
+		writeCategoryError(w, err, "Failed to delete category")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeCategoryRequest(w http.ResponseWriter, r *http.Request) (forummodels.CategoryRequest, string, bool) {
+	var req forummodels.CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log.Error("Failed to decode request", zap.Error(err))
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return req, "", false
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		writeValidationError(w, err)
+		return req, "", false
+	}
+
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return req, "", false
+	}
+	return req, userID, true
+}
+
+func writeCategoryError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCategory):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrCategoryExists), errors.Is(err, service.ErrCategoryNotEmpty):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeTopicError(w, err, message)
+	}
+}
@@ -4,11 +4,17 @@ import (
 	"net/http"
 
 	"github.com/gorilla/websocket"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
 	"github.com/luckermt/forum-app/forum-service/internal/service"
 	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/validator"
 	"go.uber.org/zap"
 )
 
+// maxWebSocketMessageSize ограничивает входящее сообщение websocket: текст чата
+// длиной MaxContentLength символов вместе с JSON экранированием
+const maxWebSocketMessageSize = 64 << 10
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
@@ -33,7 +39,8 @@ func NewChatHandler(service service.ForumService) *ChatHandler {
 func (h *ChatHandler) HandleConnections(w http.ResponseWriter, r *http.Request) {
 	// Проверка аутентификации
 	token := r.URL.Query().Get("token")
-	userID, err := h.service.ValidateUser(token)
+	// Через чат пишут сообщения, поэтому приложениям нужна область write
+	userID, err := h.service.ValidateUser(token, service.ScopeWrite)
 	if err != nil {
 		logger.Log.Error("Unauthorized websocket connection",
 			zap.Error(err))
@@ -48,14 +55,17 @@ func (h *ChatHandler) HandleConnections(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	defer conn.Close()
+	conn.SetReadLimit(maxWebSocketMessageSize)
 
 	
-	h.service.RegisterClient(userID, conn)
-	defer h.service.UnregisterClient(userID)
+	communityID := communityIDFromContext(r.Context())
+	h.service.RegisterClient(communityID, userID, conn)
+	defer h.service.UnregisterClient(communityID, conn)
 
 	for {
 		var msg struct {
-			Text string `json:"text"`
+			Text           string `json:"text"`
+			ConversationID string `json:"conversation_id"`
 		}
 		err := conn.ReadJSON(&msg)
 		if err != nil {
@@ -68,10 +78,67 @@ func (h *ChatHandler) HandleConnections(w http.ResponseWriter, r *http.Request)
 		}
 
 		
-		if err := h.service.HandleChatMessage(userID, msg.Text); err != nil {
+		// Сообщения с conversation_id уходят в личную переписку
+		if msg.ConversationID != "" {
+			req := forummodels.DirectMessageRequest{Content: msg.Text}
+			if err := validator.Validate(&req); err != nil {
+				continue
+			}
+			if _, err := h.service.SendDirectMessage(communityID, msg.ConversationID, userID, req); err != nil {
+				logger.Log.Error("Failed to send direct message",
+					zap.String("user_id", userID),
+					zap.String("conversation_id", msg.ConversationID),
+					zap.Error(err))
+			}
+			continue
+		}
+
+		if err := h.service.HandleChatMessage(communityID, userID, msg.Text); err != nil {
 			logger.Log.Error("Failed to handle chat message",
 				zap.String("user_id", userID),
 				zap.Error(err))
 		}
 	}
 }
+
+// @Summary WebSocket ответов темы
+// @Description Подписка на новые ответы в теме через WebSocket
+// @Tags messages
+// @Param id path string true "ID темы"
+// @Param token query string true "JWT токен"
+// @Router /topics/{id}/ws [get]
+func (h *ChatHandler) HandleTopicConnections(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.service.ValidateUser(r.URL.Query().Get("token"), service.ScopeRead)
+	if err != nil {
+		logger.Log.Error("Unauthorized websocket connection",
+			zap.Error(err))
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Log.Error("Failed to upgrade to websocket",
+			zap.Error(err))
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(maxWebSocketMessageSize)
+
+	communityID := communityIDFromContext(r.Context())
+	topicID := r.PathValue("id")
+	if err := h.service.RegisterTopicListener(communityID, topicID, userID, conn); err != nil {
+		logger.Log.Error("Failed to register topic listener",
+			zap.String("topic_id", topicID),
+			zap.Error(err))
+		return
+	}
+	defer h.service.UnregisterTopicListener(communityID, topicID, conn)
+
+	// Слушатели только получают сообщения; чтение нужно, чтобы заметить закрытие соединения
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type communityKey struct{}
+
+// communityPathPrefix префикс пути, задающий сообщество явно: /c/{slug}/topics
+const communityPathPrefix = "/c/"
+
+// CommunityMiddleware определяет сообщество запроса по префиксу /c/{slug}/ или по хосту
+// и кладет его ID в контекст. Префикс удаляется из пути, поэтому маршруты остаются прежними.
+func CommunityMiddleware(forumService service.ForumService, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var slug string
+		if rest, ok := strings.CutPrefix(r.URL.Path, communityPathPrefix); ok {
+			var path string
+			slug, path, _ = strings.Cut(rest, "/")
+			if slug == "" {
+				http.NotFound(w, r)
+				return
+			}
+
+			r2 := new(http.Request)
+			*r2 = *r
+			r2.URL = new(url.URL)
+			*r2.URL = *r.URL
+			r2.URL.Path = "/" + path
+			r2.URL.RawPath = ""
+			r = r2
+		}
+
+		communityID, err := forumService.ResolveCommunity(requestHost(r), slug)
+		if errors.Is(err, service.ErrNotFound) {
+			http.Error(w, "Community not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			logger.Log.Error("Failed to resolve community",
+				zap.String("host", r.Host),
+				zap.String("slug", slug),
+				zap.Error(err))
+			http.Error(w, "Failed to resolve community", http.StatusInternalServerError)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), communityKey{}, communityID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// communityIDFromContext возвращает сообщество запроса или сообщество по умолчанию
+func communityIDFromContext(ctx context.Context) string {
+	if communityID, ok := ctx.Value(communityKey{}).(string); ok && communityID != "" {
+		return communityID
+	}
+	return service.DefaultCommunityID
+}
+
+// requestHost возвращает имя хоста без порта
+func requestHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		return r.Host
+	}
+	return host
+}
@@ -0,0 +1,320 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/utils"
+	"github.com/luckermt/forum-app/shared/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// @Summary Начать личную переписку
+// @Description Переписка с одним или несколькими участниками сообщества. Для двух пользователей возвращается существующая переписка.
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param input body forummodels.ConversationRequest true "Участники"
+// @Success 201 {object} forummodels.Conversation
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /conversations [post]
+func (h *ForumHandler) CreateConversation(w http.ResponseWriter, r *http.Request) {
+	var req forummodels.ConversationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log.Error("Failed to decode request", zap.Error(err))
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	conversation, err := h.service.CreateConversation(communityIDFromContext(r.Context()), userID, req)
+	if err != nil {
+		writeConversationError(w, err, "Failed to create conversation")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(conversation)
+}
+
+// @Summary Список личных переписок
+// @Description Переписки текущего пользователя, недавно активные первыми, с числом непрочитанных сообщений
+// @Tags conversations
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} forummodels.Conversation
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /conversations [get]
+func (h *ForumHandler) GetConversations(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	conversations, err := h.service.GetConversations(communityIDFromContext(r.Context()), userID)
+	if err != nil {
+		http.Error(w, "Failed to get conversations", http.StatusInternalServerError)
+		return
+	}
+	if conversations == nil {
+		conversations = []*forummodels.Conversation{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(conversations)
+}
+
+// @Summary Получить переписку
+// @Tags conversations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID переписки"
+// @Success 200 {object} forummodels.Conversation
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /conversations/{id} [get]
+func (h *ForumHandler) GetConversation(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	conversation, err := h.service.GetConversation(communityIDFromContext(r.Context()), r.PathValue("id"), userID)
+	if err != nil {
+		writeConversationError(w, err, "Failed to get conversation")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(conversation)
+}
+
+// @Summary История переписки
+// @Description Сообщения переписки, по умолчанию новые первыми. Курсор следующей страницы возвращается в заголовках Link и X-Next-Cursor.
+// @Tags conversations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID переписки"
+// @Param sort query string false "Сортировка" Enums(newest, oldest)
+// @Param limit query int false "Размер страницы (по умолчанию 20, максимум 100)"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Success 200 {array} forummodels.DirectMessage
+// @Header 200 {string} Link "Ссылка на следующую страницу"
+// @Header 200 {string} X-Next-Cursor "Курсор следующей страницы"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /conversations/{id}/messages [get]
+func (h *ForumHandler) GetDirectMessages(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	params, err := pageParams(r)
+	if writePageError(w, err) {
+		return
+	}
+
+	page, err := h.service.GetDirectMessages(communityIDFromContext(r.Context()), r.PathValue("id"), userID, params)
+	if writePageError(w, err) {
+		return
+	}
+	if err != nil {
+		writeConversationError(w, err, "Failed to get messages")
+		return
+	}
+
+	messages := page.Items
+	if messages == nil {
+		messages = []*forummodels.DirectMessage{}
+	}
+
+	writePageHeaders(w, r, page.NextCursor)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(messages)
+}
+
+// @Summary Написать в переписку
+// @Description Сообщение сохраняется и доставляется участникам через WebSocket /ws. Отправить сообщение через /ws можно, указав conversation_id.
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID переписки"
+// @Param input body forummodels.DirectMessageRequest true "Текст сообщения"
+// @Success 201 {object} forummodels.DirectMessage
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /conversations/{id}/messages [post]
+func (h *ForumHandler) SendDirectMessage(w http.ResponseWriter, r *http.Request) {
+	var req forummodels.DirectMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log.Error("Failed to decode request", zap.Error(err))
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	message, err := h.service.SendDirectMessage(communityIDFromContext(r.Context()), r.PathValue("id"), userID, req)
+	if err != nil {
+		writeConversationError(w, err, "Failed to send message")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(message)
+}
+
+// @Summary Отметить переписку прочитанной
+// @Tags conversations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID переписки"
+// @Success 200 {object} forummodels.Conversation
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /conversations/{id}/read [post]
+func (h *ForumHandler) MarkConversationRead(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	conversation, err := h.service.MarkConversationRead(communityIDFromContext(r.Context()), r.PathValue("id"), userID)
+	if err != nil {
+		writeConversationError(w, err, "Failed to mark conversation read")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(conversation)
+}
+
+// @Summary Заблокированные пользователи
+// @Description Пользователи, которым запрещено писать текущему пользователю
+// @Tags conversations
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /blocks [get]
+func (h *ForumHandler) GetBlockedUsers(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	blocked, err := h.service.GetBlockedUsers(userID)
+	if err != nil {
+		http.Error(w, "Failed to get blocked users", http.StatusInternalServerError)
+		return
+	}
+	if blocked == nil {
+		blocked = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(blocked)
+}
+
+// @Summary Заблокировать пользователя
+// @Description Заблокированный пользователь не может начать переписку с текущим пользователем и писать в общие переписки
+// @Tags conversations
+// @Security ApiKeyAuth
+// @Param user_id path string true "ID пользователя"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /blocks/{user_id} [put]
+func (h *ForumHandler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.BlockUser(userID, r.PathValue("user_id")); err != nil {
+		writeConversationError(w, err, "Failed to block user")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Разблокировать пользователя
+// @Tags conversations
+// @Security ApiKeyAuth
+// @Param user_id path string true "ID пользователя"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /blocks/{user_id} [delete]
+func (h *ForumHandler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.UnblockUser(userID, r.PathValue("user_id")); err != nil {
+		writeConversationError(w, err, "Failed to unblock user")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeConversationError дополняет writeTopicError ошибками личных переписок
+func writeConversationError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidConversation), errors.Is(err, service.ErrSelfBlock):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrBlockedByUser):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		writeTopicError(w, err, message)
+	}
+}
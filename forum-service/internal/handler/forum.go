@@ -2,12 +2,15 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
 	"github.com/luckermt/forum-app/forum-service/internal/service"
 	"github.com/luckermt/forum-app/shared/pkg/logger"
-	"github.com/luckermt/forum-app/shared/pkg/models"
 	"github.com/luckermt/forum-app/shared/pkg/utils"
+	"github.com/luckermt/forum-app/shared/pkg/validator"
 	"go.uber.org/zap"
 )
 
@@ -25,30 +28,36 @@ func NewForumHandler(service service.ForumService) *ForumHandler {
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
-// @Param input body models.TopicRequest true "Данные темы"
-// @Success 201 {object} models.Topic
-// @Failure 400 {object} map[string]string
+// @Param input body forummodels.CreateTopicRequest true "Данные темы"
+// @Success 201 {object} forummodels.TopicDetail
+// @Failure 400 {object} ValidationErrorResponse
 // @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /topics [post]
 func (h *ForumHandler) CreateTopic(w http.ResponseWriter, r *http.Request) {
-	var req models.TopicRequest
+	var req forummodels.CreateTopicRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		logger.Log.Error("Failed to decode request", zap.Error(err))
 		http.Error(w, "Invalid request", http.StatusBadRequest)
 		return
 	}
 
+	if err := validator.Validate(&req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
 	userID, err := utils.GetUserIDFromContext(r.Context())
 	if err != nil {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	topic, err := h.service.CreateTopic(userID, req.Title, req.Content)
+	topic, err := h.service.CreateTopic(communityIDFromContext(r.Context()), userID, req)
 	if err != nil {
-		logger.Log.Error("Failed to create topic", zap.Error(err))
-		http.Error(w, "Failed to create topic", http.StatusInternalServerError)
+		writeTagError(w, err, "Failed to create topic")
 		return
 	}
 
@@ -58,7 +67,7 @@ func (h *ForumHandler) CreateTopic(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Удалить тему
-// @Description Удаление темы (только для администраторов)
+// @Description Удаление темы (только для администраторов сообщества)
 // @Tags topics
 // @Security ApiKeyAuth
 // @Param id path string true "ID темы"
@@ -75,7 +84,15 @@ func (h *ForumHandler) DeleteTopic(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.DeleteTopic(topicID, userID); err != nil {
+	err = h.service.DeleteTopic(communityIDFromContext(r.Context()), topicID, userID)
+	switch {
+	case errors.Is(err, service.ErrForbidden):
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	case errors.Is(err, service.ErrNotFound):
+		http.Error(w, "Topic not found", http.StatusNotFound)
+		return
+	case err != nil:
 		logger.Log.Error("Failed to delete topic", zap.Error(err))
 		http.Error(w, "Failed to delete topic", http.StatusInternalServerError)
 		return
@@ -85,54 +102,178 @@ func (h *ForumHandler) DeleteTopic(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Получить все темы
-// @Description Получение списка всех активных тем
+// @Description Получение страницы активных тем, закрепленные темы идут первыми. Курсор следующей страницы возвращается в заголовках Link и X-Next-Cursor.
 // @Tags topics
 // @Produce json
-// @Success 200 {array} models.Topic
+// @Param category_id query string false "ID раздела (включая подразделы)"
+// @Param tag query []string false "Теги; возвращаются темы со всеми указанными тегами" collectionFormat(multi)
+// @Param include_archived query bool false "Показывать архивные темы"
+// @Param sort query string false "Сортировка" Enums(newest, oldest, active, top)
+// @Param limit query int false "Размер страницы (по умолчанию 20, максимум 100)"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Success 200 {array} forummodels.TopicSummary
+// @Header 200 {string} Link "Ссылка на следующую страницу"
+// @Header 200 {string} X-Next-Cursor "Курсор следующей страницы"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /topics [get]
 func (h *ForumHandler) GetTopics(w http.ResponseWriter, r *http.Request) {
-	topics, err := h.service.GetTopics()
+	params, err := pageParams(r)
+	if writePageError(w, err) {
+		return
+	}
+
+	filter := forummodels.TopicFilter{
+		CategoryID: r.URL.Query().Get("category_id"),
+		Tags:       r.URL.Query()["tag"],
+	}
+	if archived := r.URL.Query().Get("include_archived"); archived != "" {
+		includeArchived, err := strconv.ParseBool(archived)
+		if err != nil {
+			http.Error(w, "Invalid include_archived", http.StatusBadRequest)
+			return
+		}
+		filter.IncludeArchived = includeArchived
+	}
+
+	page, err := h.service.GetTopics(communityIDFromContext(r.Context()), filter, params)
+	if writePageError(w, err) {
+		return
+	}
 	if err != nil {
-		logger.Log.Error("Failed to get topics", zap.Error(err))
-		http.Error(w, "Failed to get topics", http.StatusInternalServerError)
+		writeTagError(w, err, "Failed to get topics")
 		return
 	}
 
+	topics := page.Items
+	if topics == nil {
+		topics = []*forummodels.TopicSummary{}
+	}
+
+	writePageHeaders(w, r, page.NextCursor)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(topics)
 }
 
 // @Summary Получить сообщения
-// @Description Получение сообщений по теме или общего чата
+// @Description Получение страницы сообщений по теме или общего чата. Исправленные сообщения отмечены edited_at, удаленные возвращаются заглушками с deleted=true. У каждого сообщения есть счетчики реакций. Курсор следующей страницы возвращается в заголовках Link и X-Next-Cursor.
 // @Tags messages
 // @Produce json
 // @Param topic_id query string false "ID темы (если нужны сообщения темы)"
-// @Success 200 {array} models.Message
+// @Param sort query string false "Сортировка (top — только для сообщений темы)" Enums(oldest, newest, top)
+// @Param limit query int false "Размер страницы (по умолчанию 20, максимум 100)"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Success 200 {array} forummodels.MessageDetail
+// @Header 200 {string} Link "Ссылка на следующую страницу"
+// @Header 200 {string} X-Next-Cursor "Курсор следующей страницы"
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /messages [get]
 func (h *ForumHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
+	params, err := pageParams(r)
+	if writePageError(w, err) {
+		return
+	}
+
 	topicID := r.URL.Query().Get("topic_id")
-	var messages []*models.Message
-	var err error
+	communityID := communityIDFromContext(r.Context())
+	// Анонимные запросы тоже допустимы, тогда reacted_by_me всегда false
+	viewerID, _ := utils.GetUserIDFromContext(r.Context())
+	var page *forummodels.MessagePage
 
 	if topicID != "" {
-		messages, err = h.service.GetTopicMessages(topicID)
+		page, err = h.service.GetTopicMessages(communityID, topicID, viewerID, params)
 	} else {
-		messages, err = h.service.GetChatMessages()
+		page, err = h.service.GetChatMessages(communityID, viewerID, params)
 	}
 
+	if writePageError(w, err) {
+		return
+	}
 	if err != nil {
 		logger.Log.Error("Failed to get messages", zap.Error(err))
 		http.Error(w, "Failed to get messages", http.StatusInternalServerError)
 		return
 	}
 
+	messages := page.Items
+	if messages == nil {
+		messages = []*forummodels.MessageDetail{}
+	}
+
+	writePageHeaders(w, r, page.NextCursor)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(messages)
 }
 
+// @Summary Ответить в теме
+// @Description Публикация ответа в теме или ответа на сообщение (parent_id). Ответ сразу рассылается слушателям темы через WebSocket /topics/{id}/ws.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID темы"
+// @Param input body forummodels.MessageRequest true "Текст ответа"
+// @Success 201 {object} forummodels.ThreadMessage
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/messages [post]
+func (h *ForumHandler) CreateTopicMessage(w http.ResponseWriter, r *http.Request) {
+	var req forummodels.MessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log.Error("Failed to decode request", zap.Error(err))
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	message, err := h.service.CreateTopicMessage(communityIDFromContext(r.Context()), r.PathValue("id"), userID, req)
+	if err != nil {
+		writeThreadError(w, err, "Failed to create message")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(message)
+}
+
+// ValidationErrorResponse модель ответа с ошибками валидации полей
+type ValidationErrorResponse struct {
+	Message string                 `json:"message" example:"Validation failed"`
+	Errors  []validator.FieldError `json:"errors"`
+}
+
+func writeValidationError(w http.ResponseWriter, err error) {
+	var fieldErrors validator.Errors
+	if !errors.As(err, &fieldErrors) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(ValidationErrorResponse{
+		Message: "Validation failed",
+		Errors:  fieldErrors,
+	})
+}
+
 // func TestForumHandler_GetTopics(t *testing.T) {
 // 	mockSvc := &mocks.ForumService{}
 // 	mockSvc.On("GetTopics").Return([]*models.Topic{{ID: "1", Title: "Test"}}, nil)
@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/service"
 	"github.com/luckermt/forum-app/forum-service/internal/service/mocks"
 	"github.com/luckermt/forum-app/shared/pkg/models"
 	"github.com/stretchr/testify/assert"
@@ -12,8 +16,8 @@ import (
 
 func TestForumHandler_GetTopics(t *testing.T) {
 	mockSvc := new(mocks.ForumService)
-	mockSvc.On("GetTopics").Return([]*models.Topic{
-		{ID: "1", Title: "Test Topic"},
+	mockSvc.On("GetTopics", service.DefaultCommunityID, forummodels.TopicFilter{}, forummodels.PageParams{}).Return(&forummodels.TopicPage{
+		Items: []*forummodels.TopicSummary{{Topic: models.Topic{ID: "1", Title: "Test Topic"}}},
 	}, nil)
 
 	handler := NewForumHandler(mockSvc)
@@ -25,3 +29,344 @@ func TestForumHandler_GetTopics(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	mockSvc.AssertExpectations(t)
 }
+
+func TestCommunityMiddleware_PathPrefix(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+	mockSvc.On("ResolveCommunity", "forum.example.com", "golang").Return("community-1", nil)
+	mockSvc.On("GetTopics", "community-1", forummodels.TopicFilter{}, forummodels.PageParams{}).Return(&forummodels.TopicPage{}, nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /topics", NewForumHandler(mockSvc).GetTopics)
+
+	req := httptest.NewRequest("GET", "http://forum.example.com:8081/c/golang/topics", nil)
+	w := httptest.NewRecorder()
+
+	CommunityMiddleware(mockSvc, mux).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestRouter_UpdateTopic_Authenticated(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+	title := "Новый заголовок"
+	mockSvc.On("ResolveCommunity", "example.com", "").Return(service.DefaultCommunityID, nil)
+	mockSvc.On("ValidateUser", "valid-token", service.ScopeWrite).Return("user-1", nil)
+	mockSvc.On("UpdateTopic", service.DefaultCommunityID, "topic-1", "user-1", forummodels.TopicUpdateRequest{Title: &title}).
+		Return(&forummodels.TopicDetail{Topic: models.Topic{ID: "topic-1", Title: title}}, nil)
+
+	router := CommunityMiddleware(mockSvc, AuthMiddleware(mockSvc, NewRouter(mockSvc)))
+	req := httptest.NewRequest("PATCH", "/topics/topic-1", strings.NewReader(`{"title":"Новый заголовок"}`))
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestRouter_UpdateTopic_Unauthorized(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+	mockSvc.On("ResolveCommunity", "example.com", "").Return(service.DefaultCommunityID, nil)
+	mockSvc.On("ValidateUser", "expired-token", service.ScopeWrite).Return("", errors.New("invalid token"))
+
+	router := CommunityMiddleware(mockSvc, AuthMiddleware(mockSvc, NewRouter(mockSvc)))
+	for _, header := range []string{"", "Bearer expired-token"} {
+		req := httptest.NewRequest("PATCH", "/topics/topic-1", strings.NewReader(`{"title":"Новый заголовок"}`))
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+	mockSvc.AssertNotCalled(t, "UpdateTopic")
+}
+
+func TestRouter_UpdateTopic_InsufficientScope(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+	mockSvc.On("ResolveCommunity", "example.com", "").Return(service.DefaultCommunityID, nil)
+	mockSvc.On("ValidateUser", "read-only-token", service.ScopeWrite).Return("", service.ErrInsufficientScope)
+
+	router := CommunityMiddleware(mockSvc, AuthMiddleware(mockSvc, NewRouter(mockSvc)))
+	req := httptest.NewRequest("PATCH", "/topics/topic-1", strings.NewReader(`{"title":"Новый заголовок"}`))
+	req.Header.Set("Authorization", "Bearer read-only-token")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	mockSvc.AssertNotCalled(t, "UpdateTopic")
+}
+
+func TestCommunityMiddleware_UnknownCommunity(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+	mockSvc.On("ResolveCommunity", "example.com", "missing").Return("", service.ErrNotFound)
+
+	req := httptest.NewRequest("GET", "http://example.com/c/missing/topics", nil)
+	w := httptest.NewRecorder()
+
+	CommunityMiddleware(mockSvc, http.NotFoundHandler()).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestForumHandler_DiffTopicRevisions_InvalidRange(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /topics/{id}/diff", NewForumHandler(mockSvc).DiffTopicRevisions)
+
+	req := httptest.NewRequest("GET", "/topics/1/diff?from=abc&to=2", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "DiffTopicRevisions")
+}
+
+func TestForumHandler_DiffTopicRevisions_TooLarge(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+	mockSvc.On("DiffTopicRevisions", service.DefaultCommunityID, "1", 1, 2).Return(nil, service.ErrDiffTooLarge)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /topics/{id}/diff", NewForumHandler(mockSvc).DiffTopicRevisions)
+
+	req := httptest.NewRequest("GET", "/topics/1/diff?from=1&to=2", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestForumHandler_GetTopic_NotFound(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+	mockSvc.On("GetTopic", service.DefaultCommunityID, "missing").Return(nil, service.ErrNotFound)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /topics/{id}", NewForumHandler(mockSvc).GetTopic)
+
+	req := httptest.NewRequest("GET", "/topics/missing", nil)
+	w := httptest.NewRecorder()
+
+	mux.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestForumHandler_GetTopics_NextPageLink(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+	params := forummodels.PageParams{Sort: "active", Limit: 1}
+	mockSvc.On("GetTopics", service.DefaultCommunityID, forummodels.TopicFilter{}, params).Return(&forummodels.TopicPage{
+		Items:      []*forummodels.TopicSummary{{Topic: models.Topic{ID: "1"}, MessageCount: 3}},
+		NextCursor: "next",
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/topics?sort=active&limit=1", nil)
+	w := httptest.NewRecorder()
+
+	NewForumHandler(mockSvc).GetTopics(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "next", w.Header().Get("X-Next-Cursor"))
+	assert.Equal(t, `<?cursor=next&limit=1&sort=active>; rel="next"`, w.Header().Get("Link"))
+	mockSvc.AssertExpectations(t)
+}
+
+func TestForumHandler_GetTopics_InvalidLimit(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+
+	req := httptest.NewRequest("GET", "/topics?limit=-5", nil)
+	w := httptest.NewRecorder()
+
+	NewForumHandler(mockSvc).GetTopics(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "GetTopics")
+}
+
+func TestForumHandler_SetTagSettings_ZeroMaxTags(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+
+	req := httptest.NewRequest("PUT", "/tags/settings", strings.NewReader(`{"restricted":true,"max_tags":0}`))
+	w := httptest.NewRecorder()
+
+	NewForumHandler(mockSvc).SetTagSettings(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "SetTagSettings")
+}
+
+func TestForumHandler_CreateTopicMessage_EmptyContent(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+
+	req := httptest.NewRequest("POST", "/topics/topic-1/messages", strings.NewReader(`{"content":""}`))
+	req.SetPathValue("id", "topic-1")
+	w := httptest.NewRecorder()
+
+	NewForumHandler(mockSvc).CreateTopicMessage(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "content")
+	mockSvc.AssertNotCalled(t, "CreateTopicMessage")
+}
+
+func TestForumHandler_GetMessageRevisions_NotFound(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+	mockSvc.On("GetMessageRevisions", service.DefaultCommunityID, "missing").Return(nil, service.ErrNotFound)
+
+	req := httptest.NewRequest("GET", "/messages/missing/revisions", nil)
+	req.SetPathValue("id", "missing")
+	w := httptest.NewRecorder()
+
+	NewForumHandler(mockSvc).GetMessageRevisions(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestForumHandler_GetMessages_Reactions(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+	mockSvc.On("GetChatMessages", service.DefaultCommunityID, "", forummodels.PageParams{}).Return(&forummodels.MessagePage{
+		Items: []*forummodels.MessageDetail{{
+			Message:   models.Message{ID: "m1", Content: "hi", IsChat: true},
+			Reactions: []*forummodels.ReactionCount{{Emoji: "+1", Count: 2}},
+		}},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/messages", nil)
+	w := httptest.NewRecorder()
+
+	NewForumHandler(mockSvc).GetMessages(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"reactions":[{"emoji":"+1","count":2,"reacted_by_me":false}]`)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestForumHandler_VoteTopic_InvalidValue(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+
+	req := httptest.NewRequest("PUT", "/topics/topic-1/vote", strings.NewReader(`{"value":2}`))
+	req.SetPathValue("id", "topic-1")
+	w := httptest.NewRecorder()
+
+	NewForumHandler(mockSvc).VoteTopic(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "Vote")
+}
+
+func TestForumHandler_GetNotifications_Unauthorized(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+
+	req := httptest.NewRequest("GET", "/notifications?unread=true", nil)
+	w := httptest.NewRecorder()
+
+	NewForumHandler(mockSvc).GetNotifications(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockSvc.AssertNotCalled(t, "GetNotifications")
+}
+
+func TestForumHandler_SetTopicWatch_InvalidLevel(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+
+	req := httptest.NewRequest("PUT", "/topics/topic-1/watch", strings.NewReader(`{"level":"loud"}`))
+	req.SetPathValue("id", "topic-1")
+	w := httptest.NewRecorder()
+
+	NewForumHandler(mockSvc).SetTopicWatch(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "SetWatch")
+}
+
+func TestForumHandler_CreateConversation_NoMembers(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+
+	req := httptest.NewRequest("POST", "/conversations", strings.NewReader(`{"user_ids":[]}`))
+	w := httptest.NewRecorder()
+
+	NewForumHandler(mockSvc).CreateConversation(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "CreateConversation")
+}
+
+func TestForumHandler_UploadAttachment_Unauthorized(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+
+	req := httptest.NewRequest("POST", "/attachments", strings.NewReader("data"))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	w := httptest.NewRecorder()
+
+	NewForumHandler(mockSvc).UploadAttachment(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockSvc.AssertNotCalled(t, "UploadAttachment")
+}
+
+func TestForumHandler_CreateTopicMessage_TooManyAttachments(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+
+	ids := `"a","b","c","d","e","f","g","h","i","j","k"`
+	req := httptest.NewRequest("POST", "/topics/topic-1/messages",
+		strings.NewReader(`{"content":"скриншоты","attachment_ids":[`+ids+`]}`))
+	req.SetPathValue("id", "topic-1")
+	w := httptest.NewRecorder()
+
+	NewForumHandler(mockSvc).CreateTopicMessage(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "CreateTopicMessage")
+}
+
+func TestForumHandler_VotePoll_NoOptions(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+
+	req := httptest.NewRequest("POST", "/topics/topic-1/poll/vote", strings.NewReader(`{"option_ids":[]}`))
+	req.SetPathValue("id", "topic-1")
+	w := httptest.NewRecorder()
+
+	NewForumHandler(mockSvc).VotePoll(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockSvc.AssertNotCalled(t, "VotePoll")
+}
+
+func TestForumHandler_GetPoll_NotFound(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+	mockSvc.On("GetPoll", service.DefaultCommunityID, "topic-1", "").Return(nil, service.ErrNotFound)
+
+	req := httptest.NewRequest("GET", "/topics/topic-1/poll", nil)
+	req.SetPathValue("id", "topic-1")
+	w := httptest.NewRecorder()
+
+	NewForumHandler(mockSvc).GetPoll(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockSvc.AssertExpectations(t)
+}
+
+func TestForumHandler_GetTopicBacklinks_NotFound(t *testing.T) {
+	mockSvc := new(mocks.ForumService)
+	mockSvc.On("GetTopicBacklinks", service.DefaultCommunityID, "topic-1").Return(nil, service.ErrNotFound)
+
+	req := httptest.NewRequest("GET", "/topics/topic-1/backlinks", nil)
+	req.SetPathValue("id", "topic-1")
+	w := httptest.NewRecorder()
+
+	NewForumHandler(mockSvc).GetTopicBacklinks(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockSvc.AssertExpectations(t)
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/utils"
+	"github.com/luckermt/forum-app/shared/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// @Summary Редактировать сообщение
+// @Description Правка текста сообщения автором (в пределах окна редактирования) или модератором. Прежняя версия сохраняется в истории.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID сообщения"
+// @Param input body forummodels.MessageUpdateRequest true "Новый текст"
+// @Success 200 {object} forummodels.MessageDetail
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /messages/{id} [patch]
+func (h *ForumHandler) UpdateMessage(w http.ResponseWriter, r *http.Request) {
+	var req forummodels.MessageUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log.Error("Failed to decode request", zap.Error(err))
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	message, err := h.service.UpdateMessage(communityIDFromContext(r.Context()), r.PathValue("id"), userID, req)
+	if err != nil {
+		writeMessageError(w, err, "Failed to update message")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(message)
+}
+
+// @Summary Удалить сообщение
+// @Description Удаление сообщения автором (в пределах окна редактирования) или модератором. В списках остается заглушка.
+// @Tags messages
+// @Security ApiKeyAuth
+// @Param id path string true "ID сообщения"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /messages/{id} [delete]
+func (h *ForumHandler) DeleteMessage(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteMessage(communityIDFromContext(r.Context()), r.PathValue("id"), userID); err != nil {
+		writeMessageError(w, err, "Failed to delete message")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary История правок сообщения
+// @Description Прежние версии сообщения от старых к новым
+// @Tags messages
+// @Produce json
+// @Param id path string true "ID сообщения"
+// @Success 200 {array} forummodels.MessageRevision
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /messages/{id}/revisions [get]
+func (h *ForumHandler) GetMessageRevisions(w http.ResponseWriter, r *http.Request) {
+	revisions, err := h.service.GetMessageRevisions(communityIDFromContext(r.Context()), r.PathValue("id"))
+	if err != nil {
+		writeMessageError(w, err, "Failed to get revisions")
+		return
+	}
+	if revisions == nil {
+		revisions = []*forummodels.MessageRevision{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// writeMessageError дополняет writeTopicError ошибками правки сообщений
+func writeMessageError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, service.ErrEditWindowClosed) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	writeTopicError(w, err, message)
+}
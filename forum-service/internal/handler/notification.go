@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/utils"
+	"github.com/luckermt/forum-app/shared/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// @Summary Получить уведомления
+// @Description Уведомления текущего пользователя в сообществе, новые первыми: упоминания, ответы в его темах и на его сообщения, действия модераторов. Новые уведомления также приходят в открытые WebSocket-соединения пользователя. Курсор следующей страницы возвращается в заголовках Link и X-Next-Cursor.
+// @Tags notifications
+// @Produce json
+// @Security ApiKeyAuth
+// @Param unread query bool false "Только непрочитанные"
+// @Param limit query int false "Размер страницы (по умолчанию 20, максимум 100)"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Success 200 {array} forummodels.Notification
+// @Header 200 {string} Link "Ссылка на следующую страницу"
+// @Header 200 {string} X-Next-Cursor "Курсор следующей страницы"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /notifications [get]
+func (h *ForumHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	params, err := pageParams(r)
+	if writePageError(w, err) {
+		return
+	}
+
+	var unreadOnly bool
+	if unread := r.URL.Query().Get("unread"); unread != "" {
+		unreadOnly, err = strconv.ParseBool(unread)
+		if err != nil {
+			http.Error(w, "Invalid unread", http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := h.service.GetNotifications(communityIDFromContext(r.Context()), userID, unreadOnly, params)
+	if writePageError(w, err) {
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to get notifications", zap.Error(err))
+		http.Error(w, "Failed to get notifications", http.StatusInternalServerError)
+		return
+	}
+
+	notifications := page.Items
+	if notifications == nil {
+		notifications = []*forummodels.Notification{}
+	}
+
+	writePageHeaders(w, r, page.NextCursor)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notifications)
+}
+
+// @Summary Число непрочитанных уведомлений
+// @Tags notifications
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} forummodels.UnreadCount
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /notifications/unread_count [get]
+func (h *ForumHandler) GetUnreadNotificationCount(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	unread, err := h.service.GetUnreadNotificationCount(communityIDFromContext(r.Context()), userID)
+	if err != nil {
+		http.Error(w, "Failed to count notifications", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(forummodels.UnreadCount{Unread: unread})
+}
+
+// @Summary Отметить уведомление прочитанным
+// @Tags notifications
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID уведомления"
+// @Success 200 {object} forummodels.UnreadCount
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /notifications/{id}/read [post]
+func (h *ForumHandler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	unread, err := h.service.MarkNotificationRead(communityIDFromContext(r.Context()), userID, r.PathValue("id"))
+	if errors.Is(err, service.ErrNotFound) {
+		http.Error(w, "Notification not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to mark notification read", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(forummodels.UnreadCount{Unread: unread})
+}
+
+// @Summary Отметить уведомления прочитанными
+// @Description Отмечает прочитанными уведомления из списка ids, а без тела запроса или с пустым списком — все уведомления пользователя
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param input body forummodels.NotificationReadRequest false "ID уведомлений"
+// @Success 200 {object} forummodels.UnreadCount
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /notifications/read [post]
+func (h *ForumHandler) MarkNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	var req forummodels.NotificationReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		logger.Log.Error("Failed to decode request", zap.Error(err))
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	unread, err := h.service.MarkNotificationsRead(communityIDFromContext(r.Context()), userID, req.IDs)
+	if err != nil {
+		http.Error(w, "Failed to mark notifications read", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(forummodels.UnreadCount{Unread: unread})
+}
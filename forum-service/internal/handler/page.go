@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+)
+
+var errInvalidLimit = errors.New("limit must be a positive number")
+
+// pageParams читает параметры cursor, limit и sort из строки запроса
+func pageParams(r *http.Request) (forummodels.PageParams, error) {
+	q := r.URL.Query()
+	params := forummodels.PageParams{
+		Sort:   q.Get("sort"),
+		Cursor: q.Get("cursor"),
+	}
+	if limit := q.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return params, errInvalidLimit
+		}
+		params.Limit = n
+	}
+	return params, nil
+}
+
+// writePageHeaders добавляет ссылку на следующую страницу в заголовки Link и X-Next-Cursor.
+// Ссылка содержит только строку запроса, поэтому сохраняет префикс сообщества в пути.
+func writePageHeaders(w http.ResponseWriter, r *http.Request, nextCursor string) {
+	if nextCursor == "" {
+		return
+	}
+	q := r.URL.Query()
+	q.Set("cursor", nextCursor)
+	w.Header().Set("Link", "<?"+q.Encode()+`>; rel="next"`)
+	w.Header().Set("X-Next-Cursor", nextCursor)
+}
+
+// writePageError отвечает 400 на некорректные параметры страницы
+func writePageError(w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, errInvalidLimit), errors.Is(err, service.ErrInvalidCursor), errors.Is(err, service.ErrInvalidSort):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/utils"
+	"github.com/luckermt/forum-app/shared/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// @Summary Опрос темы
+// @Description Результаты опроса и варианты, выбранные текущим пользователем. Если автор скрыл результаты, счетчики появляются после закрытия опроса.
+// @Tags polls
+// @Produce json
+// @Param id path string true "ID темы"
+// @Success 200 {object} forummodels.Poll
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/poll [get]
+func (h *ForumHandler) GetPoll(w http.ResponseWriter, r *http.Request) {
+	viewerID, _ := utils.GetUserIDFromContext(r.Context())
+	poll, err := h.service.GetPoll(communityIDFromContext(r.Context()), r.PathValue("id"), viewerID)
+	if err != nil {
+		writePollError(w, err, "Failed to get poll")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(poll)
+}
+
+// @Summary Проголосовать в опросе
+// @Description Голос текущего пользователя. В опросе с единственным выбором передается один вариант. Проголосовать можно один раз, изменить голос — через PUT.
+// @Tags polls
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID темы"
+// @Param input body forummodels.PollVoteRequest true "Выбранные варианты"
+// @Success 200 {object} forummodels.Poll
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/poll/vote [post]
+func (h *ForumHandler) VotePoll(w http.ResponseWriter, r *http.Request) {
+	h.changePollVote(w, r, h.service.VotePoll)
+}
+
+// @Summary Изменить голос в опросе
+// @Tags polls
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID темы"
+// @Param input body forummodels.PollVoteRequest true "Выбранные варианты"
+// @Success 200 {object} forummodels.Poll
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/poll/vote [put]
+func (h *ForumHandler) ChangePollVote(w http.ResponseWriter, r *http.Request) {
+	h.changePollVote(w, r, h.service.ChangePollVote)
+}
+
+// @Summary Отозвать голос в опросе
+// @Tags polls
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID темы"
+// @Success 200 {object} forummodels.Poll
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/poll/vote [delete]
+func (h *ForumHandler) RetractPollVote(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	poll, err := h.service.RetractPollVote(communityIDFromContext(r.Context()), r.PathValue("id"), userID)
+	if err != nil {
+		writePollError(w, err, "Failed to retract poll vote")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(poll)
+}
+
+type pollVote func(communityID, topicID, userID string, optionIDs []string) (*forummodels.Poll, error)
+
+func (h *ForumHandler) changePollVote(w http.ResponseWriter, r *http.Request, vote pollVote) {
+	var req forummodels.PollVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log.Error("Failed to decode request", zap.Error(err))
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	if err := validator.Validate(&req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	poll, err := vote(communityIDFromContext(r.Context()), r.PathValue("id"), userID, req.OptionIDs)
+	if err != nil {
+		writePollError(w, err, "Failed to vote in poll")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(poll)
+}
+
+// writePollError дополняет writeTopicError ошибками опросов
+func writePollError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidPollVote):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrPollClosed), errors.Is(err, service.ErrAlreadyVoted):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeTopicError(w, err, message)
+	}
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/utils"
+)
+
+// @Summary Поставить реакцию на тему
+// @Description Добавление реакции текущего пользователя. Допустимые реакции: +1, -1, heart, laugh, hooray, confused, eyes, rocket.
+// @Tags reactions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID темы"
+// @Param emoji path string true "Реакция"
+// @Success 200 {array} forummodels.ReactionCount
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/reactions/{emoji} [put]
+func (h *ForumHandler) AddTopicReaction(w http.ResponseWriter, r *http.Request) {
+	h.changeReaction(w, r, forummodels.ReactionTargetTopic, h.service.AddReaction)
+}
+
+// @Summary Снять реакцию с темы
+// @Tags reactions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID темы"
+// @Param emoji path string true "Реакция"
+// @Success 200 {array} forummodels.ReactionCount
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/reactions/{emoji} [delete]
+func (h *ForumHandler) RemoveTopicReaction(w http.ResponseWriter, r *http.Request) {
+	h.changeReaction(w, r, forummodels.ReactionTargetTopic, h.service.RemoveReaction)
+}
+
+// @Summary Реакции на тему
+// @Tags reactions
+// @Produce json
+// @Param id path string true "ID темы"
+// @Success 200 {array} forummodels.ReactionCount
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/reactions [get]
+func (h *ForumHandler) GetTopicReactions(w http.ResponseWriter, r *http.Request) {
+	h.getReactions(w, r, forummodels.ReactionTargetTopic)
+}
+
+// @Summary Поставить реакцию на сообщение
+// @Description Добавление реакции текущего пользователя. Допустимые реакции: +1, -1, heart, laugh, hooray, confused, eyes, rocket.
+// @Tags reactions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID сообщения"
+// @Param emoji path string true "Реакция"
+// @Success 200 {array} forummodels.ReactionCount
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /messages/{id}/reactions/{emoji} [put]
+func (h *ForumHandler) AddMessageReaction(w http.ResponseWriter, r *http.Request) {
+	h.changeReaction(w, r, forummodels.ReactionTargetMessage, h.service.AddReaction)
+}
+
+// @Summary Снять реакцию с сообщения
+// @Tags reactions
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID сообщения"
+// @Param emoji path string true "Реакция"
+// @Success 200 {array} forummodels.ReactionCount
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /messages/{id}/reactions/{emoji} [delete]
+func (h *ForumHandler) RemoveMessageReaction(w http.ResponseWriter, r *http.Request) {
+	h.changeReaction(w, r, forummodels.ReactionTargetMessage, h.service.RemoveReaction)
+}
+
+// @Summary Реакции на сообщение
+// @Tags reactions
+// @Produce json
+// @Param id path string true "ID сообщения"
+// @Success 200 {array} forummodels.ReactionCount
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /messages/{id}/reactions [get]
+func (h *ForumHandler) GetMessageReactions(w http.ResponseWriter, r *http.Request) {
+	h.getReactions(w, r, forummodels.ReactionTargetMessage)
+}
+
+type reactionChange func(communityID, targetType, targetID, userID, emoji string) ([]*forummodels.ReactionCount, error)
+
+func (h *ForumHandler) changeReaction(w http.ResponseWriter, r *http.Request, targetType string, change reactionChange) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	reactions, err := change(communityIDFromContext(r.Context()), targetType, r.PathValue("id"), userID, r.PathValue("emoji"))
+	if err != nil {
+		writeReactionError(w, err, "Failed to change reaction")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reactions)
+}
+
+func (h *ForumHandler) getReactions(w http.ResponseWriter, r *http.Request, targetType string) {
+	viewerID, _ := utils.GetUserIDFromContext(r.Context())
+	reactions, err := h.service.GetReactions(communityIDFromContext(r.Context()), targetType, r.PathValue("id"), viewerID)
+	if err != nil {
+		writeReactionError(w, err, "Failed to get reactions")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reactions)
+}
+
+// writeReactionError дополняет writeTopicError ошибками реакций
+func writeReactionError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, service.ErrInvalidReaction) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeTopicError(w, err, message)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+)
+
+// NewRouter регистрирует все HTTP маршруты форума
+func NewRouter(forumService service.ForumService) *http.ServeMux {
+	mux := http.NewServeMux()
+	forumHandler := NewForumHandler(forumService)
+	chatHandler := NewChatHandler(forumService)
+
+	mux.HandleFunc("POST /topics", forumHandler.CreateTopic)
+	mux.HandleFunc("DELETE /topics/{id}", forumHandler.DeleteTopic)
+	mux.HandleFunc("GET /topics", forumHandler.GetTopics)
+	mux.HandleFunc("GET /topics/{id}", forumHandler.GetTopic)
+	mux.HandleFunc("PATCH /topics/{id}", forumHandler.UpdateTopic)
+	mux.HandleFunc("GET /topics/{id}/revisions", forumHandler.GetTopicRevisions)
+	mux.HandleFunc("GET /topics/{id}/backlinks", forumHandler.GetTopicBacklinks)
+	mux.HandleFunc("PUT /topics/{id}/state", forumHandler.SetTopicState)
+	mux.HandleFunc("GET /topics/{id}/diff", forumHandler.DiffTopicRevisions)
+	mux.HandleFunc("GET /categories", forumHandler.GetCategories)
+	mux.HandleFunc("POST /categories", forumHandler.CreateCategory)
+	mux.HandleFunc("PUT /categories/{id}", forumHandler.UpdateCategory)
+	mux.HandleFunc("DELETE /categories/{id}", forumHandler.DeleteCategory)
+	mux.HandleFunc("GET /tags", forumHandler.SearchTags)
+	mux.HandleFunc("POST /tags", forumHandler.CreateTag)
+	mux.HandleFunc("GET /tags/settings", forumHandler.GetTagSettings)
+	mux.HandleFunc("PUT /tags/settings", forumHandler.SetTagSettings)
+	mux.HandleFunc("DELETE /tags/{name}", forumHandler.DeleteTag)
+	mux.HandleFunc("POST /tags/{name}/merge", forumHandler.MergeTag)
+	mux.HandleFunc("POST /topics/{id}/messages", forumHandler.CreateTopicMessage)
+	mux.HandleFunc("GET /topics/{id}/thread", forumHandler.GetThread)
+	mux.HandleFunc("GET /topics/{id}/ws", chatHandler.HandleTopicConnections)
+	mux.HandleFunc("GET /messages", forumHandler.GetMessages)
+	mux.HandleFunc("PATCH /messages/{id}", forumHandler.UpdateMessage)
+	mux.HandleFunc("DELETE /messages/{id}", forumHandler.DeleteMessage)
+	mux.HandleFunc("GET /messages/{id}/revisions", forumHandler.GetMessageRevisions)
+	mux.HandleFunc("GET /messages/{id}/reactions", forumHandler.GetMessageReactions)
+	mux.HandleFunc("PUT /messages/{id}/reactions/{emoji}", forumHandler.AddMessageReaction)
+	mux.HandleFunc("DELETE /messages/{id}/reactions/{emoji}", forumHandler.RemoveMessageReaction)
+	mux.HandleFunc("GET /topics/{id}/reactions", forumHandler.GetTopicReactions)
+	mux.HandleFunc("PUT /topics/{id}/vote", forumHandler.VoteTopic)
+	mux.HandleFunc("PUT /messages/{id}/vote", forumHandler.VoteMessage)
+	mux.HandleFunc("PUT /topics/{id}/reactions/{emoji}", forumHandler.AddTopicReaction)
+	mux.HandleFunc("DELETE /topics/{id}/reactions/{emoji}", forumHandler.RemoveTopicReaction)
+	mux.HandleFunc("GET /topics/{id}/watch", forumHandler.GetTopicWatch)
+	mux.HandleFunc("PUT /topics/{id}/watch", forumHandler.SetTopicWatch)
+	mux.HandleFunc("DELETE /topics/{id}/watch", forumHandler.RemoveTopicWatch)
+	mux.HandleFunc("PUT /categories/{id}/watch", forumHandler.SetCategoryWatch)
+	mux.HandleFunc("DELETE /categories/{id}/watch", forumHandler.RemoveCategoryWatch)
+	mux.HandleFunc("POST /conversations", forumHandler.CreateConversation)
+	mux.HandleFunc("GET /conversations", forumHandler.GetConversations)
+	mux.HandleFunc("GET /conversations/{id}", forumHandler.GetConversation)
+	mux.HandleFunc("GET /conversations/{id}/messages", forumHandler.GetDirectMessages)
+	mux.HandleFunc("POST /conversations/{id}/messages", forumHandler.SendDirectMessage)
+	mux.HandleFunc("POST /conversations/{id}/read", forumHandler.MarkConversationRead)
+	mux.HandleFunc("GET /blocks", forumHandler.GetBlockedUsers)
+	mux.HandleFunc("PUT /blocks/{user_id}", forumHandler.BlockUser)
+	mux.HandleFunc("DELETE /blocks/{user_id}", forumHandler.UnblockUser)
+	mux.HandleFunc("POST /attachments", forumHandler.UploadAttachment)
+	mux.HandleFunc("GET /attachments/{id}", forumHandler.DownloadAttachment)
+	mux.HandleFunc("GET /topics/{id}/poll", forumHandler.GetPoll)
+	mux.HandleFunc("POST /topics/{id}/poll/vote", forumHandler.VotePoll)
+	mux.HandleFunc("PUT /topics/{id}/poll/vote", forumHandler.ChangePollVote)
+	mux.HandleFunc("DELETE /topics/{id}/poll/vote", forumHandler.RetractPollVote)
+	mux.HandleFunc("GET /search", forumHandler.Search)
+	mux.HandleFunc("GET /notifications", forumHandler.GetNotifications)
+	mux.HandleFunc("GET /notifications/unread_count", forumHandler.GetUnreadNotificationCount)
+	mux.HandleFunc("POST /notifications/read", forumHandler.MarkNotificationsRead)
+	mux.HandleFunc("POST /notifications/{id}/read", forumHandler.MarkNotificationRead)
+	mux.HandleFunc("/ws", chatHandler.HandleConnections)
+
+	return mux
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// @Summary Поиск
+// @Description Полнотекстовый поиск по темам и сообщениям сообщества с ранжированием и подсветкой совпадений
+// @Tags search
+// @Produce json
+// @Param q query string true "Поисковый запрос (поддерживаются кавычки, OR и -слово)"
+// @Param type query string false "Где искать" Enums(all, topics, messages)
+// @Param author query string false "ID автора"
+// @Param topic_id query string false "ID темы"
+// @Param from query string false "Начало периода (RFC3339 или YYYY-MM-DD)"
+// @Param to query string false "Конец периода, не включительно (RFC3339 или YYYY-MM-DD)"
+// @Param lang query string false "Язык запроса" Enums(simple, english, russian)
+// @Param limit query int false "Количество результатов (по умолчанию 20, максимум 100)"
+// @Param offset query int false "Смещение"
+// @Success 200 {array} forummodels.SearchResult
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /search [get]
+func (h *ForumHandler) Search(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	q := forummodels.SearchQuery{
+		Query:    params.Get("q"),
+		Type:     params.Get("type"),
+		AuthorID: params.Get("author"),
+		TopicID:  params.Get("topic_id"),
+		Language: params.Get("lang"),
+	}
+
+	var err error
+	if q.From, err = parseSearchTime(params.Get("from")); err != nil {
+		http.Error(w, "Invalid from date", http.StatusBadRequest)
+		return
+	}
+	if q.To, err = parseSearchTime(params.Get("to")); err != nil {
+		http.Error(w, "Invalid to date", http.StatusBadRequest)
+		return
+	}
+	if q.Limit, err = parseOptionalInt(params.Get("limit")); err != nil {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+	if q.Offset, err = parseOptionalInt(params.Get("offset")); err != nil {
+		http.Error(w, "Invalid offset", http.StatusBadRequest)
+		return
+	}
+
+	results, err := h.service.Search(communityIDFromContext(r.Context()), q)
+	if errors.Is(err, service.ErrInvalidSearch) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		logger.Log.Error("Failed to search", zap.Error(err))
+		http.Error(w, "Failed to search", http.StatusInternalServerError)
+		return
+	}
+	if results == nil {
+		results = []*forummodels.SearchResult{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// parseSearchTime принимает RFC3339 или дату YYYY-MM-DD
+func parseSearchTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
+
+func parseOptionalInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, errors.New("must be a non-negative number")
+	}
+	return n, nil
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/utils"
+	"github.com/luckermt/forum-app/shared/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// @Summary Поиск тегов
+// @Description Автодополнение тегов по префиксу с количеством тем. Без префикса возвращает популярные теги.
+// @Tags tags
+// @Produce json
+// @Param prefix query string false "Начало тега"
+// @Param limit query int false "Количество подсказок (максимум 50)"
+// @Success 200 {array} forummodels.Tag
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tags [get]
+func (h *ForumHandler) SearchTags(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseOptionalInt(r.URL.Query().Get("limit"))
+	if err != nil {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	tags, err := h.service.SearchTags(communityIDFromContext(r.Context()), r.URL.Query().Get("prefix"), limit)
+	if err != nil {
+		writeTagError(w, err, "Failed to search tags")
+		return
+	}
+	if tags == nil {
+		tags = []*forummodels.Tag{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tags)
+}
+
+// @Summary Правила тегов
+// @Description Ограничен ли список тегов и сколько тегов можно указать у темы
+// @Tags tags
+// @Produce json
+// @Success 200 {object} forummodels.TagSettings
+// @Failure 500 {object} map[string]string
+// @Router /tags/settings [get]
+func (h *ForumHandler) GetTagSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.service.GetTagSettings(communityIDFromContext(r.Context()))
+	if err != nil {
+		writeTagError(w, err, "Failed to get tag settings")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// @Summary Изменить правила тегов
+// @Description Только для администраторов сообщества
+// @Tags tags
+// @Accept json
+// @Security ApiKeyAuth
+// @Param input body forummodels.TagSettings true "Правила тегов"
+// @Success 204
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tags/settings [put]
+func (h *ForumHandler) SetTagSettings(w http.ResponseWriter, r *http.Request) {
+	var req forummodels.TagSettings
+	userID, ok := decodeTagRequest(w, r, &req)
+	if !ok {
+		return
+	}
+
+	if err := h.service.SetTagSettings(communityIDFromContext(r.Context()), userID, req); err != nil {
+		writeTagError(w, err, "Failed to update tag settings")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Добавить разрешенный тег
+// @Description Добавляет тег в официальный список сообщества (только для администраторов)
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param input body forummodels.TagRequest true "Тег"
+// @Success 201 {object} forummodels.Tag
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tags [post]
+func (h *ForumHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	var req forummodels.TagRequest
+	userID, ok := decodeTagRequest(w, r, &req)
+	if !ok {
+		return
+	}
+
+	tag, err := h.service.CreateOfficialTag(communityIDFromContext(r.Context()), userID, req.Name)
+	if err != nil {
+		writeTagError(w, err, "Failed to create tag")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(tag)
+}
+
+// @Summary Удалить тег
+// @Description Удаляет тег, его синонимы и снимает его со всех тем (только для администраторов)
+// @Tags tags
+// @Security ApiKeyAuth
+// @Param name path string true "Тег"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tags/{name} [delete]
+func (h *ForumHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteTag(communityIDFromContext(r.Context()), userID, r.PathValue("name")); err != nil {
+		writeTagError(w, err, "Failed to delete tag")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Слить теги
+// @Description Переносит темы с тега на другой тег, исходный тег становится синонимом (только для администраторов)
+// @Tags tags
+// @Accept json
+// @Security ApiKeyAuth
+// @Param name path string true "Исходный тег"
+// @Param input body forummodels.TagMergeRequest true "Целевой тег"
+// @Success 204
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tags/{name}/merge [post]
+func (h *ForumHandler) MergeTag(w http.ResponseWriter, r *http.Request) {
+	var req forummodels.TagMergeRequest
+	userID, ok := decodeTagRequest(w, r, &req)
+	if !ok {
+		return
+	}
+
+	if err := h.service.MergeTag(communityIDFromContext(r.Context()), userID, r.PathValue("name"), req.Into); err != nil {
+		writeTagError(w, err, "Failed to merge tags")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeTagRequest(w http.ResponseWriter, r *http.Request, req interface{}) (string, bool) {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		logger.Log.Error("Failed to decode request", zap.Error(err))
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return "", false
+	}
+
+	if err := validator.Validate(req); err != nil {
+		writeValidationError(w, err)
+		return "", false
+	}
+
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return "", false
+	}
+	return userID, true
+}
+
+func writeTagError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTag),
+		errors.Is(err, service.ErrTooManyTags),
+		errors.Is(err, service.ErrTagNotAllowed),
+		errors.Is(err, service.ErrInvalidTagSettings),
+		errors.Is(err, service.ErrInvalidAttachment),
+		errors.Is(err, service.ErrImageRejected),
+		errors.Is(err, service.ErrInvalidPoll):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		writeTopicError(w, err, message)
+	}
+}
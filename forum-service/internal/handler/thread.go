@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+)
+
+// @Summary Ветка обсуждения темы
+// @Description Получение сообщений темы деревом (format=tree) или плоским списком с глубиной (format=flat).
+// @Description Страница состоит из корневых сообщений темы или прямых ответов на parent_id, каждое — вместе с вложенными ответами на depth-1 уровней.
+// @Description Более глубокие ответы загружаются отдельным запросом с parent_id, их число видно по reply_count.
+// @Tags messages
+// @Produce json
+// @Param id path string true "ID темы"
+// @Param parent_id query string false "ID сообщения, ответы на которое нужно получить"
+// @Param depth query int false "Число уровней дерева (по умолчанию 3)"
+// @Param format query string false "Формат выдачи" Enums(tree, flat)
+// @Param sort query string false "Сортировка верхнего уровня" Enums(oldest, newest, top)
+// @Param limit query int false "Размер страницы (по умолчанию 20, максимум 100)"
+// @Param cursor query string false "Курсор следующей страницы"
+// @Success 200 {array} forummodels.ThreadMessage
+// @Header 200 {string} Link "Ссылка на следующую страницу"
+// @Header 200 {string} X-Next-Cursor "Курсор следующей страницы"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/thread [get]
+func (h *ForumHandler) GetThread(w http.ResponseWriter, r *http.Request) {
+	page, err := pageParams(r)
+	if writePageError(w, err) {
+		return
+	}
+
+	q := r.URL.Query()
+	params := forummodels.ThreadParams{
+		PageParams: page,
+		ParentID:   q.Get("parent_id"),
+		Format:     q.Get("format"),
+	}
+	if depth := q.Get("depth"); depth != "" {
+		n, err := strconv.Atoi(depth)
+		if err != nil || n <= 0 {
+			http.Error(w, service.ErrInvalidThread.Error(), http.StatusBadRequest)
+			return
+		}
+		params.Levels = n
+	}
+
+	thread, err := h.service.GetThread(communityIDFromContext(r.Context()), r.PathValue("id"), params)
+	if writePageError(w, err) {
+		return
+	}
+	if err != nil {
+		writeThreadError(w, err, "Failed to get thread")
+		return
+	}
+
+	messages := thread.Items
+	if messages == nil {
+		messages = []*forummodels.ThreadMessage{}
+	}
+
+	writePageHeaders(w, r, thread.NextCursor)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(messages)
+}
+
+// writeThreadError дополняет writeTopicError ошибками веток обсуждения
+func writeThreadError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidParent),
+		errors.Is(err, service.ErrThreadTooDeep),
+		errors.Is(err, service.ErrInvalidThread),
+		errors.Is(err, service.ErrInvalidAttachment),
+		errors.Is(err, service.ErrImageRejected):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		writeTopicError(w, err, message)
+	}
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/utils"
+	"github.com/luckermt/forum-app/shared/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// @Summary Получить тему
+// @Description Получение темы по ID вместе с номером текущей ревизии
+// @Tags topics
+// @Produce json
+// @Param id path string true "ID темы"
+// @Success 200 {object} forummodels.TopicDetail
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id} [get]
+func (h *ForumHandler) GetTopic(w http.ResponseWriter, r *http.Request) {
+	topic, err := h.service.GetTopic(communityIDFromContext(r.Context()), r.PathValue("id"))
+	if err != nil {
+		writeTopicError(w, err, "Failed to get topic")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(topic)
+}
+
+// @Summary Редактировать тему
+// @Description Изменение заголовка и/или текста темы автором или модератором. Прежняя версия сохраняется в истории.
+// @Tags topics
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID темы"
+// @Param input body forummodels.TopicUpdateRequest true "Новые данные темы"
+// @Success 200 {object} forummodels.TopicDetail
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id} [patch]
+func (h *ForumHandler) UpdateTopic(w http.ResponseWriter, r *http.Request) {
+	var req forummodels.TopicUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log.Error("Failed to decode request", zap.Error(err))
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+	if (req.Title != nil && *req.Title == "") || (req.Content != nil && *req.Content == "") {
+		http.Error(w, "Title and content cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	topic, err := h.service.UpdateTopic(communityIDFromContext(r.Context()), r.PathValue("id"), userID, req)
+	if err != nil {
+		writeTopicError(w, err, "Failed to update topic")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(topic)
+}
+
+// @Summary Обратные ссылки на тему
+// @Description Темы и сообщения из других тем и чата, где процитированы или упомянуты ссылкой эта тема и ее сообщения, от новых к старым
+// @Tags topics
+// @Produce json
+// @Param id path string true "ID темы"
+// @Success 200 {array} forummodels.Backlink
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/backlinks [get]
+func (h *ForumHandler) GetTopicBacklinks(w http.ResponseWriter, r *http.Request) {
+	backlinks, err := h.service.GetTopicBacklinks(communityIDFromContext(r.Context()), r.PathValue("id"))
+	if err != nil {
+		writeTopicError(w, err, "Failed to get backlinks")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(backlinks)
+}
+
+// @Summary История ревизий темы
+// @Description Список всех версий темы, начиная с последней
+// @Tags topics
+// @Produce json
+// @Param id path string true "ID темы"
+// @Success 200 {array} forummodels.TopicRevision
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/revisions [get]
+func (h *ForumHandler) GetTopicRevisions(w http.ResponseWriter, r *http.Request) {
+	revisions, err := h.service.GetTopicRevisions(communityIDFromContext(r.Context()), r.PathValue("id"))
+	if err != nil {
+		writeTopicError(w, err, "Failed to get revisions")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// @Summary Дифф ревизий темы
+// @Description Построчная разница заголовка и текста между двумя ревизиями темы
+// @Tags topics
+// @Produce json
+// @Param id path string true "ID темы"
+// @Param from query int true "Исходная ревизия"
+// @Param to query int true "Конечная ревизия"
+// @Success 200 {object} forummodels.TopicDiff
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/diff [get]
+func (h *ForumHandler) DiffTopicRevisions(w http.ResponseWriter, r *http.Request) {
+	from, errFrom := strconv.Atoi(r.URL.Query().Get("from"))
+	to, errTo := strconv.Atoi(r.URL.Query().Get("to"))
+	if errFrom != nil || errTo != nil || from < 1 || to < 1 {
+		http.Error(w, "from and to must be revision numbers", http.StatusBadRequest)
+		return
+	}
+
+	diff, err := h.service.DiffTopicRevisions(communityIDFromContext(r.Context()), r.PathValue("id"), from, to)
+	if errors.Is(err, service.ErrDiffTooLarge) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if err != nil {
+		writeTopicError(w, err, "Failed to diff revisions")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(diff)
+}
+
+// @Summary Изменить состояние темы
+// @Description Закрепление, закрытие и архивирование темы (только для модераторов сообщества)
+// @Tags topics
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID темы"
+// @Param input body forummodels.TopicStateRequest true "Новое состояние"
+// @Success 200 {object} forummodels.TopicDetail
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/state [put]
+func (h *ForumHandler) SetTopicState(w http.ResponseWriter, r *http.Request) {
+	var req forummodels.TopicStateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log.Error("Failed to decode request", zap.Error(err))
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	topic, err := h.service.SetTopicState(communityIDFromContext(r.Context()), r.PathValue("id"), userID, req)
+	if err != nil {
+		writeTopicError(w, err, "Failed to change topic state")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(topic)
+}
+
+// writeTopicError переводит ошибки сервиса в HTTP статусы
+func writeTopicError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		http.Error(w, "Not found", http.StatusNotFound)
+	case errors.Is(err, service.ErrTopicLocked), errors.Is(err, service.ErrTopicArchived):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, service.ErrContentTooLong):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrForbidden),
+		errors.Is(err, service.ErrNotMember),
+		errors.Is(err, service.ErrUserBlocked):
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	default:
+		logger.Log.Error(message, zap.Error(err))
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/utils"
+	"github.com/luckermt/forum-app/shared/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// @Summary Проголосовать за тему
+// @Description Голос за (1) или против (-1) темы, 0 отменяет голос. Голосовать за свои темы нельзя.
+// @Tags votes
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID темы"
+// @Param input body forummodels.VoteRequest true "Голос"
+// @Success 200 {object} forummodels.VoteResult
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/vote [put]
+func (h *ForumHandler) VoteTopic(w http.ResponseWriter, r *http.Request) {
+	h.vote(w, r, forummodels.VoteTargetTopic)
+}
+
+// @Summary Проголосовать за сообщение
+// @Description Голос за (1) или против (-1) сообщения, 0 отменяет голос. Голосовать за свои сообщения нельзя.
+// @Tags votes
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID сообщения"
+// @Param input body forummodels.VoteRequest true "Голос"
+// @Success 200 {object} forummodels.VoteResult
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /messages/{id}/vote [put]
+func (h *ForumHandler) VoteMessage(w http.ResponseWriter, r *http.Request) {
+	h.vote(w, r, forummodels.VoteTargetMessage)
+}
+
+func (h *ForumHandler) vote(w http.ResponseWriter, r *http.Request, targetType string) {
+	var req forummodels.VoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log.Error("Failed to decode request", zap.Error(err))
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.service.Vote(communityIDFromContext(r.Context()), targetType, r.PathValue("id"), userID, req.Value)
+	if err != nil {
+		writeVoteError(w, err, "Failed to vote")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// writeVoteError дополняет writeTopicError ошибками голосования
+func writeVoteError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidVote):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrSelfVote):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		writeTopicError(w, err, message)
+	}
+}
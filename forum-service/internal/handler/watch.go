@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/utils"
+	"github.com/luckermt/forum-app/shared/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// @Summary Уровень слежения за темой
+// @Description Действующий уровень слежения текущего пользователя: выбранный для темы, для ее раздела или normal по умолчанию
+// @Tags watches
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID темы"
+// @Success 200 {object} forummodels.Watch
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/watch [get]
+func (h *ForumHandler) GetTopicWatch(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	watch, err := h.service.GetTopicWatch(communityIDFromContext(r.Context()), r.PathValue("id"), userID)
+	if err != nil {
+		writeWatchError(w, err, "Failed to get watch level")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(watch)
+}
+
+// @Summary Следить за темой
+// @Description watching — уведомление о каждом ответе, tracking — только событие в открытые соединения, muted — никаких уведомлений о теме
+// @Tags watches
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID темы"
+// @Param input body forummodels.WatchRequest true "Уровень слежения"
+// @Success 200 {object} forummodels.Watch
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/watch [put]
+func (h *ForumHandler) SetTopicWatch(w http.ResponseWriter, r *http.Request) {
+	h.setWatch(w, r, forummodels.WatchTargetTopic)
+}
+
+// @Summary Сбросить уровень слежения за темой
+// @Tags watches
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID темы"
+// @Success 200 {object} forummodels.Watch
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /topics/{id}/watch [delete]
+func (h *ForumHandler) RemoveTopicWatch(w http.ResponseWriter, r *http.Request) {
+	h.removeWatch(w, r, forummodels.WatchTargetTopic)
+}
+
+// @Summary Следить за разделом
+// @Description Уровень действует для тем раздела, у которых пользователь не выбрал свой
+// @Tags watches
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID раздела"
+// @Param input body forummodels.WatchRequest true "Уровень слежения"
+// @Success 200 {object} forummodels.Watch
+// @Failure 400 {object} ValidationErrorResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /categories/{id}/watch [put]
+func (h *ForumHandler) SetCategoryWatch(w http.ResponseWriter, r *http.Request) {
+	h.setWatch(w, r, forummodels.WatchTargetCategory)
+}
+
+// @Summary Сбросить уровень слежения за разделом
+// @Tags watches
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "ID раздела"
+// @Success 200 {object} forummodels.Watch
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /categories/{id}/watch [delete]
+func (h *ForumHandler) RemoveCategoryWatch(w http.ResponseWriter, r *http.Request) {
+	h.removeWatch(w, r, forummodels.WatchTargetCategory)
+}
+
+func (h *ForumHandler) setWatch(w http.ResponseWriter, r *http.Request, targetType string) {
+	var req forummodels.WatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Log.Error("Failed to decode request", zap.Error(err))
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	watch, err := h.service.SetWatch(communityIDFromContext(r.Context()), targetType, r.PathValue("id"), userID, req.Level)
+	if err != nil {
+		writeWatchError(w, err, "Failed to set watch level")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(watch)
+}
+
+func (h *ForumHandler) removeWatch(w http.ResponseWriter, r *http.Request, targetType string) {
+	userID, err := utils.GetUserIDFromContext(r.Context())
+	if err != nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	watch, err := h.service.RemoveWatch(communityIDFromContext(r.Context()), targetType, r.PathValue("id"), userID)
+	if err != nil {
+		writeWatchError(w, err, "Failed to remove watch level")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(watch)
+}
+
+// writeWatchError дополняет writeTopicError ошибками слежения
+func writeWatchError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, service.ErrInvalidWatch) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeTopicError(w, err, message)
+}
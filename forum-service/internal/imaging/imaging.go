@@ -0,0 +1,221 @@
+// Package imaging готовит загруженные картинки к показу: убирает метаданные (EXIF, GPS, XMP),
+// поворачивает фото по EXIF-ориентации, строит уменьшенные копии и отсекает
+// картинки, которые при распаковке заняли бы слишком много памяти.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // регистрирует декодер WebP
+)
+
+var (
+	ErrTooLarge     = errors.New("image dimensions exceed the limit")
+	ErrInvalidImage = errors.New("invalid or unsupported image")
+)
+
+// OriginalName имя очищенной от метаданных копии исходной картинки
+const OriginalName = "original"
+
+// Limits ограничения на обрабатываемые картинки
+type Limits struct {
+	// MaxPixels наибольшая площадь картинки; проверяется по заголовку до распаковки
+	MaxPixels int
+	// MaxDimension наибольшая ширина или высота
+	MaxDimension int
+}
+
+// DefaultLimits около 100 МБ памяти на распакованную картинку
+var DefaultLimits = Limits{
+	MaxPixels:    24_000_000,
+	MaxDimension: 16_000,
+}
+
+// Size уменьшенная копия, которая вписывается в рамку MaxWidth × MaxHeight
+type Size struct {
+	Name      string
+	MaxWidth  int
+	MaxHeight int
+}
+
+// DefaultSizes миниатюра и два размера для адаптивной верстки
+var DefaultSizes = []Size{
+	{Name: "thumb", MaxWidth: 320, MaxHeight: 320},
+	{Name: "medium", MaxWidth: 800, MaxHeight: 800},
+	{Name: "large", MaxWidth: 1600, MaxHeight: 1600},
+}
+
+// Output готовый вариант картинки
+type Output struct {
+	Name        string
+	ContentType string
+	Data        []byte
+	Width       int
+	Height      int
+}
+
+// Result результат обработки. Первым в Outputs идет очищенный оригинал,
+// за ним уменьшенные копии; копии не больше оригинала не строятся.
+type Result struct {
+	Width   int
+	Height  int
+	Outputs []Output
+}
+
+// Process обрабатывает картинку в формате JPEG, PNG, GIF или WebP
+func Process(data []byte, limits Limits, sizes []Size) (*Result, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 ||
+		cfg.Width > limits.MaxDimension || cfg.Height > limits.MaxDimension ||
+		cfg.Width*cfg.Height > limits.MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooLarge, cfg.Width, cfg.Height)
+	}
+
+	// Для картинки хватает первого кадра GIF, но браузеры распаковывают все кадры
+	if format == "gif" {
+		if err := checkGIFFrames(data, limits); err != nil {
+			return nil, err
+		}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	var original Output
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+	if orientation > 1 && orientation <= 8 {
+		// Поворот нельзя сохранить без EXIF, поэтому картинка перекодируется уже повернутой
+		img = orient(img, orientation)
+		original, err = encode(OriginalName, img)
+	} else {
+		original, err = stripped(data, format, img.Bounds())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	result := &Result{Width: bounds.Dx(), Height: bounds.Dy(), Outputs: []Output{original}}
+	for _, size := range sizes {
+		width, height := fit(bounds.Dx(), bounds.Dy(), size.MaxWidth, size.MaxHeight)
+		if width >= bounds.Dx() && height >= bounds.Dy() {
+			continue
+		}
+		dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+		output, err := encode(size.Name, dst)
+		if err != nil {
+			return nil, err
+		}
+		result.Outputs = append(result.Outputs, output)
+	}
+	return result, nil
+}
+
+// stripped возвращает исходные байты без метаданных, не перекодируя картинку
+func stripped(data []byte, format string, bounds image.Rectangle) (Output, error) {
+	var (
+		clean       []byte
+		contentType string
+		err         error
+	)
+	switch format {
+	case "jpeg":
+		clean, err = stripJPEG(data)
+		contentType = "image/jpeg"
+	case "png":
+		clean, err = stripPNG(data)
+		contentType = "image/png"
+	case "gif":
+		clean, err = stripGIF(data)
+		contentType = "image/gif"
+	case "webp":
+		clean, err = stripWebP(data)
+		contentType = "image/webp"
+	default:
+		return Output{}, fmt.Errorf("%w: format %s", ErrInvalidImage, format)
+	}
+	if err != nil {
+		return Output{}, err
+	}
+	return Output{
+		Name:        OriginalName,
+		ContentType: contentType,
+		Data:        clean,
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+	}, nil
+}
+
+// encode сохраняет непрозрачные картинки в JPEG, а с прозрачностью — в PNG
+func encode(name string, img image.Image) (Output, error) {
+	var buf bytes.Buffer
+	contentType := "image/jpeg"
+	var err error
+	if opaque(img) {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+	} else {
+		contentType = "image/png"
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return Output{}, err
+	}
+	bounds := img.Bounds()
+	return Output{
+		Name:        name,
+		ContentType: contentType,
+		Data:        buf.Bytes(),
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+	}, nil
+}
+
+func opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
+
+// fit вписывает размеры в рамку с сохранением пропорций
+func fit(width, height, maxWidth, maxHeight int) (int, int) {
+	if width <= maxWidth && height <= maxHeight {
+		return width, height
+	}
+	if width*maxHeight > height*maxWidth {
+		return maxWidth, max(1, height*maxWidth/width)
+	}
+	return max(1, width*maxHeight/height), maxHeight
+}
+
+// checkGIFFrames ограничивает суммарную площадь кадров анимации четырьмя MaxPixels
+func checkGIFFrames(data []byte, limits Limits) error {
+	frames, err := gifFrameCount(data)
+	if err != nil {
+		return err
+	}
+	cfg, err := gif.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+	if frames*cfg.Width*cfg.Height > limits.MaxPixels*4 {
+		return fmt.Errorf("%w: %d frames of %dx%d", ErrTooLarge, frames, cfg.Width, cfg.Height)
+	}
+	return nil
+}
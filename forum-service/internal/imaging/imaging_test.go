@@ -0,0 +1,136 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+// exifSegment собирает APP1 с ориентацией и текстом, имитирующим GPS-координаты
+func exifSegment(orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = append(tiff, 0, 1) // одна запись в IFD0
+	entry := make([]byte, 12)
+	binary.BigEndian.PutUint16(entry[0:], exifOrientationTag)
+	binary.BigEndian.PutUint16(entry[2:], 3)
+	binary.BigEndian.PutUint32(entry[4:], 1)
+	binary.BigEndian.PutUint16(entry[8:], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0)
+	tiff = append(tiff, "GPSLatitude 55.7558"...)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func jpegWithExif(t *testing.T, width, height int, orientation uint16) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, testImage(width, height), nil))
+	data := buf.Bytes()
+	return append(append(append([]byte{}, data[:2]...), exifSegment(orientation)...), data[2:]...)
+}
+
+func TestProcess_StripsJPEGMetadata(t *testing.T) {
+	data := jpegWithExif(t, 40, 20, 1)
+
+	result, err := Process(data, DefaultLimits, DefaultSizes)
+
+	require.NoError(t, err)
+	assert.Equal(t, 40, result.Width)
+	assert.Equal(t, 20, result.Height)
+	require.Len(t, result.Outputs, 1)
+	assert.Equal(t, OriginalName, result.Outputs[0].Name)
+	assert.Equal(t, "image/jpeg", result.Outputs[0].ContentType)
+	assert.NotContains(t, string(result.Outputs[0].Data), "GPSLatitude")
+	assert.Equal(t, len(data)-len(exifSegment(1)), len(result.Outputs[0].Data))
+}
+
+func TestProcess_AppliesOrientation(t *testing.T) {
+	data := jpegWithExif(t, 40, 20, 6)
+
+	result, err := Process(data, DefaultLimits, DefaultSizes)
+
+	require.NoError(t, err)
+	assert.Equal(t, 20, result.Width)
+	assert.Equal(t, 40, result.Height)
+	assert.NotContains(t, string(result.Outputs[0].Data), "GPSLatitude")
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(result.Outputs[0].Data))
+	require.NoError(t, err)
+	assert.Equal(t, 20, cfg.Width)
+}
+
+func TestProcess_Variants(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(1000, 500)))
+
+	result, err := Process(buf.Bytes(), DefaultLimits, DefaultSizes)
+
+	require.NoError(t, err)
+	require.Len(t, result.Outputs, 3)
+	assert.Equal(t, "thumb", result.Outputs[1].Name)
+	assert.Equal(t, 320, result.Outputs[1].Width)
+	assert.Equal(t, 160, result.Outputs[1].Height)
+	assert.Equal(t, "image/jpeg", result.Outputs[1].ContentType)
+	assert.Equal(t, "medium", result.Outputs[2].Name)
+	assert.Equal(t, 800, result.Outputs[2].Width)
+}
+
+func TestProcess_RejectsDecompressionBomb(t *testing.T) {
+	// Заголовок PNG 100000×100000 без данных: проверка должна сработать до распаковки
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], 100000)
+	binary.BigEndian.PutUint32(ihdr[4:], 100000)
+	ihdr[8], ihdr[9] = 8, 6
+	data := append(append([]byte{}, pngSignature...), pngChunk("IHDR", ihdr)...)
+
+	_, err := Process(data, DefaultLimits, DefaultSizes)
+
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestProcess_InvalidImage(t *testing.T) {
+	_, err := Process([]byte("not an image"), DefaultLimits, DefaultSizes)
+
+	assert.ErrorIs(t, err, ErrInvalidImage)
+}
+
+func TestStripPNG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(4, 4)))
+	data := buf.Bytes()
+	// tEXt вставляется сразу после IHDR
+	withText := append(append(append([]byte{}, data[:33]...), pngChunk("tEXt", []byte("Author\x00John"))...), data[33:]...)
+
+	clean, err := stripPNG(withText)
+
+	require.NoError(t, err)
+	assert.Equal(t, data, clean)
+}
+
+func pngChunk(name string, payload []byte) []byte {
+	chunk := make([]byte, 8, 12+len(payload))
+	binary.BigEndian.PutUint32(chunk, uint32(len(payload)))
+	copy(chunk[4:], name)
+	chunk = append(chunk, payload...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
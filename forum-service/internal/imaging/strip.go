@@ -0,0 +1,256 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// stripJPEG убирает сегменты APP1–APP15 (EXIF, XMP, IPTC и т.п.) и комментарии.
+// Остаются JFIF (APP0), ICC-профиль (APP2) и Adobe (APP14), которые влияют на цвета.
+func stripJPEG(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, fmt.Errorf("%w: missing JPEG SOI", ErrInvalidImage)
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+
+	for i := 2; i < len(data); {
+		if data[i] != 0xFF {
+			return nil, fmt.Errorf("%w: bad JPEG marker", ErrInvalidImage)
+		}
+		// Перед маркером может стоять любое число байтов-заполнителей 0xFF
+		for i < len(data) && data[i] == 0xFF {
+			i++
+		}
+		if i >= len(data) {
+			break
+		}
+		marker := data[i]
+		i++
+
+		switch {
+		case marker == 0xDA, marker == 0xD9:
+			// Начало сжатых данных или конец картинки: дальше метаданных нет
+			out.Write([]byte{0xFF, marker})
+			out.Write(data[i:])
+			return out.Bytes(), nil
+		case marker >= 0xD0 && marker <= 0xD7, marker == 0x01:
+			out.Write([]byte{0xFF, marker})
+			continue
+		}
+
+		if i+2 > len(data) {
+			return nil, fmt.Errorf("%w: truncated JPEG segment", ErrInvalidImage)
+		}
+		length := int(binary.BigEndian.Uint16(data[i:]))
+		if length < 2 || i+length > len(data) {
+			return nil, fmt.Errorf("%w: truncated JPEG segment", ErrInvalidImage)
+		}
+		keep := marker != 0xFE
+		if marker >= 0xE0 && marker <= 0xEF {
+			keep = marker == 0xE0 || marker == 0xE2 || marker == 0xEE
+		}
+		if keep {
+			out.Write([]byte{0xFF, marker})
+			out.Write(data[i : i+length])
+		}
+		i += length
+	}
+	return nil, fmt.Errorf("%w: JPEG without image data", ErrInvalidImage)
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// pngChunks фрагменты PNG, которые нужны для показа картинки, включая анимацию APNG.
+// Текстовые фрагменты, eXIf и время изменения отбрасываются.
+var pngChunks = map[string]bool{
+	"IHDR": true, "PLTE": true, "IDAT": true, "IEND": true,
+	"tRNS": true, "gAMA": true, "cHRM": true, "sRGB": true, "iCCP": true, "sBIT": true, "bKGD": true, "pHYs": true,
+	"acTL": true, "fcTL": true, "fdAT": true,
+}
+
+func stripPNG(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, fmt.Errorf("%w: missing PNG signature", ErrInvalidImage)
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(pngSignature)
+
+	for i := len(pngSignature); i < len(data); {
+		if i+8 > len(data) {
+			return nil, fmt.Errorf("%w: truncated PNG chunk", ErrInvalidImage)
+		}
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+		if end > len(data) {
+			return nil, fmt.Errorf("%w: truncated PNG chunk", ErrInvalidImage)
+		}
+		chunk := string(data[i+4 : i+8])
+		if pngChunks[chunk] {
+			out.Write(data[i:end])
+		}
+		i = end
+		if chunk == "IEND" {
+			break
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// Флаги VP8X о наличии фрагментов EXIF и XMP
+const (
+	webpFlagXMP  = 0x04
+	webpFlagEXIF = 0x08
+)
+
+// stripWebP убирает фрагменты EXIF и XMP и сбрасывает их флаги в VP8X
+func stripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, fmt.Errorf("%w: missing WebP header", ErrInvalidImage)
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:12])
+
+	for i := 12; i+8 <= len(data); {
+		fourcc := string(data[i : i+4])
+		size := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + size
+		if end > len(data) {
+			return nil, fmt.Errorf("%w: truncated WebP chunk", ErrInvalidImage)
+		}
+		// Фрагменты нечетной длины выравниваются байтом, у последнего его может не быть
+		if size%2 == 1 && end < len(data) {
+			end++
+		}
+		switch fourcc {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte(nil), data[i:end]...)
+			if len(chunk) > 8 {
+				chunk[8] &^= webpFlagEXIF | webpFlagXMP
+			}
+			out.Write(chunk)
+		default:
+			out.Write(data[i:end])
+		}
+		i = end
+	}
+
+	clean := out.Bytes()
+	binary.LittleEndian.PutUint32(clean[4:], uint32(len(clean)-8))
+	return clean, nil
+}
+
+// gifBlock расширение или кадр GIF в исходных байтах
+type gifBlock struct {
+	start, end int
+	frame      bool
+	label      byte
+	app        string
+}
+
+// stripGIF убирает комментарии и расширения приложений, кроме настроек анимации (NETSCAPE2.0)
+func stripGIF(data []byte) ([]byte, error) {
+	header, blocks, err := gifBlocks(data)
+	if err != nil {
+		return nil, err
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:header])
+	for _, block := range blocks {
+		switch {
+		case block.label == 0xFE:
+		case block.label == 0xFF && block.app != "NETSCAPE2.0" && block.app != "ANIMEXTS1.0":
+		default:
+			out.Write(data[block.start:block.end])
+		}
+	}
+	out.WriteByte(0x3B)
+	return out.Bytes(), nil
+}
+
+func gifFrameCount(data []byte) (int, error) {
+	_, blocks, err := gifBlocks(data)
+	if err != nil {
+		return 0, err
+	}
+	frames := 0
+	for _, block := range blocks {
+		if block.frame {
+			frames++
+		}
+	}
+	return frames, nil
+}
+
+// gifBlocks разбирает GIF на заголовок с глобальной палитрой и последовательность блоков
+func gifBlocks(data []byte) (int, []gifBlock, error) {
+	invalid := fmt.Errorf("%w: malformed GIF", ErrInvalidImage)
+	if len(data) < 13 || (string(data[:6]) != "GIF87a" && string(data[:6]) != "GIF89a") {
+		return 0, nil, invalid
+	}
+	header := 13
+	if flags := data[10]; flags&0x80 != 0 {
+		header += 3 << (flags&0x07 + 1)
+	}
+	if header > len(data) {
+		return 0, nil, invalid
+	}
+
+	// skipSubBlocks пропускает цепочку подблоков, завершенную нулевым байтом
+	skipSubBlocks := func(i int) (int, bool) {
+		for i < len(data) {
+			size := int(data[i])
+			i++
+			if size == 0 {
+				return i, true
+			}
+			i += size
+		}
+		return i, false
+	}
+
+	var blocks []gifBlock
+	for i := header; i < len(data); {
+		block := gifBlock{start: i}
+		switch data[i] {
+		case 0x3B:
+			return header, blocks, nil
+		case 0x21:
+			if i+2 > len(data) {
+				return 0, nil, invalid
+			}
+			block.label = data[i+1]
+			if block.label == 0xFF && i+3 < len(data) && data[i+2] == 11 && i+14 <= len(data) {
+				block.app = string(data[i+3 : i+14])
+			}
+			end, ok := skipSubBlocks(i + 2)
+			if !ok {
+				return 0, nil, invalid
+			}
+			block.end = end
+		case 0x2C:
+			if i+10 > len(data) {
+				return 0, nil, invalid
+			}
+			next := i + 10
+			if flags := data[i+9]; flags&0x80 != 0 {
+				next += 3 << (flags&0x07 + 1)
+			}
+			// Байт минимального размера кода LZW, затем сжатые данные
+			end, ok := skipSubBlocks(next + 1)
+			if !ok {
+				return 0, nil, invalid
+			}
+			block.end = end
+			block.frame = true
+		default:
+			return 0, nil, invalid
+		}
+		blocks = append(blocks, block)
+		i = block.end
+	}
+	// Без завершающего байта GIF все равно показывается браузерами
+	return header, blocks, nil
+}
@@ -0,0 +1,287 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	fenceRe   = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})(.*)$")
+	hrRe      = regexp.MustCompile(`^ {0,3}(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	headingRe = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?[ \t]*$`)
+	closingRe = regexp.MustCompile(`(?:^|[ \t]+)#+$`)
+	quoteRe   = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	listRe    = regexp.MustCompile(`^( {0,3})([-+*]|(\d{1,9})([.)]))(?:([ \t]+)(.*))?$`)
+)
+
+// maxNestingDepth сколько уровней вложенных цитат и списков разбирается. Каждый уровень
+// заново проходит по своим строкам, поэтому более глубокие маркеры выводятся как текст,
+// чтобы время рендеринга оставалось линейным.
+const maxNestingDepth = 16
+
+// renderBlocks разбирает строки на блоки и пишет их HTML. В плотном (tight) режиме
+// абзацы выводятся без <p>, как в элементах компактного списка. depth — уровень
+// вложенности цитат и списков, в которых находятся строки.
+func renderBlocks(b *strings.Builder, lines []string, tight bool, depth int) {
+	for i := range lines {
+		lines[i] = expandTabs(lines[i])
+	}
+
+	nested := depth < maxNestingDepth
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case isBlank(line):
+			i++
+		case isFence(line):
+			i = renderFence(b, lines, i)
+		case indentOf(line) >= 4:
+			i = renderIndentedCode(b, lines, i)
+		case hrRe.MatchString(line):
+			b.WriteString("<hr>\n")
+			i++
+		case headingRe.MatchString(line):
+			renderHeading(b, line)
+			i++
+		case nested && quoteOpenRe.MatchString(line):
+			i = renderQuoteBlock(b, lines, i, depth)
+		case nested && quoteRe.MatchString(line):
+			i = renderQuote(b, lines, i, depth)
+		case nested && listRe.MatchString(line):
+			i = renderList(b, lines, i, depth)
+		default:
+			i = renderParagraph(b, lines, i, tight)
+		}
+	}
+}
+
+func renderHeading(b *strings.Builder, line string) {
+	m := headingRe.FindStringSubmatch(line)
+	level := strconv.Itoa(len(m[1]))
+	text := closingRe.ReplaceAllString(m[2], "")
+	b.WriteString("<h" + level + ">")
+	renderInline(b, strings.TrimSpace(text))
+	b.WriteString("</h" + level + ">\n")
+}
+
+func renderFence(b *strings.Builder, lines []string, i int) int {
+	m := fenceRe.FindStringSubmatch(lines[i])
+	indent, fence := len(m[1]), m[2]
+	info := strings.Fields(m[3])
+
+	var code []string
+	for i++; i < len(lines); i++ {
+		if closesFence(lines[i], fence) {
+			i++
+			break
+		}
+		code = append(code, trimIndent(lines[i], indent))
+	}
+
+	b.WriteString("<pre><code")
+	if len(info) > 0 {
+		b.WriteString(` class="language-` + html.EscapeString(info[0]) + `"`)
+	}
+	b.WriteString(">")
+	writeCode(b, code)
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+func renderIndentedCode(b *strings.Builder, lines []string, i int) int {
+	var code []string
+	for ; i < len(lines) && (isBlank(lines[i]) || indentOf(lines[i]) >= 4); i++ {
+		code = append(code, trimIndent(lines[i], 4))
+	}
+	for len(code) > 0 && isBlank(code[len(code)-1]) {
+		code = code[:len(code)-1]
+	}
+
+	b.WriteString("<pre><code>")
+	writeCode(b, code)
+	b.WriteString("</code></pre>\n")
+	return i
+}
+
+func writeCode(b *strings.Builder, code []string) {
+	for _, line := range code {
+		b.WriteString(html.EscapeString(line))
+		b.WriteString("\n")
+	}
+}
+
+func renderQuote(b *strings.Builder, lines []string, i, depth int) int {
+	var inner []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if m := quoteRe.FindStringSubmatch(line); m != nil {
+			inner = append(inner, m[1])
+			continue
+		}
+		// Ленивое продолжение абзаца внутри цитаты
+		if !isBlank(line) && len(inner) > 0 && !isBlank(inner[len(inner)-1]) && !startsBlock(line) {
+			inner = append(inner, line)
+			continue
+		}
+		break
+	}
+
+	b.WriteString("<blockquote>\n")
+	renderBlocks(b, inner, false, depth+1)
+	b.WriteString("</blockquote>\n")
+	return i
+}
+
+func renderList(b *strings.Builder, lines []string, i, depth int) int {
+	first := listRe.FindStringSubmatch(lines[i])
+	ordered := first[3] != ""
+	kind := listKind(first)
+
+	var items [][]string
+	tight := true
+	for i < len(lines) {
+		m := listRe.FindStringSubmatch(lines[i])
+		if m == nil || hrRe.MatchString(lines[i]) || listKind(m) != kind {
+			break
+		}
+		contentIndent := len(m[1]) + len(m[2]) + 1
+		if spaces := len(m[5]); spaces >= 1 && spaces <= 4 && m[6] != "" {
+			contentIndent = len(m[1]) + len(m[2]) + spaces
+		}
+
+		item := []string{m[6]}
+		for i++; i < len(lines); i++ {
+			line := lines[i]
+			if isBlank(line) {
+				item = append(item, "")
+				continue
+			}
+			if indentOf(line) >= contentIndent {
+				item = append(item, line[contentIndent:])
+				continue
+			}
+			// Ленивое продолжение абзаца элемента
+			if !isBlank(item[len(item)-1]) && !startsBlock(line) {
+				item = append(item, strings.TrimLeft(line, " "))
+				continue
+			}
+			break
+		}
+
+		trailing := 0
+		for len(item) > 1 && isBlank(item[len(item)-1]) {
+			item = item[:len(item)-1]
+			trailing++
+		}
+		if trailing > 0 && i < len(lines) && listRe.MatchString(lines[i]) {
+			tight = false
+		}
+		for _, line := range item {
+			if isBlank(line) {
+				tight = false
+			}
+		}
+		items = append(items, item)
+	}
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	b.WriteString("<" + tag)
+	if start, _ := strconv.Atoi(first[3]); ordered && start != 1 {
+		b.WriteString(` start="` + strconv.Itoa(start) + `"`)
+	}
+	b.WriteString(">\n")
+	for _, item := range items {
+		var inner strings.Builder
+		renderBlocks(&inner, item, tight, depth+1)
+		content := inner.String()
+		if tight {
+			content = strings.TrimSuffix(content, "\n")
+		} else {
+			content = "\n" + content
+		}
+		b.WriteString("<li>" + content + "</li>\n")
+	}
+	b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+func renderParagraph(b *strings.Builder, lines []string, i int, tight bool) int {
+	var text []string
+	for start := i; i < len(lines); i++ {
+		line := lines[i]
+		if isBlank(line) || (i > start && startsBlock(line)) {
+			break
+		}
+		text = append(text, strings.TrimLeft(line, " "))
+	}
+
+	if !tight {
+		b.WriteString("<p>")
+	}
+	renderInline(b, strings.TrimRight(strings.Join(text, "\n"), " "))
+	if !tight {
+		b.WriteString("</p>")
+	}
+	b.WriteString("\n")
+	return i
+}
+
+// startsBlock сообщает, что строка начинает новый блок и прерывает абзац
+func startsBlock(line string) bool {
+	if isFence(line) || hrRe.MatchString(line) || headingRe.MatchString(line) || quoteRe.MatchString(line) ||
+		quoteOpenRe.MatchString(line) {
+		return true
+	}
+	m := listRe.FindStringSubmatch(line)
+	return m != nil && strings.TrimSpace(m[6]) != ""
+}
+
+// listKind отличает списки по маркеру: новый маркер начинает новый список
+func listKind(m []string) string {
+	if m[3] != "" {
+		return "ol" + m[4]
+	}
+	return "ul" + m[2]
+}
+
+func isFence(line string) bool {
+	m := fenceRe.FindStringSubmatch(line)
+	// В строке информации после ``` не может быть обратных кавычек
+	return m != nil && !(m[2][0] == '`' && strings.Contains(m[3], "`"))
+}
+
+// closesFence сообщает, что строка закрывает блок кода, открытый маркером fence
+func closesFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return indentOf(line) < 4 && strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// trimIndent убирает не больше n пробелов в начале строки
+func trimIndent(line string, n int) string {
+	if indent := indentOf(line); indent < n {
+		n = indent
+	}
+	return line[n:]
+}
+
+// expandTabs заменяет табуляцию в отступе строки на четыре пробела
+func expandTabs(line string) string {
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+	if !strings.Contains(line[:indent], "\t") {
+		return line
+	}
+	return strings.ReplaceAll(line[:indent], "\t", "    ") + line[indent:]
+}
@@ -0,0 +1,43 @@
+package markdown
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Options ограничения, которые применяются при рендеринге
+type Options struct {
+	// MaxImages сколько картинок выводится в одном тексте; остальные превращаются в ссылки
+	MaxImages int
+	// MinHeadingLevel самый крупный допустимый заголовок: более крупные понижаются до него,
+	// чтобы текст пользователя не спорил с заголовками страницы
+	MinHeadingLevel int
+}
+
+// DefaultOptions ограничения по умолчанию
+var DefaultOptions = Options{
+	MaxImages:       10,
+	MinHeadingLevel: 2,
+}
+
+// LoadOptions читает ограничения из MARKDOWN_MAX_IMAGES и MARKDOWN_MIN_HEADING_LEVEL.
+// Незаданные переменные оставляют значения по умолчанию.
+func LoadOptions() (Options, error) {
+	opts := DefaultOptions
+	if value := os.Getenv("MARKDOWN_MAX_IMAGES"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return opts, fmt.Errorf("invalid MARKDOWN_MAX_IMAGES %q", value)
+		}
+		opts.MaxImages = n
+	}
+	if value := os.Getenv("MARKDOWN_MIN_HEADING_LEVEL"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 6 {
+			return opts, fmt.Errorf("invalid MARKDOWN_MIN_HEADING_LEVEL %q", value)
+		}
+		opts.MinHeadingLevel = n
+	}
+	return opts, nil
+}
@@ -0,0 +1,302 @@
+package markdown
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// renderInline пишет HTML строчной разметки: код, ссылки, картинки, выделение и переносы.
+// Весь остальной текст, включая HTML-теги, экранируется.
+func renderInline(b *strings.Builder, text string) {
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && text[i+1] == '\n':
+			b.WriteString("<br>\n")
+			i += 2
+		case c == '\\' && i+1 < len(text) && isPunct(text[i+1]):
+			b.WriteString(html.EscapeString(text[i+1 : i+2]))
+			i += 2
+		case c == '`':
+			i = renderCodeSpan(b, text, i)
+		case c == '!' && i+1 < len(text) && text[i+1] == '[':
+			if link, ok := parseLink(text, i+1); ok {
+				writeImage(b, link)
+				i = link.end
+			} else {
+				b.WriteByte('!')
+				i++
+			}
+		case c == '[':
+			if link, ok := parseLink(text, i); ok {
+				writeLink(b, link)
+				i = link.end
+			} else {
+				b.WriteByte('[')
+				i++
+			}
+		case c == '<':
+			i = renderAutolink(b, text, i)
+		case c == '*' || c == '_' || (c == '~' && strings.HasPrefix(text[i:], "~~")):
+			i = renderEmphasis(b, text, i)
+		case c == 'h' && isWordStart(text, i) && (strings.HasPrefix(text[i:], "http://") || strings.HasPrefix(text[i:], "https://")):
+			i = renderBareURL(b, text, i)
+		case c == ' ' && hardBreakAt(text, i):
+			b.WriteString("<br>\n")
+			i = strings.IndexByte(text[i:], '\n') + i + 1
+		default:
+			_, size := utf8.DecodeRuneInString(text[i:])
+			b.WriteString(html.EscapeString(text[i : i+size]))
+			i += size
+		}
+	}
+}
+
+// renderCodeSpan выводит `код`. Без закрывающей последовательности той же длины
+// обратные кавычки остаются текстом.
+func renderCodeSpan(b *strings.Builder, text string, i int) int {
+	n := runLength(text, i, '`')
+	for j := i + n; j < len(text); {
+		k := strings.IndexByte(text[j:], '`')
+		if k < 0 {
+			break
+		}
+		j += k
+		m := runLength(text, j, '`')
+		if m != n {
+			j += m
+			continue
+		}
+		code := strings.ReplaceAll(text[i+n:j], "\n", " ")
+		if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+			code = code[1 : len(code)-1]
+		}
+		b.WriteString("<code>" + html.EscapeString(code) + "</code>")
+		return j + n
+	}
+	b.WriteString(text[i : i+n])
+	return i + n
+}
+
+// renderEmphasis выводит *курсив*, **жирный** и ~~зачеркнутый~~ текст.
+// Подчеркивание внутри слова выделением не считается.
+func renderEmphasis(b *strings.Builder, text string, i int) int {
+	c := text[i]
+	n := runLength(text, i, c)
+	if n > 2 {
+		n = 2
+	}
+	if c == '~' && n != 2 {
+		b.WriteByte(c)
+		return i + 1
+	}
+	delim := strings.Repeat(string(c), n)
+	open := i + n
+
+	canOpen := open < len(text) && !isSpaceByte(text[open])
+	if c == '_' && i > 0 && isWordByte(text[i-1]) {
+		canOpen = false
+	}
+	if canOpen {
+		for j := open + 1; j <= len(text)-n; j++ {
+			if !strings.HasPrefix(text[j:], delim) || isSpaceByte(text[j-1]) {
+				continue
+			}
+			// Одиночный разделитель не закрывается началом двойного
+			if n == 1 && j+1 < len(text) && text[j+1] == c {
+				j++
+				continue
+			}
+			if c == '_' && j+n < len(text) && isWordByte(text[j+n]) {
+				continue
+			}
+			tag := "em"
+			switch {
+			case c == '~':
+				tag = "del"
+			case n == 2:
+				tag = "strong"
+			}
+			b.WriteString("<" + tag + ">")
+			renderInline(b, text[open:j])
+			b.WriteString("</" + tag + ">")
+			return j + n
+		}
+	}
+	b.WriteString(delim)
+	return open
+}
+
+// inlineLink разобранная ссылка [текст](адрес "заголовок")
+type inlineLink struct {
+	text  string
+	dest  string
+	title string
+	end   int
+}
+
+// parseLink разбирает ссылку, начинающуюся с '[' в позиции i
+func parseLink(text string, i int) (inlineLink, bool) {
+	depth := 0
+	closeText := -1
+	for j := i; j < len(text) && closeText < 0; j++ {
+		switch text[j] {
+		case '\\':
+			j++
+		case '`':
+			j += runLength(text, j, '`') - 1
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				closeText = j
+			}
+		}
+	}
+	if closeText < 0 || closeText+1 >= len(text) || text[closeText+1] != '(' {
+		return inlineLink{}, false
+	}
+
+	link := inlineLink{text: text[i+1 : closeText]}
+	j := skipSpaces(text, closeText+2)
+	if j < len(text) && text[j] == '<' {
+		k := strings.IndexAny(text[j:], ">\n")
+		if k < 0 || text[j+k] != '>' {
+			return inlineLink{}, false
+		}
+		link.dest = text[j+1 : j+k]
+		j += k + 1
+	} else {
+		start, parens := j, 0
+		for ; j < len(text) && !isSpaceByte(text[j]); j++ {
+			if text[j] == '(' {
+				parens++
+			} else if text[j] == ')' {
+				if parens == 0 {
+					break
+				}
+				parens--
+			}
+		}
+		link.dest = text[start:j]
+	}
+
+	j = skipSpaces(text, j)
+	if j < len(text) && (text[j] == '"' || text[j] == '\'') {
+		k := strings.IndexByte(text[j+1:], text[j])
+		if k < 0 {
+			return inlineLink{}, false
+		}
+		link.title = text[j+1 : j+1+k]
+		j = skipSpaces(text, j+k+2)
+	}
+	if j >= len(text) || text[j] != ')' {
+		return inlineLink{}, false
+	}
+	link.end = j + 1
+	return link, true
+}
+
+func writeLink(b *strings.Builder, link inlineLink) {
+	b.WriteString(`<a href="` + html.EscapeString(link.dest) + `"`)
+	if link.title != "" {
+		b.WriteString(` title="` + html.EscapeString(link.title) + `"`)
+	}
+	b.WriteString(">")
+	renderInline(b, link.text)
+	b.WriteString("</a>")
+}
+
+func writeImage(b *strings.Builder, link inlineLink) {
+	b.WriteString(`<img src="` + html.EscapeString(link.dest) + `" alt="` + html.EscapeString(plainText(link.text)) + `"`)
+	if link.title != "" {
+		b.WriteString(` title="` + html.EscapeString(link.title) + `"`)
+	}
+	b.WriteString(">")
+}
+
+// renderAutolink выводит <https://example.com>; любой другой '<' экранируется
+func renderAutolink(b *strings.Builder, text string, i int) int {
+	end := strings.IndexAny(text[i+1:], "<> \n")
+	if end > 0 && text[i+1+end] == '>' {
+		dest := text[i+1 : i+1+end]
+		lower := strings.ToLower(dest)
+		if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "mailto:") {
+			writeLink(b, inlineLink{text: dest, dest: dest})
+			return i + end + 2
+		}
+	}
+	b.WriteString("&lt;")
+	return i + 1
+}
+
+// renderBareURL превращает адрес в тексте в ссылку. Завершающая пунктуация в адрес не входит.
+func renderBareURL(b *strings.Builder, text string, i int) int {
+	end := i
+	for end < len(text) && !isSpaceByte(text[end]) && text[end] != '<' {
+		end++
+	}
+	for end > i && strings.IndexByte(".,:;!?)'\"*_~", text[end-1]) >= 0 {
+		end--
+	}
+	dest := text[i:end]
+	b.WriteString(`<a href="` + html.EscapeString(dest) + `">` + html.EscapeString(dest) + "</a>")
+	return end
+}
+
+// hardBreakAt сообщает, что с позиции i идут два и более пробела перед переводом строки
+func hardBreakAt(text string, i int) bool {
+	j := i
+	for j < len(text) && text[j] == ' ' {
+		j++
+	}
+	return j-i >= 2 && j < len(text) && text[j] == '\n'
+}
+
+// plainText убирает разметку из текста картинки для атрибута alt
+func plainText(text string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune("*_`[]~", r) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+func runLength(text string, i int, c byte) int {
+	n := 0
+	for i+n < len(text) && text[i+n] == c {
+		n++
+	}
+	return n
+}
+
+func skipSpaces(text string, i int) int {
+	for i < len(text) && isSpaceByte(text[i]) {
+		i++
+	}
+	return i
+}
+
+func isWordStart(text string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	r, _ := utf8.DecodeLastRuneInString(text[:i])
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func isWordByte(c byte) bool {
+	return c >= 0x80 || c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isPunct(c byte) bool {
+	return c < 0x80 && unicode.IsPunct(rune(c)) || strings.IndexByte("$+<=>^`|~", c) >= 0
+}
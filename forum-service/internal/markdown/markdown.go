@@ -0,0 +1,32 @@
+// Package markdown превращает Markdown пользователей в безопасный HTML.
+// Поддерживается подмножество CommonMark: абзацы, заголовки, цитаты, списки,
+// блоки кода, ссылки, картинки, выделение и зачеркивание, а также цитаты сообщений
+// [quote=id]...[/quote]. HTML в исходном тексте выводится как текст, а результат
+// дополнительно проходит через Sanitize.
+package markdown
+
+import (
+	"strings"
+)
+
+// Renderer рендерит Markdown с заданными ограничениями
+type Renderer struct {
+	opts Options
+}
+
+func NewRenderer(opts Options) *Renderer {
+	return &Renderer{opts: opts}
+}
+
+// Render возвращает очищенный HTML для исходного текста
+func (r *Renderer) Render(source string) string {
+	if strings.TrimSpace(source) == "" {
+		return ""
+	}
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	source = strings.ReplaceAll(source, "\r", "\n")
+
+	var b strings.Builder
+	renderBlocks(&b, strings.Split(source, "\n"), false, 0)
+	return Sanitize(b.String(), r.opts)
+}
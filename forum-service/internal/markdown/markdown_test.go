@@ -0,0 +1,93 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func render(source string) string {
+	return NewRenderer(DefaultOptions).Render(source)
+}
+
+func TestRender_Blocks(t *testing.T) {
+	assert.Equal(t, "<h2>Заголовок</h2>\n<p>Текст <strong>жирный</strong>, <em>курсив</em> и <del>старое</del></p>\n",
+		render("## Заголовок\nТекст **жирный**, _курсив_ и ~~старое~~"))
+	assert.Equal(t, "<blockquote>\n<p>цитата\nпродолжение</p>\n</blockquote>\n", render("> цитата\nпродолжение"))
+	assert.Equal(t, "<ul>\n<li>один</li>\n<li>два</li>\n</ul>\n", render("- один\n- два"))
+	assert.Equal(t, "<ol start=\"3\">\n<li>три</li>\n</ol>\n", render("3. три"))
+	assert.Equal(t, "<hr>\n", render("***"))
+}
+
+func TestRender_NestingDepth(t *testing.T) {
+	quotes := render(strings.Repeat("> ", 5000) + "глубоко")
+	assert.Equal(t, maxNestingDepth, strings.Count(quotes, "<blockquote>"))
+	assert.Contains(t, quotes, "<p>&gt; &gt;")
+	assert.Contains(t, quotes, "глубоко")
+
+	lists := render(strings.Repeat("- ", 5000) + "глубоко")
+	assert.Equal(t, maxNestingDepth, strings.Count(lists, "<ul>"))
+	assert.Contains(t, lists, "глубоко")
+
+	blocks := render(strings.Repeat("[quote=m]\n", 5000) + "глубоко")
+	assert.Equal(t, maxNestingDepth, strings.Count(blocks, `<blockquote data-message-id="m">`))
+	assert.Contains(t, blocks, "[quote=m]")
+	assert.Contains(t, blocks, "глубоко")
+}
+
+func TestRender_Code(t *testing.T) {
+	assert.Equal(t, "<pre><code class=\"language-go\">if a &lt; b {\n}\n</code></pre>\n", render("```go\nif a < b {\n}\n```"))
+	assert.Equal(t, "<pre><code>x := 1\n</code></pre>\n", render("    x := 1"))
+	assert.Equal(t, "<p>вызови <code>**f**()</code></p>\n", render("вызови `**f**()`"))
+	assert.Equal(t, "<p>snake_case_name</p>\n", render("snake_case_name"))
+}
+
+func TestRender_Links(t *testing.T) {
+	assert.Equal(t, "<p><a href=\"https://example.com\" title=\"Пример\" rel=\"nofollow noopener noreferrer\">сайт</a></p>\n",
+		render("[сайт](https://example.com \"Пример\")"))
+	assert.Equal(t, "<p><a href=\"/topics/1\">тема</a></p>\n", render("[тема](/topics/1)"))
+	assert.Equal(t, "<p>см. <a href=\"https://go.dev\" rel=\"nofollow noopener noreferrer\">https://go.dev</a>.</p>\n", render("см. https://go.dev."))
+	assert.Equal(t, "<p><a>xss</a></p>\n", render("[xss](javascript:alert(1))"))
+}
+
+func TestRender_EscapesHTML(t *testing.T) {
+	assert.Equal(t, "<p>&lt;script&gt;alert(1)&lt;/script&gt; &amp; &lt;b&gt;</p>\n", render("<script>alert(1)</script> & <b>"))
+	assert.Equal(t, "", render("   \n"))
+}
+
+func TestRender_Limits(t *testing.T) {
+	renderer := NewRenderer(Options{MaxImages: 1, MinHeadingLevel: 3})
+
+	result := renderer.Render("# Большой\n\n![a](https://x/a.png) ![b](https://x/b.png)")
+
+	assert.Equal(t, "<h3>Большой</h3>\n<p><img src=\"https://x/a.png\" alt=\"a\"> "+
+		"<a href=\"https://x/b.png\" rel=\"nofollow noopener noreferrer\">b</a></p>\n", result)
+}
+
+func TestSanitize(t *testing.T) {
+	input := `<p onclick="x()">ok<script>bad()</script><iframe src="x"></iframe><span>text</span>` +
+		`<a href="JavaScript:alert(1)">a</a><img src="data:image/png;base64,AA"><code class="evil">c</code><em>open`
+
+	assert.Equal(t, "<p>oktext<a>a</a><code>c</code><em>open</em></p>", Sanitize(input, DefaultOptions))
+}
+
+func TestRender_QuoteBlock(t *testing.T) {
+	assert.Equal(t, "<p>Выше писали:</p>\n<blockquote data-message-id=\"m-1\">\n<p>оригинал</p>\n</blockquote>\n<p>согласен</p>\n",
+		render("Выше писали:\n[quote=m-1]\nоригинал\n[/quote]\nсогласен"))
+	assert.Equal(t, "<pre><code>[quote=m-1]\n</code></pre>\n", render("```\n[quote=m-1]\n```"))
+	assert.Equal(t, "<p>[quote=&#34;x&#34;]</p>\n", render(`[quote="x"]`))
+}
+
+func TestQuotes(t *testing.T) {
+	source := "intro\r\n[quote=a]\r\n[quote=b]\r\nnested\r\n[/quote]\r\n[/quote]\r\n```\n[quote=c]\n```\n[quote=d]\nunclosed"
+	quotes := Quotes(source)
+
+	assert.Len(t, quotes, 2)
+	assert.Equal(t, "a", quotes[0].MessageID)
+	assert.Equal(t, "[quote=a]\r\n[quote=b]\r\nnested\r\n[/quote]\r\n[/quote]", source[quotes[0].Start:quotes[0].End])
+	assert.Equal(t, "[quote=b]\nnested\n[/quote]", quotes[0].Body)
+	assert.Equal(t, "d", quotes[1].MessageID)
+	assert.Equal(t, "[quote=d]\nunclosed", source[quotes[1].Start:quotes[1].End])
+	assert.Equal(t, "unclosed", quotes[1].Body)
+}
@@ -0,0 +1,96 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	quoteOpenRe  = regexp.MustCompile(`^ {0,3}\[quote=([A-Za-z0-9-]{1,64})\][ \t]*$`)
+	quoteCloseRe = regexp.MustCompile(`^ {0,3}\[/quote\][ \t]*$`)
+)
+
+// Quote блок цитаты сообщения:
+//
+//	[quote=<id сообщения>]
+//	цитируемый текст
+//	[/quote]
+//
+// Start и End — байтовые смещения блока в исходном тексте вместе со строками [quote] и [/quote],
+// Body — цитируемый текст без этих строк.
+type Quote struct {
+	MessageID string
+	Start     int
+	End       int
+	Body      string
+}
+
+// Quotes находит в тексте блоки цитат верхнего уровня. Цитаты внутри цитат
+// и строки в блоках кода не учитываются. Незакрытый блок продолжается до конца текста.
+func Quotes(source string) []Quote {
+	lines := strings.Split(source, "\n")
+	starts := make([]int, len(lines)+1)
+	for i, line := range lines {
+		starts[i+1] = starts[i] + len(line) + 1
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	var quotes []Quote
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case isFence(line):
+			fence := fenceRe.FindStringSubmatch(line)[2]
+			for i++; i < len(lines) && !closesFence(lines[i], fence); i++ {
+			}
+			i++
+		case quoteOpenRe.MatchString(line):
+			end := quoteEnd(lines, i)
+			stop := len(source)
+			if end < len(lines) {
+				stop = starts[end] + len(lines[end])
+			}
+			quotes = append(quotes, Quote{
+				MessageID: quoteOpenRe.FindStringSubmatch(line)[1],
+				Start:     starts[i],
+				End:       stop,
+				Body:      strings.Join(lines[i+1:end], "\n"),
+			})
+			i = end + 1
+		default:
+			i++
+		}
+	}
+	return quotes
+}
+
+// renderQuoteBlock выводит блок цитаты как blockquote с id цитируемого сообщения
+func renderQuoteBlock(b *strings.Builder, lines []string, i, depth int) int {
+	id := quoteOpenRe.FindStringSubmatch(lines[i])[1]
+	end := quoteEnd(lines, i)
+
+	b.WriteString(`<blockquote data-message-id="` + id + `">` + "\n")
+	renderBlocks(b, lines[i+1:end], false, depth+1)
+	b.WriteString("</blockquote>\n")
+	if end < len(lines) {
+		end++
+	}
+	return end
+}
+
+// quoteEnd возвращает номер строки [/quote], закрывающей блок из строки i, с учетом вложенных цитат
+func quoteEnd(lines []string, i int) int {
+	depth := 1
+	for i++; i < len(lines); i++ {
+		switch {
+		case quoteOpenRe.MatchString(lines[i]):
+			depth++
+		case quoteCloseRe.MatchString(lines[i]):
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(lines)
+}
@@ -0,0 +1,248 @@
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// allowedTags теги, которые остаются в результате, и допустимые у них атрибуты
+var allowedTags = map[string][]string{
+	"p":          nil,
+	"br":         nil,
+	"hr":         nil,
+	"h1":         nil,
+	"h2":         nil,
+	"h3":         nil,
+	"h4":         nil,
+	"h5":         nil,
+	"h6":         nil,
+	"strong":     nil,
+	"em":         nil,
+	"del":        nil,
+	"code":       {"class"},
+	"pre":        nil,
+	"blockquote": {"data-message-id"},
+	"ul":         nil,
+	"ol":         {"start"},
+	"li":         nil,
+	"a":          {"href", "title"},
+	"img":        {"src", "alt", "title"},
+}
+
+// voidTags теги без закрывающей пары
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// droppedTags теги, которые удаляются вместе с содержимым
+var droppedTags = map[string]bool{"script": true, "style": true, "iframe": true, "object": true, "textarea": true, "title": true}
+
+var (
+	languageClassRe = regexp.MustCompile(`^language-[A-Za-z0-9_+-]+$`)
+	digitsRe        = regexp.MustCompile(`^\d{1,9}$`)
+	messageIDRe     = regexp.MustCompile(`^[A-Za-z0-9-]{1,64}$`)
+)
+
+// Sanitize оставляет в HTML только разрешенные теги и атрибуты. Ссылки и картинки
+// допускаются лишь с безопасными схемами, внешние ссылки получают rel="nofollow",
+// картинки сверх MaxImages заменяются ссылками, а заголовки крупнее MinHeadingLevel понижаются.
+func Sanitize(input string, opts Options) string {
+	var (
+		b       strings.Builder
+		open    []string
+		dropped string
+		images  int
+	)
+	z := nethtml.NewTokenizer(strings.NewReader(input))
+	for {
+		tt := z.Next()
+		if tt == nethtml.ErrorToken {
+			break
+		}
+		token := z.Token()
+		name := token.Data
+
+		if dropped != "" {
+			if tt == nethtml.EndTagToken && name == dropped {
+				dropped = ""
+			}
+			continue
+		}
+
+		switch tt {
+		case nethtml.TextToken:
+			b.WriteString(html.EscapeString(token.Data))
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			if droppedTags[name] {
+				if tt == nethtml.StartTagToken {
+					dropped = name
+				}
+				continue
+			}
+			if _, ok := allowedTags[name]; !ok {
+				continue
+			}
+			if name == "img" {
+				images++
+				if images > opts.MaxImages {
+					writeImageAsLink(&b, token)
+					continue
+				}
+			}
+			name = clampHeading(name, opts.MinHeadingLevel)
+			attrs, ok := sanitizeAttrs(token)
+			if !ok {
+				// Картинка без безопасного src не выводится вовсе
+				continue
+			}
+			b.WriteString("<" + name + attrs + ">")
+			if !voidTags[name] {
+				open = append(open, name)
+			}
+		case nethtml.EndTagToken:
+			if _, ok := allowedTags[name]; !ok || voidTags[name] {
+				continue
+			}
+			name = clampHeading(name, opts.MinHeadingLevel)
+			// Закрываем только открытый тег, попутно закрывая вложенные в него
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != name {
+					continue
+				}
+				for j := len(open) - 1; j >= i; j-- {
+					b.WriteString("</" + open[j] + ">")
+				}
+				open = open[:i]
+				break
+			}
+		}
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+	return b.String()
+}
+
+// sanitizeAttrs возвращает разрешенные атрибуты тега. Для img без безопасного src
+// возвращается false.
+func sanitizeAttrs(token nethtml.Token) (string, bool) {
+	var (
+		b        strings.Builder
+		external bool
+		hasSrc   bool
+	)
+	allowed := allowedTags[token.Data]
+	seen := make(map[string]bool)
+	for _, attr := range token.Attr {
+		key := attr.Key
+		if attr.Namespace != "" || seen[key] || !containsString(allowed, key) {
+			continue
+		}
+		value := attr.Val
+		switch {
+		case token.Data == "a" && key == "href":
+			u, ok := safeURL(value, true)
+			if !ok {
+				continue
+			}
+			external = u.IsAbs() || u.Host != ""
+		case token.Data == "img" && key == "src":
+			if _, ok := safeURL(value, false); !ok {
+				continue
+			}
+			hasSrc = true
+		case key == "class":
+			if !languageClassRe.MatchString(value) {
+				continue
+			}
+		case key == "start":
+			if !digitsRe.MatchString(value) {
+				continue
+			}
+		case key == "data-message-id":
+			if !messageIDRe.MatchString(value) {
+				continue
+			}
+		}
+		seen[key] = true
+		b.WriteString(" " + key + `="` + html.EscapeString(value) + `"`)
+	}
+	if token.Data == "img" && !hasSrc {
+		return "", false
+	}
+	if external {
+		b.WriteString(` rel="nofollow noopener noreferrer"`)
+	}
+	return b.String(), true
+}
+
+// safeURL разрешает http, https, для ссылок еще mailto, а также относительные адреса
+func safeURL(value string, link bool) (*url.URL, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, false
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u, u.Host != ""
+	case "mailto":
+		return u, link
+	case "":
+		// Относительный адрес не должен прятать схему за пробелами или управляющими символами
+		return u, !strings.ContainsAny(value, "\x00\t\n\r")
+	}
+	return nil, false
+}
+
+// writeImageAsLink выводит картинку сверх лимита как ссылку на нее
+func writeImageAsLink(b *strings.Builder, token nethtml.Token) {
+	var src, alt string
+	for _, attr := range token.Attr {
+		switch attr.Key {
+		case "src":
+			src = attr.Val
+		case "alt":
+			alt = attr.Val
+		}
+	}
+	if alt == "" {
+		alt = src
+	}
+	u, ok := safeURL(src, false)
+	if !ok {
+		b.WriteString(html.EscapeString(alt))
+		return
+	}
+	b.WriteString(`<a href="` + html.EscapeString(src) + `"`)
+	if u.IsAbs() {
+		b.WriteString(` rel="nofollow noopener noreferrer"`)
+	}
+	b.WriteString(">" + html.EscapeString(alt) + "</a>")
+}
+
+// clampHeading понижает заголовок до минимально допустимого уровня
+func clampHeading(name string, minLevel int) string {
+	if len(name) != 2 || name[0] != 'h' || name[1] < '1' || name[1] > '6' {
+		return name
+	}
+	if level := int(name[1] - '0'); level < minLevel {
+		return "h" + strconv.Itoa(minLevel)
+	}
+	return name
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// Объекты, к которым прикрепляются вложения
+const (
+	AttachmentTargetTopic   = "topic"
+	AttachmentTargetMessage = "message"
+)
+
+// Состояния вложения. Картинки после загрузки ждут обработки (pending): до ее окончания
+// их может скачать только загрузивший. Картинки, которые не удалось разобрать
+// или которые слишком велики, отклоняются (rejected).
+const (
+	AttachmentPending  = "pending"
+	AttachmentReady    = "ready"
+	AttachmentRejected = "rejected"
+	AttachmentFailed   = "failed"
+)
+
+// MaxAttachmentsPerPost сколько вложений можно прикрепить к одной теме или сообщению
+const MaxAttachmentsPerPost = 10
+
+// Attachment загруженный файл. Содержимое хранится в BlobStore под ключом SHA256,
+// поэтому одинаковые файлы занимают место один раз.
+type Attachment struct {
+	ID          string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID      string    `json:"user_id"`
+	Filename    string    `json:"filename" example:"screenshot.png"`
+	ContentType string    `json:"content_type" example:"image/png"`
+	Size        int64     `json:"size" example:"48213"`
+	SHA256      string    `json:"sha256"`
+	Status      string    `json:"status" example:"ready"`
+	Width       int       `json:"width,omitempty" example:"1920"`
+	Height      int       `json:"height,omitempty" example:"1080"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	Variants []*AttachmentVariant `json:"variants,omitempty"`
+}
+
+// AttachmentVariant обработанная копия картинки: очищенный от метаданных оригинал
+// или уменьшенная копия. Скачивается через GET /attachments/{id}?variant=name.
+type AttachmentVariant struct {
+	Name        string `json:"name" example:"thumb"`
+	ContentType string `json:"content_type" example:"image/jpeg"`
+	Size        int64  `json:"size" example:"12034"`
+	Width       int    `json:"width" example:"320"`
+	Height      int    `json:"height" example:"180"`
+	Key         string `json:"-"`
+}
+
+// AttachmentFile файл, который отдается при скачивании вложения или его варианта
+type AttachmentFile struct {
+	Filename    string
+	ContentType string
+	Size        int64
+	Key         string
+}
+
+// ImageJob задача очереди обработки картинок
+type ImageJob struct {
+	AttachmentID string
+	CommunityID  string
+	SHA256       string
+	Attempts     int
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Category раздел форума. Разделы образуют дерево через ParentID.
+type Category struct {
+	ID           string      `json:"id"`
+	ParentID     string      `json:"parent_id,omitempty"`
+	Name         string      `json:"name" example:"Go"`
+	Slug         string      `json:"slug" example:"go"`
+	Description  string      `json:"description,omitempty"`
+	SortOrder    int         `json:"sort_order" example:"10"`
+	PostRole     string      `json:"post_role" example:"user"`
+	TopicCount   int         `json:"topic_count" example:"12"`
+	MessageCount int         `json:"message_count" example:"340"`
+	CreatedAt    time.Time   `json:"created_at"`
+	Children     []*Category `json:"children,omitempty"`
+}
+
+// CategoryRequest модель запроса создания и изменения раздела.
+// PostRole задает минимальную роль в сообществе, необходимую для создания тем.
+type CategoryRequest struct {
+	Name        string `json:"name" binding:"required,max=100" example:"Go"`
+	Slug        string `json:"slug" binding:"required,max=64" example:"go"`
+	Description string `json:"description" binding:"max=1000"`
+	ParentID    string `json:"parent_id,omitempty"`
+	SortOrder   int    `json:"sort_order" example:"10"`
+	PostRole    string `json:"post_role,omitempty" binding:"oneof=user moderator admin" example:"admin"`
+}
+
+// TopicFilter условия отбора тем в списке
+type TopicFilter struct {
+	// CategoryID включает темы раздела и всех его подразделов
+	CategoryID string
+	// Tags оставляет только темы, у которых есть все перечисленные теги
+	Tags []string
+	// IncludeArchived добавляет в список архивные темы
+	IncludeArchived bool
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// MaxConversationMembers максимальное число участников личной переписки, включая создателя
+const MaxConversationMembers = 10
+
+// Conversation личная переписка двух или нескольких пользователей
+type Conversation struct {
+	ID            string     `json:"id"`
+	Members       []string   `json:"members"`
+	CreatedBy     string     `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+	LastMessageAt *time.Time `json:"last_message_at,omitempty"`
+	Unread        int        `json:"unread" example:"2"`
+}
+
+// ConversationRequest модель запроса создания переписки.
+// Для переписки двух пользователей возвращается уже существующая.
+type ConversationRequest struct {
+	UserIDs []string `json:"user_ids" binding:"required,min=1,max=9"`
+}
+
+// DirectMessage сообщение в личной переписке
+type DirectMessage struct {
+	ID             string    `json:"id"`
+	ConversationID string    `json:"conversation_id"`
+	UserID         string    `json:"user_id"`
+	Content        string    `json:"content"`
+	ContentHTML    string    `json:"content_html"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// DirectMessageRequest модель запроса отправки личного сообщения
+type DirectMessageRequest struct {
+	Content string `json:"content" binding:"required,max=10000" example:"Привет!"`
+}
+
+// DirectMessagePage страница истории переписки
+type DirectMessagePage struct {
+	Items      []*DirectMessage
+	NextCursor string
+}
+
+// DirectMessageEventType тип события DirectMessageEvent
+const DirectMessageEventType = "direct_message"
+
+// DirectMessageEvent доставляет личное сообщение в соединения участников переписки
+type DirectMessageEvent struct {
+	Type    string         `json:"type" example:"direct_message"`
+	Message *DirectMessage `json:"message"`
+}
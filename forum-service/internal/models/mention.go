@@ -0,0 +1,27 @@
+package models
+
+// Mention упоминание пользователя в сообщении
+type Mention struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username" example:"john"`
+}
+
+// Типы размеченных фрагментов текста сообщения
+const (
+	EntityMention = "mention"
+	EntityQuote   = "quote"
+	EntityLink    = "link"
+)
+
+// MessageEntity размеченный фрагмент текста сообщения.
+// Offset и Length считаются в символах (рунах); у упоминания включают символ @,
+// у цитаты — строки [quote=id] и [/quote]. У цитаты UserID — автор цитируемого сообщения.
+type MessageEntity struct {
+	Type      string `json:"type" example:"mention"`
+	Offset    int    `json:"offset" example:"6"`
+	Length    int    `json:"length" example:"5"`
+	UserID    string `json:"user_id,omitempty"`
+	Username  string `json:"username,omitempty" example:"john"`
+	TopicID   string `json:"topic_id,omitempty"`
+	MessageID string `json:"message_id,omitempty"`
+}
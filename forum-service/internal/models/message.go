@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/luckermt/forum-app/shared/pkg/models"
+)
+
+// MessageDetail сообщение с HTML текста и отметками о правке и удалении.
+// У удаленного сообщения остается только заглушка: текст пустой, Deleted = true.
+type MessageDetail struct {
+	models.Message
+	ContentHTML string           `json:"content_html"`
+	EditedAt    *time.Time       `json:"edited_at,omitempty"`
+	Deleted     bool             `json:"deleted,omitempty"`
+	Score       int              `json:"score"`
+	Reactions   []*ReactionCount `json:"reactions,omitempty"`
+	Entities    []*MessageEntity `json:"entities,omitempty"`
+	Attachments []*Attachment    `json:"attachments,omitempty"`
+}
+
+// MessageUpdateRequest модель запроса правки сообщения
+type MessageUpdateRequest struct {
+	Content string `json:"content" binding:"required,max=10000" example:"Исправленный текст"`
+}
+
+// MessageRevision предыдущая версия сообщения
+type MessageRevision struct {
+	Content   string    `json:"content"`
+	EditedBy  string    `json:"edited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Типы событий, которые рассылаются клиентам при изменении сообщений
+const (
+	MessageEventCreated = "message_created"
+	MessageEventEdited  = "message_edited"
+	MessageEventDeleted = "message_deleted"
+)
+
+// MessageEvent уведомление клиентов чата и слушателей темы о новом, измененном или удаленном сообщении
+type MessageEvent struct {
+	Type    string         `json:"type" example:"message_created"`
+	Message *MessageDetail `json:"message"`
+}
+
+// MaxContentLength наибольшая длина текста темы или сообщения в символах
+const MaxContentLength = 10000
+
+// MessageRequest модель запроса ответа в теме
+type MessageRequest struct {
+	Content       string   `json:"content" binding:"required,max=10000" example:"Согласен, стоит попробовать"`
+	ParentID      string   `json:"parent_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	AttachmentIDs []string `json:"attachment_ids,omitempty" binding:"max=10"`
+}
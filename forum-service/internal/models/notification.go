@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Типы уведомлений
+const (
+	NotificationMention    = "mention"     // пользователя упомянули в сообщении
+	NotificationReply      = "reply"       // ответ в теме пользователя или на его сообщение
+	NotificationModeration = "moderation"  // модератор изменил контент пользователя
+	NotificationTopicReply = "topic_reply" // новый ответ в теме, за которой пользователь следит
+)
+
+// Действия модераторов, о которых сообщают уведомления типа moderation
+const (
+	ModerationTopicEdited       = "topic_edited"
+	ModerationTopicStateChanged = "topic_state_changed"
+	ModerationTopicDeleted      = "topic_deleted"
+	ModerationMessageEdited     = "message_edited"
+	ModerationMessageDeleted    = "message_deleted"
+)
+
+// Notification уведомление пользователя о событии в сообществе
+type Notification struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	Type      string     `json:"type" example:"mention"`
+	Action    string     `json:"action,omitempty" example:"topic_edited"`
+	ActorID   string     `json:"actor_id,omitempty"`
+	TopicID   string     `json:"topic_id,omitempty"`
+	MessageID string     `json:"message_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+}
+
+// NotificationPage страница списка уведомлений
+type NotificationPage struct {
+	Items      []*Notification
+	NextCursor string
+}
+
+// NotificationReadRequest модель запроса отметки уведомлений прочитанными.
+// Пустой список отмечает все уведомления.
+type NotificationReadRequest struct {
+	IDs []string `json:"ids,omitempty" binding:"max=100"`
+}
+
+// UnreadCount число непрочитанных уведомлений
+type UnreadCount struct {
+	Unread int `json:"unread" example:"3"`
+}
+
+// Типы событий об уведомлениях, которые получают соединения пользователя
+const (
+	NotificationEventCreated = "notification"
+	NotificationEventRead    = "notifications_read"
+)
+
+// NotificationEvent доставляет уведомление в открытые WebSocket-соединения получателя
+// вместе с актуальным числом непрочитанных
+type NotificationEvent struct {
+	Type         string        `json:"type" example:"notification"`
+	Notification *Notification `json:"notification,omitempty"`
+	Unread       int           `json:"unread" example:"3"`
+}
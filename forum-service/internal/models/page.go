@@ -0,0 +1,70 @@
+package models
+
+import (
+	"time"
+
+	"github.com/luckermt/forum-app/shared/pkg/models"
+)
+
+// Режимы сортировки списков
+const (
+	SortNewest = "newest"
+	SortOldest = "oldest"
+	SortActive = "active" // темы с наибольшим числом сообщений
+	SortTop    = "top"    // наибольший рейтинг по голосам
+)
+
+// Ограничения размера страницы
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// PageParams параметры страницы в том виде, в котором их передает клиент
+type PageParams struct {
+	Sort   string
+	Cursor string
+	Limit  int
+}
+
+// PageCursor позиция в списке для keyset-пагинации по (created_at, id).
+// Для сортировки active дополнительно учитывается число сообщений,
+// для тем — закрепление.
+type PageCursor struct {
+	Sort         string    `json:"s"`
+	CreatedAt    time.Time `json:"t"`
+	ID           string    `json:"i"`
+	MessageCount int       `json:"c,omitempty"`
+	Pinned       bool      `json:"p,omitempty"`
+	Score        int       `json:"v,omitempty"`
+}
+
+// PageQuery разобранные параметры страницы для репозитория
+type PageQuery struct {
+	Sort  string
+	Limit int
+	After *PageCursor
+}
+
+// TopicSummary тема в списке вместе с числом сообщений
+type TopicSummary struct {
+	models.Topic
+	ContentHTML  string   `json:"content_html"`
+	CategoryID   string   `json:"category_id,omitempty"`
+	Tags         []string `json:"tags"`
+	MessageCount int      `json:"message_count" example:"42"`
+	Score        int      `json:"score" example:"7"`
+	TopicState
+}
+
+// TopicPage страница списка тем
+type TopicPage struct {
+	Items      []*TopicSummary
+	NextCursor string
+}
+
+// MessagePage страница списка сообщений
+type MessagePage struct {
+	Items      []*MessageDetail
+	NextCursor string
+}
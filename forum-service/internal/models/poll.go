@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Ограничения опроса
+const (
+	MaxPollOptions        = 10
+	MaxPollQuestionLength = 300
+	MaxPollOptionLength   = 100
+)
+
+// PollRequest опрос, который создается вместе с темой
+type PollRequest struct {
+	Question string   `json:"question" example:"Какой язык выбрать для нового сервиса?"`
+	Options  []string `json:"options" example:"Go,Rust,Kotlin"`
+	// Multiple разрешает выбрать несколько вариантов
+	Multiple bool `json:"multiple" example:"false"`
+	// Anonymous скрывает, кто за что проголосовал
+	Anonymous bool `json:"anonymous" example:"true"`
+	// HideResults скрывает результаты до закрытия опроса
+	HideResults bool       `json:"hide_results" example:"false"`
+	ClosesAt    *time.Time `json:"closes_at,omitempty" example:"2025-01-31T18:00:00Z"`
+}
+
+// Poll опрос темы. Пока результаты скрыты (results_hidden), у вариантов нет счетчиков
+// и голосовавших, а общее число проголосовавших voters равно нулю.
+type Poll struct {
+	ID            string        `json:"id"`
+	TopicID       string        `json:"topic_id"`
+	Question      string        `json:"question"`
+	Multiple      bool          `json:"multiple"`
+	Anonymous     bool          `json:"anonymous"`
+	HideResults   bool          `json:"hide_results"`
+	ClosesAt      *time.Time    `json:"closes_at,omitempty"`
+	Closed        bool          `json:"closed"`
+	ResultsHidden bool          `json:"results_hidden"`
+	Voters        int           `json:"voters" example:"12"`
+	Options       []*PollOption `json:"options"`
+	MyVotes       []string      `json:"my_votes,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// PollOption вариант ответа. Voters заполняется только в открытых (не анонимных) опросах.
+type PollOption struct {
+	ID     string   `json:"id"`
+	Text   string   `json:"text" example:"Go"`
+	Votes  *int     `json:"votes,omitempty" example:"7"`
+	Voters []string `json:"voters,omitempty"`
+}
+
+// PollVoteRequest модель запроса голосования в опросе
+type PollVoteRequest struct {
+	OptionIDs []string `json:"option_ids" binding:"required,max=10"`
+}
+
+// PollEventUpdated рассылается слушателям темы после каждого голоса
+const PollEventUpdated = "poll_updated"
+
+// PollEvent событие об изменении результатов опроса, без my_votes
+type PollEvent struct {
+	Type    string `json:"type" example:"poll_updated"`
+	TopicID string `json:"topic_id"`
+	Poll    *Poll  `json:"poll"`
+}
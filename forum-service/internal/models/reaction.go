@@ -0,0 +1,43 @@
+package models
+
+// Объекты, на которые можно реагировать
+const (
+	ReactionTargetTopic   = "topic"
+	ReactionTargetMessage = "message"
+)
+
+// ReactionEmoji допустимые реакции: короткое имя и соответствующий эмодзи
+var ReactionEmoji = map[string]string{
+	"+1":       "👍",
+	"-1":       "👎",
+	"heart":    "❤️",
+	"laugh":    "😄",
+	"hooray":   "🎉",
+	"confused": "😕",
+	"eyes":     "👀",
+	"rocket":   "🚀",
+}
+
+// ReactionCount число реакций одного вида на объект
+type ReactionCount struct {
+	Emoji       string `json:"emoji" example:"+1"`
+	Count       int    `json:"count" example:"3"`
+	ReactedByMe bool   `json:"reacted_by_me"`
+}
+
+// Типы событий об изменении реакций
+const (
+	ReactionEventAdded   = "reaction_added"
+	ReactionEventRemoved = "reaction_removed"
+)
+
+// ReactionEvent рассылается клиентам чата и слушателям темы при изменении реакций.
+// Reactions содержит итоговые счетчики объекта без признака reacted_by_me.
+type ReactionEvent struct {
+	Type       string           `json:"type" example:"reaction_added"`
+	TargetType string           `json:"target_type" example:"message"`
+	TargetID   string           `json:"target_id"`
+	Emoji      string           `json:"emoji" example:"+1"`
+	UserID     string           `json:"user_id"`
+	Reactions  []*ReactionCount `json:"reactions"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Виды ссылок между темами и сообщениями
+const (
+	ReferenceLink  = "link"
+	ReferenceQuote = "quote"
+)
+
+// Объекты, которые ссылаются и на которые ссылаются
+const (
+	ReferenceTopic   = "topic"
+	ReferenceMessage = "message"
+)
+
+// Reference ссылка или цитата в тексте темы или сообщения. Автор и тема цитируемого объекта
+// запоминаются при сохранении, поэтому подпись цитаты не меняется после правки или удаления оригинала.
+type Reference struct {
+	Kind           string
+	TargetType     string
+	TargetID       string
+	TargetTopicID  string
+	TargetAuthorID string
+}
+
+// Backlink место, где упомянута тема или ее сообщения
+type Backlink struct {
+	Kind       string    `json:"kind" example:"quote"`
+	SourceType string    `json:"source_type" example:"message"`
+	SourceID   string    `json:"source_id"`
+	TopicID    string    `json:"topic_id,omitempty"`
+	TopicTitle string    `json:"topic_title,omitempty" example:"Выбор языка"`
+	AuthorID   string    `json:"author_id"`
+	TargetType string    `json:"target_type" example:"message"`
+	TargetID   string    `json:"target_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Типы результатов поиска
+const (
+	SearchTypeAll      = "all"
+	SearchTypeTopics   = "topics"
+	SearchTypeMessages = "messages"
+)
+
+// Конфигурации текстового поиска Postgres, которые поддерживает форум
+const (
+	SearchConfigSimple  = "simple"
+	SearchConfigEnglish = "english"
+	SearchConfigRussian = "russian"
+)
+
+// SearchQuery параметры полнотекстового поиска
+type SearchQuery struct {
+	Query    string
+	Type     string
+	AuthorID string
+	TopicID  string
+	From     *time.Time
+	To       *time.Time
+	Language string
+	Limit    int
+	Offset   int
+}
+
+// SearchResult найденная тема или сообщение.
+// В Snippet совпадения выделены тегами <mark>, остальной текст экранирован.
+type SearchResult struct {
+	Type      string    `json:"type" example:"message"`
+	ID        string    `json:"id"`
+	TopicID   string    `json:"topic_id"`
+	Title     string    `json:"title"`
+	Snippet   string    `json:"snippet" example:"как настроить <mark>grpc</mark> клиент"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Rank      float64   `json:"rank" example:"0.42"`
+}
@@ -0,0 +1,34 @@
+package models
+
+// Ограничения тегов
+const (
+	MaxTagLength    = 32
+	DefaultMaxTags  = 5
+	MaxTagsPerTopic = 10
+)
+
+// Tag тег сообщества. Official теги заданы администратором и
+// единственно допустимые, если сообщество ограничило список тегов.
+type Tag struct {
+	Name       string `json:"name" example:"grpc"`
+	Official   bool   `json:"official"`
+	SynonymOf  string `json:"synonym_of,omitempty"`
+	TopicCount int    `json:"topic_count" example:"17"`
+}
+
+// TagSettings правила использования тегов в сообществе
+type TagSettings struct {
+	Restricted bool `json:"restricted"`
+	MaxTags    int  `json:"max_tags" binding:"required,min=1,max=10" example:"5"`
+}
+
+// TagRequest модель запроса добавления тега в список разрешенных
+type TagRequest struct {
+	Name string `json:"name" binding:"required,max=32" example:"grpc"`
+}
+
+// TagMergeRequest модель запроса слияния тега с другим.
+// Исходный тег становится синонимом целевого.
+type TagMergeRequest struct {
+	Into string `json:"into" binding:"required,max=32" example:"golang"`
+}
@@ -0,0 +1,45 @@
+package models
+
+const (
+	// MaxThreadDepth максимальная вложенность ответов; корневые сообщения имеют глубину 0
+	MaxThreadDepth = 8
+	// DefaultThreadLevels сколько уровней дерева возвращается по умолчанию
+	DefaultThreadLevels = 3
+)
+
+// Форматы выдачи ветки обсуждения
+const (
+	ThreadFormatTree = "tree"
+	ThreadFormatFlat = "flat"
+)
+
+// ThreadMessage сообщение темы с местом в дереве ответов
+type ThreadMessage struct {
+	MessageDetail
+	ParentID   string           `json:"parent_id,omitempty"`
+	Depth      int              `json:"depth" example:"1"`
+	ReplyCount int              `json:"reply_count" example:"3"`
+	Replies    []*ThreadMessage `json:"replies,omitempty"`
+}
+
+// ThreadMessageEvent сообщает слушателям темы о новом ответе вместе с его местом в ветке
+type ThreadMessageEvent struct {
+	Type    string         `json:"type" example:"message_created"`
+	Message *ThreadMessage `json:"message"`
+}
+
+// ThreadParams параметры выборки ветки обсуждения.
+// Пагинация применяется к прямым ответам на ParentID (или к корневым сообщениям темы),
+// для каждого из них возвращается до Levels-1 уровней вложенных ответов.
+type ThreadParams struct {
+	PageParams
+	ParentID string
+	Levels   int
+	Format   string
+}
+
+// ThreadPage страница ветки обсуждения
+type ThreadPage struct {
+	Items      []*ThreadMessage
+	NextCursor string
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/luckermt/forum-app/shared/pkg/models"
+)
+
+// TopicDetail тема вместе с номером текущей ревизии
+type TopicDetail struct {
+	models.Topic
+	ContentHTML string           `json:"content_html" example:"<p>Текст темы</p>"`
+	CategoryID  string           `json:"category_id,omitempty"`
+	Tags        []string         `json:"tags"`
+	Revision    int              `json:"revision" example:"2"`
+	UpdatedAt   *time.Time       `json:"updated_at,omitempty"`
+	Score       int              `json:"score" example:"7"`
+	Attachments []*Attachment    `json:"attachments,omitempty"`
+	Poll        *Poll            `json:"poll,omitempty"`
+	Entities    []*MessageEntity `json:"entities,omitempty"`
+	TopicState
+}
+
+// TopicState состояние темы, которое меняют модераторы.
+// Закрепленные темы идут первыми в списках, в закрытые нельзя писать,
+// архивные доступны только для чтения и не показываются в списках по умолчанию.
+type TopicState struct {
+	Pinned   bool `json:"pinned"`
+	Locked   bool `json:"locked"`
+	Archived bool `json:"archived"`
+}
+
+// TopicStateRequest модель запроса смены состояния темы.
+// Незаданные поля остаются без изменений.
+type TopicStateRequest struct {
+	Pinned   *bool `json:"pinned,omitempty" example:"true"`
+	Locked   *bool `json:"locked,omitempty" example:"false"`
+	Archived *bool `json:"archived,omitempty" example:"false"`
+}
+
+// CreateTopicRequest модель запроса создания темы
+type CreateTopicRequest struct {
+	models.TopicRequest
+	CategoryID    string       `json:"category_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Tags          []string     `json:"tags,omitempty" binding:"max=10" example:"go,grpc"`
+	AttachmentIDs []string     `json:"attachment_ids,omitempty" binding:"max=10"`
+	Poll          *PollRequest `json:"poll,omitempty"`
+}
+
+// TopicUpdateRequest модель запроса редактирования темы.
+// Незаданные поля остаются без изменений.
+type TopicUpdateRequest struct {
+	Title   *string `json:"title,omitempty" binding:"max=100" example:"Исправленный заголовок"`
+	Content *string `json:"content,omitempty" binding:"max=10000" example:"Исправленный текст"`
+}
+
+// TopicRevision сохраненная версия темы
+type TopicRevision struct {
+	TopicID   string    `json:"topic_id"`
+	Revision  int       `json:"revision" example:"1"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	EditedBy  string    `json:"edited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Операции строки диффа
+const (
+	DiffEqual  = "equal"
+	DiffInsert = "insert"
+	DiffDelete = "delete"
+)
+
+// DiffLine строка построчного диффа
+type DiffLine struct {
+	Op   string `json:"op" example:"insert"`
+	Text string `json:"text"`
+}
+
+// TopicDiff разница между двумя ревизиями темы
+type TopicDiff struct {
+	TopicID string     `json:"topic_id"`
+	From    int        `json:"from" example:"1"`
+	To      int        `json:"to" example:"2"`
+	Title   []DiffLine `json:"title"`
+	Content []DiffLine `json:"content"`
+}
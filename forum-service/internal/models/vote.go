@@ -0,0 +1,26 @@
+package models
+
+// Объекты, за которые можно голосовать
+const (
+	VoteTargetTopic   = "topic"
+	VoteTargetMessage = "message"
+)
+
+// VoteRequest модель запроса голосования: 1 — за, -1 — против, 0 — отменить голос
+type VoteRequest struct {
+	Value int `json:"value" binding:"min=-1,max=1" example:"1"`
+}
+
+// VoteResult итоговый рейтинг объекта и голос текущего пользователя
+type VoteResult struct {
+	Score  int `json:"score" example:"12"`
+	MyVote int `json:"my_vote" example:"1"`
+}
+
+// Karma репутация пользователя, набранная голосами за его темы и сообщения
+type Karma struct {
+	UserID    string `json:"user_id"`
+	Karma     int64  `json:"karma" example:"42"`
+	Upvotes   int64  `json:"upvotes" example:"50"`
+	Downvotes int64  `json:"downvotes" example:"8"`
+}
@@ -0,0 +1,40 @@
+package models
+
+// Объекты, за которыми можно следить
+const (
+	WatchTargetTopic    = "topic"
+	WatchTargetCategory = "category"
+)
+
+// Уровни слежения. Без явно выбранного уровня действует обычный (normal):
+// уведомления приходят только об упоминаниях и ответах пользователю.
+const (
+	WatchWatching = "watching" // уведомление о каждом новом ответе
+	WatchTracking = "tracking" // событие о новом ответе в открытые соединения без уведомления
+	WatchNormal   = "normal"
+	WatchMuted    = "muted" // никаких уведомлений о теме, кроме действий модераторов
+)
+
+// WatchRequest модель запроса смены уровня слежения
+type WatchRequest struct {
+	Level string `json:"level" binding:"required,oneof=watching tracking muted" example:"watching"`
+}
+
+// Watch действующий уровень слежения пользователя. Source показывает,
+// откуда взят уровень: из настройки темы, ее раздела или по умолчанию (пусто).
+type Watch struct {
+	Level  string `json:"level" example:"watching"`
+	Source string `json:"source,omitempty" example:"topic"`
+}
+
+// TopicActivityEvent рассылается в соединения пользователей, отслеживающих тему (tracking),
+// когда в ней появляется новый ответ
+type TopicActivityEvent struct {
+	Type      string `json:"type" example:"topic_activity"`
+	TopicID   string `json:"topic_id"`
+	MessageID string `json:"message_id"`
+	UserID    string `json:"user_id"`
+}
+
+// TopicActivityEventType тип события TopicActivityEvent
+const TopicActivityEventType = "topic_activity"
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// attachmentColumns колонки, которые читает scanAttachment
+const attachmentColumns = `a.id, a.user_id, a.filename, a.content_type, a.size, a.sha256,
+                           a.status, COALESCE(a.width, 0), COALESCE(a.height, 0), a.created_at`
+
+// CreateAttachment сохраняет сведения о загруженном файле. Для вложения в состоянии pending
+// в той же транзакции ставится задача в очередь обработки картинок.
+func (r *PostgresRepository) CreateAttachment(communityID string, attachment *forummodels.Attachment) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO attachments (id, community_id, user_id, filename, content_type, size, sha256, status, created_at)
+	                  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		attachment.ID, communityID, attachment.UserID, attachment.Filename,
+		attachment.ContentType, attachment.Size, attachment.SHA256, attachment.Status, attachment.CreatedAt)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if attachment.Status == forummodels.AttachmentPending {
+		_, err = tx.Exec(`INSERT INTO image_jobs (attachment_id, run_after, created_at) VALUES ($1, $2, $2)`,
+			attachment.ID, attachment.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresRepository) GetAttachment(communityID, attachmentID string) (*forummodels.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments a WHERE a.id = $1 AND a.community_id = $2`
+	attachment, err := scanAttachment(r.db.QueryRow(query, attachmentID, communityID))
+	if err == sql.ErrNoRows {
+		return nil, ErrAttachmentNotFound
+	}
+	return attachment, err
+}
+
+// GetAttachments возвращает найденные вложения из списка; отсутствующие пропускаются
+func (r *PostgresRepository) GetAttachments(communityID string, ids []string) ([]*forummodels.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments a WHERE a.community_id = $1 AND a.id = ANY($2)`
+	rows, err := r.db.Query(query, communityID, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []*forummodels.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, rows.Err()
+}
+
+// GetTargetAttachments возвращает вложения набора тем или сообщений в порядке прикрепления
+func (r *PostgresRepository) GetTargetAttachments(communityID, targetType string, targetIDs []string) (map[string][]*forummodels.Attachment, error) {
+	query := `SELECT ar.target_id, ` + attachmentColumns + `
+	          FROM attachment_refs ar JOIN attachments a ON a.id = ar.attachment_id
+	          WHERE a.community_id = $1 AND ar.target_type = $2 AND ar.target_id = ANY($3)
+	          ORDER BY ar.target_id, ar.position`
+	rows, err := r.db.Query(query, communityID, targetType, pq.Array(targetIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := make(map[string][]*forummodels.Attachment)
+	for rows.Next() {
+		var targetID string
+		var attachment forummodels.Attachment
+		err := rows.Scan(&targetID, &attachment.ID, &attachment.UserID, &attachment.Filename,
+			&attachment.ContentType, &attachment.Size, &attachment.SHA256,
+			&attachment.Status, &attachment.Width, &attachment.Height, &attachment.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		attachments[targetID] = append(attachments[targetID], &attachment)
+	}
+	return attachments, rows.Err()
+}
+
+// IsAttachmentVisible сообщает, что вложение прикреплено к неудаленной теме
+// или неудаленному сообщению и поэтому доступно участникам сообщества
+func (r *PostgresRepository) IsAttachmentVisible(communityID, attachmentID string) (bool, error) {
+	query := `SELECT EXISTS (
+	              SELECT 1 FROM attachment_refs ar
+	              LEFT JOIN topics t ON ar.target_type = 'topic' AND t.id = ar.target_id
+	              LEFT JOIN messages m ON ar.target_type = 'message' AND m.id = ar.target_id
+	              WHERE ar.attachment_id = $1
+	                AND ((t.id IS NOT NULL AND t.community_id = $2 AND t.deleted = false)
+	                  OR (m.id IS NOT NULL AND m.community_id = $2 AND m.deleted_at IS NULL)))`
+	var visible bool
+	err := r.db.QueryRow(query, attachmentID, communityID).Scan(&visible)
+	return visible, err
+}
+
+// GetAttachmentVariants возвращает обработанные копии вложений, от меньшей к большей
+func (r *PostgresRepository) GetAttachmentVariants(attachmentIDs []string) (map[string][]*forummodels.AttachmentVariant, error) {
+	query := `SELECT attachment_id, name, blob_key, content_type, size, width, height
+	          FROM attachment_variants WHERE attachment_id = ANY($1)
+	          ORDER BY attachment_id, width * height, name`
+	rows, err := r.db.Query(query, pq.Array(attachmentIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := make(map[string][]*forummodels.AttachmentVariant)
+	for rows.Next() {
+		var attachmentID string
+		var variant forummodels.AttachmentVariant
+		err := rows.Scan(&attachmentID, &variant.Name, &variant.Key, &variant.ContentType,
+			&variant.Size, &variant.Width, &variant.Height)
+		if err != nil {
+			return nil, err
+		}
+		variants[attachmentID] = append(variants[attachmentID], &variant)
+	}
+	return variants, rows.Err()
+}
+
+// ClaimImageJob забирает из очереди следующую готовую к запуску задачу и блокирует ее на lease.
+// Задачи, чья блокировка истекла (воркер упал), забираются повторно. Если задач нет, возвращает nil.
+func (r *PostgresRepository) ClaimImageJob(at time.Time, lease time.Duration) (*forummodels.ImageJob, error) {
+	query := `WITH next AS (
+	              SELECT attachment_id FROM image_jobs
+	              WHERE run_after <= $1 AND (locked_until IS NULL OR locked_until < $1)
+	              ORDER BY run_after
+	              LIMIT 1
+	              FOR UPDATE SKIP LOCKED
+	          )
+	          UPDATE image_jobs j SET attempts = j.attempts + 1, locked_until = $2
+	          FROM next, attachments a
+	          WHERE j.attachment_id = next.attachment_id AND a.id = j.attachment_id
+	          RETURNING j.attachment_id, a.community_id, a.sha256, j.attempts`
+	var job forummodels.ImageJob
+	err := r.db.QueryRow(query, at, at.Add(lease)).Scan(&job.AttachmentID, &job.CommunityID, &job.SHA256, &job.Attempts)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// CompleteImageJob сохраняет результат обработки картинки, переводит вложение в состояние ready
+// и убирает задачу из очереди
+func (r *PostgresRepository) CompleteImageJob(attachmentID string, width, height int, variants []*forummodels.AttachmentVariant) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM attachment_variants WHERE attachment_id = $1`, attachmentID); err != nil {
+		return err
+	}
+	for _, variant := range variants {
+		_, err := tx.Exec(`INSERT INTO attachment_variants (attachment_id, name, blob_key, content_type, size, width, height)
+		                   VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			attachmentID, variant.Name, variant.Key, variant.ContentType, variant.Size, variant.Width, variant.Height)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE attachments SET status = $2, width = $3, height = $4 WHERE id = $1`,
+		attachmentID, forummodels.AttachmentReady, width, height)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM image_jobs WHERE attachment_id = $1`, attachmentID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FailImageJob переводит вложение в итоговое состояние rejected или failed и убирает задачу из очереди
+func (r *PostgresRepository) FailImageJob(attachmentID, status string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE attachments SET status = $2 WHERE id = $1`, attachmentID, status); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM image_jobs WHERE attachment_id = $1`, attachmentID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RetryImageJob снимает блокировку с задачи и откладывает ее следующий запуск
+func (r *PostgresRepository) RetryImageJob(attachmentID string, runAfter time.Time, lastError string) error {
+	_, err := r.db.Exec(`UPDATE image_jobs SET run_after = $2, locked_until = NULL, last_error = $3
+	                     WHERE attachment_id = $1`, attachmentID, runAfter, lastError)
+	return err
+}
+
+// insertAttachmentRefs прикрепляет вложения к теме или сообщению в транзакции их создания
+func insertAttachmentRefs(tx *sql.Tx, targetType, targetID string, attachments []*forummodels.Attachment) error {
+	for i, attachment := range attachments {
+		_, err := tx.Exec(`INSERT INTO attachment_refs (attachment_id, target_type, target_id, position)
+		                   VALUES ($1, $2, $3, $4)`, attachment.ID, targetType, targetID, i)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanAttachment(row rowScanner) (*forummodels.Attachment, error) {
+	var attachment forummodels.Attachment
+	err := row.Scan(&attachment.ID, &attachment.UserID, &attachment.Filename,
+		&attachment.ContentType, &attachment.Size, &attachment.SHA256,
+		&attachment.Status, &attachment.Width, &attachment.Height, &attachment.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// Код ошибки Postgres foreign_key_violation
+const foreignKeyViolation = "23503"
+
+func (r *PostgresRepository) CreateCategory(communityID string, category *forummodels.Category) error {
+	query := `INSERT INTO categories (id, community_id, parent_id, name, slug, description, sort_order, post_role, created_at)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := r.db.Exec(query,
+		category.ID,
+		communityID,
+		nullString(category.ParentID),
+		category.Name,
+		category.Slug,
+		category.Description,
+		category.SortOrder,
+		category.PostRole,
+		category.CreatedAt,
+	)
+	return translateError(err)
+}
+
+func (r *PostgresRepository) UpdateCategory(communityID string, category *forummodels.Category) error {
+	query := `UPDATE categories
+	          SET parent_id = $1, name = $2, slug = $3, description = $4, sort_order = $5, post_role = $6
+	          WHERE id = $7 AND community_id = $8`
+	res, err := r.db.Exec(query,
+		nullString(category.ParentID),
+		category.Name,
+		category.Slug,
+		category.Description,
+		category.SortOrder,
+		category.PostRole,
+		category.ID,
+		communityID,
+	)
+	if err != nil {
+		return translateError(err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+// DeleteCategory удаляет пустой раздел. Если в нем есть темы или подразделы,
+// возвращает ErrCategoryNotEmpty. Удаленные темы раздел не удерживают:
+// они остаются без раздела.
+func (r *PostgresRepository) DeleteCategory(communityID, categoryID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`UPDATE topics SET category_id = NULL
+	                  WHERE category_id = $1 AND community_id = $2 AND deleted = true`, categoryID, communityID)
+	if err != nil {
+		return err
+	}
+
+	res, err := tx.Exec(`DELETE FROM categories WHERE id = $1 AND community_id = $2`, categoryID, communityID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return ErrCategoryNotEmpty
+	}
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrCategoryNotFound
+	}
+	return tx.Commit()
+}
+
+// categoryStatsQuery выбирает разделы вместе с количеством тем и сообщений
+// во всем поддереве каждого раздела. Условие where ограничивает корни поддеревьев.
+const categoryStatsQuery = `
+	WITH RECURSIVE subtree(root_id, id) AS (
+	    SELECT c.id, c.id FROM categories c WHERE %s
+	    UNION ALL
+	    SELECT s.root_id, c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+	)
+	SELECT c.id, c.parent_id, c.name, c.slug, c.description, c.sort_order, c.post_role, c.created_at,
+	       COUNT(t.id), COALESCE(SUM(t.message_count), 0)
+	FROM categories c
+	JOIN subtree s ON s.root_id = c.id
+	LEFT JOIN topics t ON t.category_id = s.id AND t.deleted = false
+	GROUP BY c.id`
+
+func (r *PostgresRepository) GetCategory(communityID, categoryID string) (*forummodels.Category, error) {
+	query := fmt.Sprintf(categoryStatsQuery, "c.id = $1 AND c.community_id = $2")
+	category, err := scanCategory(r.db.QueryRow(query, categoryID, communityID))
+	if err == sql.ErrNoRows {
+		return nil, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+// GetCategories возвращает все разделы сообщества плоским списком
+// вместе с количеством тем и сообщений в каждом, включая подразделы
+func (r *PostgresRepository) GetCategories(communityID string) ([]*forummodels.Category, error) {
+	query := fmt.Sprintf(categoryStatsQuery, "c.community_id = $1") + ` ORDER BY c.sort_order, c.name`
+	rows, err := r.db.Query(query, communityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []*forummodels.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCategory(row rowScanner) (*forummodels.Category, error) {
+	var category forummodels.Category
+	var parentID sql.NullString
+	err := row.Scan(
+		&category.ID,
+		&parentID,
+		&category.Name,
+		&category.Slug,
+		&category.Description,
+		&category.SortOrder,
+		&category.PostRole,
+		&category.CreatedAt,
+		&category.TopicCount,
+		&category.MessageCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	category.ParentID = parentID.String
+	return &category, nil
+}
+
+// nullString превращает пустую строку в NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
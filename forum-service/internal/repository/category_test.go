@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/shared/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestCategory(t *testing.T, repo *PostgresRepository, communityID, parentID string) *forummodels.Category {
+	id := uuid.NewString()
+	category := &forummodels.Category{
+		ID:        id,
+		ParentID:  parentID,
+		Name:      "Раздел " + id[:8],
+		Slug:      "c-" + id[:8],
+		PostRole:  "user",
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, repo.CreateCategory(communityID, category))
+	return category
+}
+
+func createTestTopic(t *testing.T, repo *PostgresRepository, communityID, categoryID string) *forummodels.TopicDetail {
+	topic := &forummodels.TopicDetail{Topic: models.Topic{
+		ID:        uuid.NewString(),
+		Title:     "Тема",
+		Content:   "Текст",
+		UserID:    "test-user-id",
+		CreatedAt: time.Now(),
+	}, CategoryID: categoryID}
+	require.NoError(t, repo.CreateTopic(communityID, topic))
+	return topic
+}
+
+func TestPostgresRepository_GetCategory_CountsSubtree(t *testing.T) {
+	repo := testRepository(t)
+	communityID := uuid.NewString()
+
+	parent := createTestCategory(t, repo, communityID, "")
+	child := createTestCategory(t, repo, communityID, parent.ID)
+	grandchild := createTestCategory(t, repo, communityID, child.ID)
+	createTestTopic(t, repo, communityID, parent.ID)
+	createTestTopic(t, repo, communityID, child.ID)
+	createTestTopic(t, repo, communityID, grandchild.ID)
+	deleted := createTestTopic(t, repo, communityID, grandchild.ID)
+	require.NoError(t, repo.DeleteTopic(communityID, deleted.ID))
+
+	got, err := repo.GetCategory(communityID, parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, got.TopicCount)
+
+	categories, err := repo.GetCategories(communityID)
+	require.NoError(t, err)
+	counts := make(map[string]int)
+	for _, category := range categories {
+		counts[category.ID] = category.TopicCount
+	}
+	assert.Equal(t, map[string]int{parent.ID: 3, child.ID: 2, grandchild.ID: 1}, counts)
+}
+
+func TestPostgresRepository_DeleteCategory_IgnoresDeletedTopics(t *testing.T) {
+	repo := testRepository(t)
+	communityID := uuid.NewString()
+
+	category := createTestCategory(t, repo, communityID, "")
+	topic := createTestTopic(t, repo, communityID, category.ID)
+
+	assert.ErrorIs(t, repo.DeleteCategory(communityID, category.ID), ErrCategoryNotEmpty)
+
+	require.NoError(t, repo.DeleteTopic(communityID, topic.ID))
+	require.NoError(t, repo.DeleteCategory(communityID, category.ID))
+
+	_, err := repo.GetCategory(communityID, category.ID)
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// conversationSelect выбирает переписки вместе с участниками и числом сообщений,
+// непрочитанных пользователем $1
+const conversationSelect = `SELECT c.id, c.created_by, c.created_at, c.last_message_at,
+	ARRAY(SELECT cm.user_id FROM conversation_members cm
+	      WHERE cm.conversation_id = c.id ORDER BY cm.joined_at, cm.user_id),
+	(SELECT COUNT(*) FROM direct_messages d
+	 JOIN conversation_members m ON m.conversation_id = d.conversation_id AND m.user_id = $1
+	 WHERE d.conversation_id = c.id AND d.user_id <> $1
+	   AND (m.last_read_at IS NULL OR d.created_at > m.last_read_at))
+	FROM conversations c`
+
+// CreateConversation создает переписку с участниками. Для переписки двух пользователей
+// directKey не пустой, и при повторном создании возвращается уже существующая.
+func (r *PostgresRepository) CreateConversation(communityID string, conversation *forummodels.Conversation, directKey string) (*forummodels.Conversation, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRow(`INSERT INTO conversations (id, community_id, direct_key, created_by, created_at)
+	                   VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+	                   ON CONFLICT (community_id, direct_key) DO NOTHING
+	                   RETURNING id`,
+		conversation.ID, communityID, directKey, conversation.CreatedBy, conversation.CreatedAt).Scan(&id)
+	if err == sql.ErrNoRows {
+		// Переписка этих двух пользователей уже есть
+		err = tx.QueryRow(`SELECT id FROM conversations WHERE community_id = $1 AND direct_key = $2`,
+			communityID, directKey).Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+		return r.GetConversation(communityID, id, conversation.CreatedBy)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, userID := range conversation.Members {
+		_, err := tx.Exec(`INSERT INTO conversation_members (conversation_id, user_id, joined_at)
+		                   VALUES ($1, $2, $3)`, id, userID, conversation.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return conversation, nil
+}
+
+// GetConversation возвращает переписку с числом сообщений, непрочитанных viewerID
+func (r *PostgresRepository) GetConversation(communityID, conversationID, viewerID string) (*forummodels.Conversation, error) {
+	query := conversationSelect + ` WHERE c.community_id = $2 AND c.id = $3`
+	conversation, err := scanConversation(r.db.QueryRow(query, viewerID, communityID, conversationID))
+	if err == sql.ErrNoRows {
+		return nil, ErrConversationNotFound
+	}
+	return conversation, err
+}
+
+// GetConversations возвращает переписки пользователя, недавно активные первыми
+func (r *PostgresRepository) GetConversations(communityID, userID string) ([]*forummodels.Conversation, error) {
+	query := conversationSelect + `
+	          WHERE c.community_id = $2
+	            AND EXISTS (SELECT 1 FROM conversation_members cm WHERE cm.conversation_id = c.id AND cm.user_id = $1)
+	          ORDER BY COALESCE(c.last_message_at, c.created_at) DESC, c.id DESC`
+	rows, err := r.db.Query(query, userID, communityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var conversations []*forummodels.Conversation
+	for rows.Next() {
+		conversation, err := scanConversation(rows)
+		if err != nil {
+			return nil, err
+		}
+		conversations = append(conversations, conversation)
+	}
+	return conversations, rows.Err()
+}
+
+func scanConversation(row interface{ Scan(...interface{}) error }) (*forummodels.Conversation, error) {
+	var conversation forummodels.Conversation
+	var lastMessageAt sql.NullTime
+	err := row.Scan(&conversation.ID, &conversation.CreatedBy, &conversation.CreatedAt, &lastMessageAt,
+		pq.Array(&conversation.Members), &conversation.Unread)
+	if err != nil {
+		return nil, err
+	}
+	if lastMessageAt.Valid {
+		conversation.LastMessageAt = &lastMessageAt.Time
+	}
+	return &conversation, nil
+}
+
+// CreateDirectMessage сохраняет сообщение переписки. Для отправителя сообщение сразу прочитано.
+func (r *PostgresRepository) CreateDirectMessage(message *forummodels.DirectMessage) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO direct_messages (id, conversation_id, user_id, content, content_html, created_at)
+	                  VALUES ($1, $2, $3, $4, $5, $6)`,
+		message.ID, message.ConversationID, message.UserID, message.Content, message.ContentHTML, message.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE conversations SET last_message_at = $1 WHERE id = $2`,
+		message.CreatedAt, message.ConversationID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE conversation_members SET last_read_at = $1
+	                      WHERE conversation_id = $2 AND user_id = $3`,
+		message.CreatedAt, message.ConversationID, message.UserID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetDirectMessages возвращает страницу истории переписки
+func (r *PostgresRepository) GetDirectMessages(conversationID string, page forummodels.PageQuery) ([]*forummodels.DirectMessage, error) {
+	args := []interface{}{conversationID}
+	where, order := keyset(page, &args)
+	args = append(args, page.Limit)
+
+	query := fmt.Sprintf(`SELECT id, conversation_id, user_id, content, COALESCE(content_html, ''), created_at
+	                      FROM direct_messages
+	                      WHERE conversation_id = $1%s
+	                      ORDER BY %s
+	                      LIMIT $%d`, where, order, len(args))
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*forummodels.DirectMessage
+	for rows.Next() {
+		var message forummodels.DirectMessage
+		if err := rows.Scan(&message.ID, &message.ConversationID, &message.UserID,
+			&message.Content, &message.ContentHTML, &message.CreatedAt); err != nil {
+			return nil, err
+		}
+		messages = append(messages, &message)
+	}
+	return messages, rows.Err()
+}
+
+// MarkConversationRead отмечает прочитанными сообщения переписки до момента at
+func (r *PostgresRepository) MarkConversationRead(conversationID, userID string, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE conversation_members SET last_read_at = GREATEST(COALESCE(last_read_at, $1), $1)
+	                     WHERE conversation_id = $2 AND user_id = $3`, at, conversationID, userID)
+	return err
+}
+
+// BlockUser запрещает blockedID писать пользователю userID
+func (r *PostgresRepository) BlockUser(userID, blockedID string, at time.Time) error {
+	_, err := r.db.Exec(`INSERT INTO user_blocks (user_id, blocked_user_id, created_at)
+	                     VALUES ($1, $2, $3)
+	                     ON CONFLICT DO NOTHING`, userID, blockedID, at)
+	return err
+}
+
+// UnblockUser снимает блокировку
+func (r *PostgresRepository) UnblockUser(userID, blockedID string) error {
+	_, err := r.db.Exec(`DELETE FROM user_blocks WHERE user_id = $1 AND blocked_user_id = $2`, userID, blockedID)
+	return err
+}
+
+// GetBlockedUsers возвращает пользователей, заблокированных userID
+func (r *PostgresRepository) GetBlockedUsers(userID string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT blocked_user_id FROM user_blocks
+	                         WHERE user_id = $1 ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocked []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		blocked = append(blocked, id)
+	}
+	return blocked, rows.Err()
+}
+
+// BlockedBy возвращает тех из userIDs, кто заблокировал senderID
+func (r *PostgresRepository) BlockedBy(senderID string, userIDs []string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT user_id FROM user_blocks
+	                         WHERE blocked_user_id = $1 AND user_id = ANY($2)`, senderID, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blockers []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		blockers = append(blockers, id)
+	}
+	return blockers, rows.Err()
+}
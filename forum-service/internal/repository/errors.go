@@ -1,11 +1,36 @@
 package repository
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
 
 var (
-	ErrTopicNotFound  = errors.New("topic not found")
-	ErrUserNotFound   = errors.New("user not found")
-	ErrAccessDenied   = errors.New("access denied")
-	ErrAlreadyExists  = errors.New("already exists")
-	ErrInvalidRequest = errors.New("invalid request")
+	ErrTopicNotFound        = errors.New("topic not found")
+	ErrRevisionNotFound     = errors.New("revision not found")
+	ErrCategoryNotFound     = errors.New("category not found")
+	ErrCategoryNotEmpty     = errors.New("category has topics or subcategories")
+	ErrTagNotFound          = errors.New("tag not found")
+	ErrMessageNotFound      = errors.New("message not found")
+	ErrConversationNotFound = errors.New("conversation not found")
+	ErrAttachmentNotFound   = errors.New("attachment not found")
+	ErrPollNotFound         = errors.New("poll not found")
+	ErrPollVoteNotFound     = errors.New("poll vote not found")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrAccessDenied         = errors.New("access denied")
+	ErrAlreadyExists        = errors.New("already exists")
+	ErrInvalidRequest       = errors.New("invalid request")
 )
+
+// Код ошибки Postgres unique_violation
+const uniqueViolation = "23505"
+
+// translateError превращает нарушения уникальности в ErrAlreadyExists
+func translateError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return ErrAlreadyExists
+	}
+	return err
+}
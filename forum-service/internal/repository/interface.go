@@ -3,21 +3,116 @@ package repository
 import (
 	"time"
 
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
 	"github.com/luckermt/forum-app/shared/pkg/models"
 )
 
 type ForumRepository interface {
 	// Topics
-	CreateTopic(topic *models.Topic) error
-	GetTopics() ([]*models.Topic, error)
-	DeleteTopic(topicID string) error
+	CreateTopic(communityID string, topic *forummodels.TopicDetail) error
+	GetTopics(communityID string, filter forummodels.TopicFilter, page forummodels.PageQuery) ([]*forummodels.TopicSummary, error)
+	DeleteTopic(communityID, topicID string) error
+	GetTopic(communityID, topicID string) (*forummodels.TopicDetail, error)
+	UpdateTopic(communityID, topicID, title, content, contentHTML, editorID string, at time.Time) (*forummodels.TopicDetail, error)
+	GetTopicRevisions(communityID, topicID string) ([]*forummodels.TopicRevision, error)
+	GetTopicRevision(communityID, topicID string, revision int) (*forummodels.TopicRevision, error)
+	SetTopicState(communityID, topicID string, state forummodels.TopicState) (*forummodels.TopicDetail, error)
+
+	// Categories
+	CreateCategory(communityID string, category *forummodels.Category) error
+	UpdateCategory(communityID string, category *forummodels.Category) error
+	DeleteCategory(communityID, categoryID string) error
+	GetCategory(communityID, categoryID string) (*forummodels.Category, error)
+	GetCategories(communityID string) ([]*forummodels.Category, error)
+
+	// Tags
+	GetTagSettings(communityID string) (*forummodels.TagSettings, error)
+	SetTagSettings(communityID string, settings forummodels.TagSettings) error
+	GetTags(communityID string, names []string) ([]*forummodels.Tag, error)
+	CreateOfficialTag(communityID, name string) error
+	DeleteTag(communityID, name string) error
+	MergeTag(communityID, from, into string) error
+	SearchTags(communityID, prefix string, limit int) ([]*forummodels.Tag, error)
 
 	// Messages
-	CreateMessage(message *models.Message) error
-	GetMessagesByTopic(topicID string) ([]*models.Message, error)
-	GetChatMessages() ([]*models.Message, error)
+	CreateMessage(communityID string, message *models.Message, contentHTML string) error
+	GetMessagesByTopic(communityID, topicID string, page forummodels.PageQuery) ([]*forummodels.MessageDetail, error)
+	GetChatMessages(communityID string, page forummodels.PageQuery) ([]*forummodels.MessageDetail, error)
+	GetMessage(communityID, messageID string) (*forummodels.ThreadMessage, error)
+	CreateThreadMessage(communityID string, message *forummodels.ThreadMessage) error
+	GetThread(communityID, topicID, parentID string, maxDepth int, page forummodels.PageQuery) ([]*forummodels.ThreadMessage, error)
+	UpdateMessage(communityID, messageID, content, contentHTML, editorID string, at time.Time) (*forummodels.MessageDetail, error)
+	DeleteMessage(communityID, messageID, userID string, at time.Time) (*forummodels.MessageDetail, error)
+	GetMessageRevisions(communityID, messageID string) ([]*forummodels.MessageRevision, error)
 	DeleteMessagesOlderThan(t time.Duration) error
 
+	// Reactions
+	AddReaction(communityID, targetType, targetID, userID, emoji string, at time.Time) error
+	RemoveReaction(communityID, targetType, targetID, userID, emoji string) error
+	GetReactions(communityID, targetType string, targetIDs []string, viewerID string) (map[string][]*forummodels.ReactionCount, error)
+
+	// Votes
+	SetVote(communityID, targetType, targetID, userID, authorID string, value int, at time.Time) (int, error)
+	GetUserKarma(userID string) (*forummodels.Karma, error)
+
+	// Mentions
+	ReplaceMentions(communityID, messageID string, mentions []*forummodels.Mention, at time.Time) ([]*forummodels.Mention, error)
+	GetMentions(communityID string, messageIDs []string) (map[string][]*forummodels.Mention, error)
+
+	// Notifications
+	CreateNotifications(communityID string, notifications []*forummodels.Notification) error
+	GetNotifications(communityID, userID string, unreadOnly bool, page forummodels.PageQuery) ([]*forummodels.Notification, error)
+	MarkNotificationsRead(communityID, userID string, ids []string, at time.Time) (int, error)
+	CountUnreadNotifications(communityID, userID string) (int, error)
+
+	// Watches
+	SetWatch(communityID, userID, targetType, targetID, level string, at time.Time) error
+	AddWatch(communityID, userID, targetType, targetID, level string, at time.Time) error
+	DeleteWatch(communityID, userID, targetType, targetID string) error
+	GetWatch(communityID, userID, targetType, targetID string) (string, error)
+	GetTopicWatchers(communityID, topicID, categoryID string) (map[string]string, error)
+
+	// Direct messages
+	CreateConversation(communityID string, conversation *forummodels.Conversation, directKey string) (*forummodels.Conversation, error)
+	GetConversation(communityID, conversationID, viewerID string) (*forummodels.Conversation, error)
+	GetConversations(communityID, userID string) ([]*forummodels.Conversation, error)
+	CreateDirectMessage(message *forummodels.DirectMessage) error
+	GetDirectMessages(conversationID string, page forummodels.PageQuery) ([]*forummodels.DirectMessage, error)
+	MarkConversationRead(conversationID, userID string, at time.Time) error
+	BlockUser(userID, blockedID string, at time.Time) error
+	UnblockUser(userID, blockedID string) error
+	GetBlockedUsers(userID string) ([]string, error)
+	BlockedBy(senderID string, userIDs []string) ([]string, error)
+
+	// Attachments
+	CreateAttachment(communityID string, attachment *forummodels.Attachment) error
+	GetAttachment(communityID, attachmentID string) (*forummodels.Attachment, error)
+	GetAttachments(communityID string, ids []string) ([]*forummodels.Attachment, error)
+	GetTargetAttachments(communityID, targetType string, targetIDs []string) (map[string][]*forummodels.Attachment, error)
+	IsAttachmentVisible(communityID, attachmentID string) (bool, error)
+	GetAttachmentVariants(attachmentIDs []string) (map[string][]*forummodels.AttachmentVariant, error)
+
+	// Image jobs
+	ClaimImageJob(at time.Time, lease time.Duration) (*forummodels.ImageJob, error)
+	CompleteImageJob(attachmentID string, width, height int, variants []*forummodels.AttachmentVariant) error
+	FailImageJob(attachmentID, status string) error
+	RetryImageJob(attachmentID string, runAfter time.Time, lastError string) error
+
+	// Polls
+	GetTopicPoll(communityID, topicID string) (*forummodels.Poll, error)
+	GetPollChoices(pollID, userID string) ([]string, error)
+	CreatePollVote(pollID, userID string, optionIDs []string, at time.Time) error
+	ReplacePollVote(pollID, userID string, optionIDs []string, at time.Time) error
+	DeletePollVote(pollID, userID string) error
+
+	// References
+	ReplaceReferences(communityID, sourceType, sourceID, sourceTopicID string, refs []*forummodels.Reference, at time.Time) error
+	GetReferences(communityID, sourceType string, sourceIDs []string) (map[string][]*forummodels.Reference, error)
+	GetBacklinks(communityID, topicID string, limit int) ([]*forummodels.Backlink, error)
+
+	// Search
+	Search(communityID string, q forummodels.SearchQuery) ([]*forummodels.SearchResult, error)
+
 	// Moderation
 	IsUserBlocked(userID string) (bool, error)
 }
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// ReplaceMentions заменяет упоминания в сообщении новым набором и возвращает
+// пользователей, которые раньше в нем упомянуты не были
+func (r *PostgresRepository) ReplaceMentions(communityID, messageID string, mentions []*forummodels.Mention, at time.Time) ([]*forummodels.Mention, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	userIDs := make([]string, 0, len(mentions))
+	for _, mention := range mentions {
+		userIDs = append(userIDs, mention.UserID)
+	}
+	_, err = tx.Exec(`DELETE FROM mentions
+	                  WHERE community_id = $1 AND message_id = $2 AND NOT (user_id = ANY($3))`,
+		communityID, messageID, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	var added []*forummodels.Mention
+	for _, mention := range mentions {
+		result, err := tx.Exec(`INSERT INTO mentions (message_id, user_id, username, community_id, created_at)
+		                        VALUES ($1, $2, $3, $4, $5)
+		                        ON CONFLICT DO NOTHING`,
+			messageID, mention.UserID, mention.Username, communityID, at)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			added = append(added, mention)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// GetMentions возвращает упоминания для набора сообщений
+func (r *PostgresRepository) GetMentions(communityID string, messageIDs []string) (map[string][]*forummodels.Mention, error) {
+	query := `SELECT message_id, user_id, username
+	          FROM mentions
+	          WHERE community_id = $1 AND message_id = ANY($2)
+	          ORDER BY message_id, created_at, username`
+	rows, err := r.db.Query(query, communityID, pq.Array(messageIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mentions := make(map[string][]*forummodels.Mention)
+	for rows.Next() {
+		var messageID string
+		var mention forummodels.Mention
+		if err := rows.Scan(&messageID, &mention.UserID, &mention.Username); err != nil {
+			return nil, err
+		}
+		mentions[messageID] = append(mentions[messageID], &mention)
+	}
+	return mentions, rows.Err()
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// messageColumns колонки MessageDetail; текст удаленных сообщений не отдается
+const messageColumns = `id, topic_id, user_id,
+	CASE WHEN deleted_at IS NULL THEN content ELSE '' END,
+	CASE WHEN deleted_at IS NULL THEN COALESCE(content_html, '') ELSE '' END,
+	created_at, is_chat, edited_at, deleted_at IS NOT NULL, score`
+
+// UpdateMessage заменяет текст сообщения и сохраняет прежнюю версию в message_revisions.
+// Строка блокируется, чтобы параллельные правки не потеряли версии.
+func (r *PostgresRepository) UpdateMessage(communityID, messageID, content, contentHTML, editorID string, at time.Time) (*forummodels.MessageDetail, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var previous string
+	err = tx.QueryRow(`SELECT content FROM messages
+	                   WHERE id = $1 AND community_id = $2 AND deleted_at IS NULL
+	                   FOR UPDATE`, messageID, communityID).Scan(&previous)
+	if err == sql.ErrNoRows {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`INSERT INTO message_revisions (message_id, content, edited_by, created_at)
+	                  VALUES ($1, $2, $3, $4)`, messageID, previous, editorID, at)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := scanMessageDetail(tx.QueryRow(`UPDATE messages
+	          SET content = $1, content_html = $2, search_config = $3, edited_at = $4
+	          WHERE id = $5 AND community_id = $6
+	          RETURNING `+messageColumns,
+		content, contentHTML, searchConfig(content), at, messageID, communityID))
+	if err != nil {
+		return nil, err
+	}
+
+	return message, tx.Commit()
+}
+
+// DeleteMessage помечает сообщение удаленным. Строка остается, чтобы не разрывать ветки ответов.
+func (r *PostgresRepository) DeleteMessage(communityID, messageID, userID string, at time.Time) (*forummodels.MessageDetail, error) {
+	query := `UPDATE messages SET deleted_at = $1, deleted_by = $2
+	          WHERE id = $3 AND community_id = $4 AND deleted_at IS NULL
+	          RETURNING ` + messageColumns
+	message, err := scanMessageDetail(r.db.QueryRow(query, at, userID, messageID, communityID))
+	if err == sql.ErrNoRows {
+		return nil, ErrMessageNotFound
+	}
+	return message, err
+}
+
+// GetMessageRevisions возвращает прежние версии сообщения от старых к новым
+func (r *PostgresRepository) GetMessageRevisions(communityID, messageID string) ([]*forummodels.MessageRevision, error) {
+	query := `SELECT mr.content, mr.edited_by, mr.created_at
+	          FROM message_revisions mr JOIN messages m ON m.id = mr.message_id
+	          WHERE mr.message_id = $1 AND m.community_id = $2
+	          ORDER BY mr.created_at, mr.id`
+	rows, err := r.db.Query(query, messageID, communityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*forummodels.MessageRevision
+	for rows.Next() {
+		var revision forummodels.MessageRevision
+		if err := rows.Scan(&revision.Content, &revision.EditedBy, &revision.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &revision)
+	}
+	return revisions, rows.Err()
+}
+
+func scanMessageDetail(row rowScanner) (*forummodels.MessageDetail, error) {
+	var message forummodels.MessageDetail
+	var topicID sql.NullString
+	var editedAt sql.NullTime
+	err := row.Scan(
+		&message.ID,
+		&topicID,
+		&message.UserID,
+		&message.Content,
+		&message.ContentHTML,
+		&message.CreatedAt,
+		&message.IsChat,
+		&editedAt,
+		&message.Deleted,
+		&message.Score,
+	)
+	if err != nil {
+		return nil, err
+	}
+	// У сообщений общего чата темы нет
+	message.TopicID = topicID.String
+	if editedAt.Valid {
+		message.EditedAt = &editedAt.Time
+	}
+	return &message, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/shared/pkg/config"
+	"github.com/luckermt/forum-app/shared/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRepository подключается к тестовой базе forum_test с примененными миграциями
+// и пользователем test-user-id; без базы тест пропускается
+func testRepository(t *testing.T) *PostgresRepository {
+	repo, err := NewPostgresRepository(config.PostgresConfig{
+		Host:     "localhost",
+		Port:     "5432",
+		User:     "postgres",
+		Password: "postgres",
+		DBName:   "forum_test",
+		SSLMode:  "disable",
+	})
+	if err != nil {
+		t.Skipf("test database is not available: %v", err)
+	}
+	t.Cleanup(func() { repo.db.Close() })
+	return repo
+}
+
+func TestPostgresRepository_DeleteMessagesOlderThan_KeepsTopicReplies(t *testing.T) {
+	repo := testRepository(t)
+	old := time.Now().Add(-48 * time.Hour)
+
+	topic := &forummodels.TopicDetail{Topic: models.Topic{
+		ID:        uuid.NewString(),
+		Title:     "Старая тема",
+		Content:   "Текст",
+		UserID:    "test-user-id",
+		CreatedAt: old,
+	}}
+	require.NoError(t, repo.CreateTopic("default", topic))
+
+	reply := &models.Message{
+		ID:        uuid.NewString(),
+		TopicID:   topic.ID,
+		UserID:    "test-user-id",
+		Content:   "Ответ в теме",
+		CreatedAt: old,
+	}
+	require.NoError(t, repo.CreateMessage("default", reply, "<p>Ответ в теме</p>"))
+
+	chat := &models.Message{
+		ID:        uuid.NewString(),
+		UserID:    "test-user-id",
+		Content:   "Сообщение в чате",
+		CreatedAt: old,
+		IsChat:    true,
+	}
+	require.NoError(t, repo.CreateMessage("default", chat, "<p>Сообщение в чате</p>"))
+
+	require.NoError(t, repo.DeleteMessagesOlderThan(24*time.Hour))
+
+	kept, err := repo.GetMessage("default", reply.ID)
+	require.NoError(t, err)
+	assert.Equal(t, reply.Content, kept.Content)
+
+	_, err = repo.GetMessage("default", chat.ID)
+	assert.ErrorIs(t, err, ErrMessageNotFound)
+}
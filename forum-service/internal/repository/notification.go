@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+const notificationColumns = `id, user_id, type, COALESCE(action, ''), COALESCE(actor_id, ''),
+	COALESCE(topic_id, ''), COALESCE(message_id, ''), created_at, read_at`
+
+// CreateNotifications сохраняет уведомления одной транзакцией
+func (r *PostgresRepository) CreateNotifications(communityID string, notifications []*forummodels.Notification) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, n := range notifications {
+		_, err := tx.Exec(`INSERT INTO notifications (id, community_id, user_id, type, action, actor_id, topic_id, message_id, created_at)
+		                   VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), NULLIF($8, ''), $9)`,
+			n.ID, communityID, n.UserID, n.Type, n.Action, n.ActorID, n.TopicID, n.MessageID, n.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetNotifications возвращает страницу уведомлений пользователя, новые первыми
+func (r *PostgresRepository) GetNotifications(communityID, userID string, unreadOnly bool, page forummodels.PageQuery) ([]*forummodels.Notification, error) {
+	args := []interface{}{communityID, userID}
+	where, order := keyset(page, &args)
+	if unreadOnly {
+		where += " AND read_at IS NULL"
+	}
+	args = append(args, page.Limit)
+
+	query := fmt.Sprintf(`SELECT %s FROM notifications
+	                      WHERE community_id = $1 AND user_id = $2%s
+	                      ORDER BY %s
+	                      LIMIT $%d`, notificationColumns, where, order, len(args))
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*forummodels.Notification
+	for rows.Next() {
+		var n forummodels.Notification
+		var readAt sql.NullTime
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.Action, &n.ActorID,
+			&n.TopicID, &n.MessageID, &n.CreatedAt, &readAt); err != nil {
+			return nil, err
+		}
+		if readAt.Valid {
+			n.ReadAt = &readAt.Time
+		}
+		notifications = append(notifications, &n)
+	}
+	return notifications, rows.Err()
+}
+
+// MarkNotificationsRead отмечает уведомления пользователя прочитанными (все, если ids пуст)
+// и возвращает число найденных уведомлений. Время первого прочтения не перезаписывается.
+func (r *PostgresRepository) MarkNotificationsRead(communityID, userID string, ids []string, at time.Time) (int, error) {
+	query := `UPDATE notifications SET read_at = COALESCE(read_at, $3)
+	          WHERE community_id = $1 AND user_id = $2`
+	args := []interface{}{communityID, userID, at}
+	if len(ids) > 0 {
+		query += ` AND id = ANY($4)`
+		args = append(args, pq.Array(ids))
+	}
+
+	result, err := r.db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
+
+// CountUnreadNotifications возвращает число непрочитанных уведомлений пользователя
+func (r *PostgresRepository) CountUnreadNotifications(communityID, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM notifications
+	                      WHERE community_id = $1 AND user_id = $2 AND read_at IS NULL`,
+		communityID, userID).Scan(&count)
+	return count, err
+}
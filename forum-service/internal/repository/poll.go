@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// GetTopicPoll возвращает опрос темы со счетчиками голосов. Голосовавшие по вариантам
+// загружаются только для открытых опросов.
+func (r *PostgresRepository) GetTopicPoll(communityID, topicID string) (*forummodels.Poll, error) {
+	query := `SELECT p.id, p.topic_id, p.question, p.multiple, p.anonymous, p.hide_results, p.closes_at, p.created_at,
+	                 (SELECT COUNT(*) FROM poll_ballots b WHERE b.poll_id = p.id)
+	          FROM polls p JOIN topics t ON t.id = p.topic_id
+	          WHERE p.topic_id = $1 AND t.community_id = $2`
+	var poll forummodels.Poll
+	var closesAt sql.NullTime
+	err := r.db.QueryRow(query, topicID, communityID).Scan(&poll.ID, &poll.TopicID, &poll.Question,
+		&poll.Multiple, &poll.Anonymous, &poll.HideResults, &closesAt, &poll.CreatedAt, &poll.Voters)
+	if err == sql.ErrNoRows {
+		return nil, ErrPollNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if closesAt.Valid {
+		poll.ClosesAt = &closesAt.Time
+	}
+
+	rows, err := r.db.Query(`SELECT o.id, o.text, COUNT(c.user_id)
+	                         FROM poll_options o LEFT JOIN poll_choices c ON c.poll_id = o.poll_id AND c.option_id = o.id
+	                         WHERE o.poll_id = $1
+	                         GROUP BY o.id, o.text, o.position
+	                         ORDER BY o.position`, poll.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	options := make(map[string]*forummodels.PollOption)
+	for rows.Next() {
+		var option forummodels.PollOption
+		var votes int
+		if err := rows.Scan(&option.ID, &option.Text, &votes); err != nil {
+			return nil, err
+		}
+		option.Votes = &votes
+		poll.Options = append(poll.Options, &option)
+		options[option.ID] = &option
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if poll.Anonymous {
+		return &poll, nil
+	}
+	voters, err := r.db.Query(`SELECT c.option_id, c.user_id
+	                           FROM poll_choices c JOIN poll_ballots b ON b.poll_id = c.poll_id AND b.user_id = c.user_id
+	                           WHERE c.poll_id = $1
+	                           ORDER BY b.created_at, c.user_id`, poll.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer voters.Close()
+	for voters.Next() {
+		var optionID, userID string
+		if err := voters.Scan(&optionID, &userID); err != nil {
+			return nil, err
+		}
+		if option, ok := options[optionID]; ok {
+			option.Voters = append(option.Voters, userID)
+		}
+	}
+	return &poll, voters.Err()
+}
+
+// GetPollChoices возвращает варианты, выбранные пользователем
+func (r *PostgresRepository) GetPollChoices(pollID, userID string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT c.option_id FROM poll_choices c JOIN poll_options o ON o.id = c.option_id
+	                         WHERE c.poll_id = $1 AND c.user_id = $2 ORDER BY o.position`, pollID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var choices []string
+	for rows.Next() {
+		var optionID string
+		if err := rows.Scan(&optionID); err != nil {
+			return nil, err
+		}
+		choices = append(choices, optionID)
+	}
+	return choices, rows.Err()
+}
+
+// CreatePollVote сохраняет бюллетень пользователя. Повторный голос
+// нарушает первичный ключ poll_ballots и возвращает ErrAlreadyExists.
+func (r *PostgresRepository) CreatePollVote(pollID, userID string, optionIDs []string, at time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO poll_ballots (poll_id, user_id, created_at, updated_at) VALUES ($1, $2, $3, $3)`,
+		pollID, userID, at)
+	if err != nil {
+		return translateError(err)
+	}
+	if err := insertPollChoices(tx, pollID, userID, optionIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ReplacePollVote заменяет выбранные варианты в существующем бюллетене
+func (r *PostgresRepository) ReplacePollVote(pollID, userID string, optionIDs []string, at time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE poll_ballots SET updated_at = $3 WHERE poll_id = $1 AND user_id = $2`,
+		pollID, userID, at)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrPollVoteNotFound
+	}
+	if _, err := tx.Exec(`DELETE FROM poll_choices WHERE poll_id = $1 AND user_id = $2`, pollID, userID); err != nil {
+		return err
+	}
+	if err := insertPollChoices(tx, pollID, userID, optionIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeletePollVote отзывает бюллетень вместе с выбранными вариантами
+func (r *PostgresRepository) DeletePollVote(pollID, userID string) error {
+	result, err := r.db.Exec(`DELETE FROM poll_ballots WHERE poll_id = $1 AND user_id = $2`, pollID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return ErrPollVoteNotFound
+	}
+	return nil
+}
+
+// insertPoll создает опрос в транзакции создания темы. closes_at хранится
+// без часового пояса, поэтому время закрытия переводится в UTC.
+func insertPoll(tx *sql.Tx, topicID string, poll *forummodels.Poll) error {
+	var closesAt interface{}
+	if poll.ClosesAt != nil {
+		closesAt = poll.ClosesAt.UTC()
+	}
+	_, err := tx.Exec(`INSERT INTO polls (id, topic_id, question, multiple, anonymous, hide_results, closes_at, created_at)
+	                   VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		poll.ID, topicID, poll.Question, poll.Multiple, poll.Anonymous, poll.HideResults, closesAt, poll.CreatedAt)
+	if err != nil {
+		return err
+	}
+	for i, option := range poll.Options {
+		_, err := tx.Exec(`INSERT INTO poll_options (id, poll_id, text, position) VALUES ($1, $2, $3, $4)`,
+			option.ID, poll.ID, option.Text, i)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertPollChoices(tx *sql.Tx, pollID, userID string, optionIDs []string) error {
+	for _, optionID := range optionIDs {
+		_, err := tx.Exec(`INSERT INTO poll_choices (poll_id, user_id, option_id) VALUES ($1, $2, $3)`,
+			pollID, userID, optionID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/shared/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresRepository_CreateTopic_PollClosesAtWithOffset(t *testing.T) {
+	repo := testRepository(t)
+
+	// 18:00 по Москве — это 15:00 UTC
+	closesAt := time.Date(2030, 1, 31, 18, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	topic := &forummodels.TopicDetail{Topic: models.Topic{
+		ID:        uuid.NewString(),
+		Title:     "Опрос",
+		Content:   "Текст",
+		UserID:    "test-user-id",
+		CreatedAt: time.Now(),
+	}}
+	topic.Poll = &forummodels.Poll{
+		ID:        uuid.NewString(),
+		Question:  "Какой язык?",
+		ClosesAt:  &closesAt,
+		CreatedAt: time.Now(),
+		Options: []*forummodels.PollOption{
+			{ID: uuid.NewString(), Text: "Go"},
+			{ID: uuid.NewString(), Text: "Rust"},
+		},
+	}
+	require.NoError(t, repo.CreateTopic("default", topic))
+
+	poll, err := repo.GetTopicPoll("default", topic.ID)
+	require.NoError(t, err)
+	require.NotNil(t, poll.ClosesAt)
+	assert.True(t, closesAt.Equal(*poll.ClosesAt), "closes_at = %s", poll.ClosesAt)
+}
@@ -7,7 +7,8 @@ import (
 	"testing"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
 	"github.com/luckermt/forum-app/shared/pkg/config"
 	"github.com/luckermt/forum-app/shared/pkg/logger"
 	"github.com/luckermt/forum-app/shared/pkg/models"
@@ -52,110 +53,271 @@ func NewPostgresRepository(cfg config.PostgresConfig) (*PostgresRepository, erro
 	return &PostgresRepository{db: db}, nil
 }
 
-func (r *PostgresRepository) CreateTopic(topic *models.Topic) error {
-	query := `INSERT INTO topics (id, title, content, user_id, created_at, deleted) 
-	          VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := r.db.Exec(query,
+func (r *PostgresRepository) CreateTopic(communityID string, topic *forummodels.TopicDetail) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO topics (id, community_id, title, content, content_html, user_id, created_at, deleted, search_config, category_id) 
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	_, err = tx.Exec(query,
 		topic.ID,
+		communityID,
 		topic.Title,
 		topic.Content,
+		topic.ContentHTML,
 		topic.UserID,
 		topic.CreatedAt,
 		false, // deleted по умолчанию false
+		searchConfig(topic.Title+" "+topic.Content),
+		nullString(topic.CategoryID),
 	)
-	return err
+	if err != nil {
+		return translateError(err)
+	}
+
+	if err := insertTopicTags(tx, communityID, topic.ID, topic.Tags); err != nil {
+		return err
+	}
+
+	if err := insertAttachmentRefs(tx, forummodels.AttachmentTargetTopic, topic.ID, topic.Attachments); err != nil {
+		return err
+	}
+
+	if topic.Poll != nil {
+		if err := insertPoll(tx, topic.ID, topic.Poll); err != nil {
+			return err
+		}
+	}
+
+	// Первая ревизия хранит исходный текст темы
+	_, err = tx.Exec(`INSERT INTO topic_revisions (topic_id, revision, title, content, edited_by, created_at)
+	                  VALUES ($1, 1, $2, $3, $4, $5)`,
+		topic.ID, topic.Title, topic.Content, topic.UserID, topic.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *PostgresRepository) GetTopics() ([]*models.Topic, error) {
-	query := `SELECT id, title, content, user_id, created_at 
-	          FROM topics WHERE deleted = false ORDER BY created_at DESC`
-	rows, err := r.db.Query(query)
+func (r *PostgresRepository) GetTopics(communityID string, filter forummodels.TopicFilter, page forummodels.PageQuery) ([]*forummodels.TopicSummary, error) {
+	args := []interface{}{communityID}
+	filters := topicFilters(filter, &args)
+	where, order := topicKeyset(page, &args)
+	query := `SELECT id, title, content, COALESCE(content_html, ''), user_id, created_at, message_count, category_id, ` + topicTagsColumn + `,
+	                 pinned, locked, archived, score
+	          FROM topics WHERE community_id = $1 AND deleted = false` + filters + where +
+		` ORDER BY ` + order + fmt.Sprintf(" LIMIT %d", page.Limit)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var topics []*models.Topic
+	var topics []*forummodels.TopicSummary
 	for rows.Next() {
-		var topic models.Topic
+		var topic forummodels.TopicSummary
+		var categoryID sql.NullString
 		err := rows.Scan(
 			&topic.ID,
 			&topic.Title,
 			&topic.Content,
+			&topic.ContentHTML,
 			&topic.UserID,
 			&topic.CreatedAt,
+			&topic.MessageCount,
+			&categoryID,
+			pq.Array(&topic.Tags),
+			&topic.Pinned,
+			&topic.Locked,
+			&topic.Archived,
+			&topic.Score,
 		)
 		if err != nil {
 			return nil, err
 		}
+		topic.CategoryID = categoryID.String
 		topics = append(topics, &topic)
 	}
 
-	return topics, nil
+	return topics, rows.Err()
 }
 
-func (r *PostgresRepository) DeleteTopic(topicID string) error {
-	query := `UPDATE topics SET deleted = true WHERE id = $1`
-	_, err := r.db.Exec(query, topicID)
-	return err
+func (r *PostgresRepository) DeleteTopic(communityID, topicID string) error {
+	query := `UPDATE topics SET deleted = true WHERE id = $1 AND community_id = $2`
+	res, err := r.db.Exec(query, topicID, communityID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrTopicNotFound
+	}
+	return nil
 }
 
-func (r *PostgresRepository) CreateMessage(message *models.Message) error {
-	query := `INSERT INTO messages (id, topic_id, user_id, content, created_at, is_chat) 
-	          VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := r.db.Exec(query,
+func (r *PostgresRepository) CreateMessage(communityID string, message *models.Message, contentHTML string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO messages (id, community_id, topic_id, user_id, content, content_html, created_at, is_chat, search_config) 
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err = tx.Exec(query,
 		message.ID,
-		message.TopicID,
+		communityID,
+		nullString(message.TopicID),
 		message.UserID,
 		message.Content,
+		contentHTML,
 		message.CreatedAt,
 		message.IsChat,
+		searchConfig(message.Content),
 	)
-	return err
+	if err != nil {
+		return translateError(err)
+	}
+
+	// Счетчик нужен для сортировки тем по активности
+	if !message.IsChat && message.TopicID != "" {
+		_, err = tx.Exec(`UPDATE topics SET message_count = message_count + 1 WHERE id = $1`, message.TopicID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresRepository) GetMessagesByTopic(communityID, topicID string, page forummodels.PageQuery) ([]*forummodels.MessageDetail, error) {
+	args := []interface{}{communityID, topicID}
+	where, order := keyset(page, &args)
+	query := `SELECT ` + messageColumns + `
+	          FROM messages WHERE community_id = $1 AND topic_id = $2 AND is_chat = false` + where +
+		` ORDER BY ` + order + fmt.Sprintf(" LIMIT %d", page.Limit)
+	return r.queryMessages(query, args...)
+}
+
+func (r *PostgresRepository) GetChatMessages(communityID string, page forummodels.PageQuery) ([]*forummodels.MessageDetail, error) {
+	args := []interface{}{communityID}
+	where, order := keyset(page, &args)
+	query := `SELECT ` + messageColumns + `
+	          FROM messages WHERE community_id = $1 AND is_chat = true` + where +
+		` ORDER BY ` + order + fmt.Sprintf(" LIMIT %d", page.Limit)
+	return r.queryMessages(query, args...)
+}
+
+// topicFilters возвращает условия отбора тем. Параметры дописываются в args.
+func topicFilters(filter forummodels.TopicFilter, args *[]interface{}) string {
+	var where string
+	if !filter.IncludeArchived {
+		where += " AND archived = false"
+	}
+	if filter.CategoryID != "" {
+		*args = append(*args, filter.CategoryID)
+		where += fmt.Sprintf(` AND category_id IN (
+		    WITH RECURSIVE subtree(id) AS (
+		        SELECT id FROM categories WHERE id = $%d
+		        UNION ALL
+		        SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+		    )
+		    SELECT id FROM subtree)`, len(*args))
+	}
+	if len(filter.Tags) > 0 {
+		*args = append(*args, pq.Array(filter.Tags), len(filter.Tags))
+		where += fmt.Sprintf(` AND id IN (
+		    SELECT topic_id FROM topic_tags
+		    WHERE community_id = $1 AND tag = ANY($%d)
+		    GROUP BY topic_id
+		    HAVING COUNT(*) = $%d)`, len(*args)-1, len(*args))
+	}
+	return where
 }
 
-func (r *PostgresRepository) GetMessagesByTopic(topicID string) ([]*models.Message, error) {
-	query := `SELECT id, topic_id, user_id, content, created_at 
-	          FROM messages WHERE topic_id = $1 AND is_chat = false 
-	          ORDER BY created_at ASC`
-	return r.queryMessages(query, topicID)
+// topicKeyset дополняет keyset закреплением: закрепленные темы всегда идут первыми
+func topicKeyset(page forummodels.PageQuery, args *[]interface{}) (string, string) {
+	if page.After == nil {
+		_, order := keyset(page, args)
+		return "", "pinned DESC, " + order
+	}
+
+	*args = append(*args, page.After.Pinned)
+	n := len(*args)
+	inner, order := keyset(page, args)
+	where := fmt.Sprintf(" AND (pinned < $%d OR (pinned = $%d%s))", n, n, inner)
+	return where, "pinned DESC, " + order
 }
 
-func (r *PostgresRepository) GetChatMessages() ([]*models.Message, error) {
-	query := `SELECT id, topic_id, user_id, content, created_at 
-	          FROM messages WHERE is_chat = true 
-	          ORDER BY created_at ASC`
-	return r.queryMessages(query)
+// keyset возвращает условие продолжения после курсора и порядок сортировки.
+// Параметры курсора дописываются в args.
+func keyset(page forummodels.PageQuery, args *[]interface{}) (string, string) {
+	var where string
+	switch page.Sort {
+	case forummodels.SortOldest:
+		if page.After != nil {
+			*args = append(*args, page.After.CreatedAt, page.After.ID)
+			where = fmt.Sprintf(" AND (created_at, id) > ($%d, $%d)", len(*args)-1, len(*args))
+		}
+		return where, "created_at ASC, id ASC"
+	case forummodels.SortTop:
+		if page.After != nil {
+			*args = append(*args, page.After.Score, page.After.CreatedAt, page.After.ID)
+			where = fmt.Sprintf(" AND (score, created_at, id) < ($%d, $%d, $%d)", len(*args)-2, len(*args)-1, len(*args))
+		}
+		return where, "score DESC, created_at DESC, id DESC"
+	case forummodels.SortActive:
+		if page.After != nil {
+			*args = append(*args, page.After.MessageCount, page.After.CreatedAt, page.After.ID)
+			where = fmt.Sprintf(" AND (message_count, created_at, id) < ($%d, $%d, $%d)", len(*args)-2, len(*args)-1, len(*args))
+		}
+		return where, "message_count DESC, created_at DESC, id DESC"
+	default:
+		if page.After != nil {
+			*args = append(*args, page.After.CreatedAt, page.After.ID)
+			where = fmt.Sprintf(" AND (created_at, id) < ($%d, $%d)", len(*args)-1, len(*args))
+		}
+		return where, "created_at DESC, id DESC"
+	}
 }
 
-func (r *PostgresRepository) queryMessages(query string, args ...interface{}) ([]*models.Message, error) {
+func (r *PostgresRepository) queryMessages(query string, args ...interface{}) ([]*forummodels.MessageDetail, error) {
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var messages []*models.Message
+	var messages []*forummodels.MessageDetail
 	for rows.Next() {
-		var msg models.Message
-		err := rows.Scan(
-			&msg.ID,
-			&msg.TopicID,
-			&msg.UserID,
-			&msg.Content,
-			&msg.CreatedAt,
-		)
+		msg, err := scanMessageDetail(rows)
 		if err != nil {
 			return nil, err
 		}
-		messages = append(messages, &msg)
+		messages = append(messages, msg)
 	}
 
 	return messages, nil
 }
 
+// DeleteMessagesOlderThan удаляет старые сообщения общего чата вместе с их реакциями,
+// ссылками и привязками вложений. Ответы в темах хранятся бессрочно
 func (r *PostgresRepository) DeleteMessagesOlderThan(maxAge time.Duration) error {
-	query := `DELETE FROM messages WHERE created_at < $1`
+	query := `WITH deleted AS (
+	              DELETE FROM messages WHERE is_chat = true AND created_at < $1
+	              RETURNING id
+	          ), deleted_reactions AS (
+	              DELETE FROM reactions
+	              WHERE target_type = 'message' AND target_id IN (SELECT id FROM deleted)
+	          ), deleted_refs AS (
+	              DELETE FROM content_refs
+	              WHERE source_type = 'message' AND source_id IN (SELECT id FROM deleted)
+	          )
+	          DELETE FROM attachment_refs
+	          WHERE target_type = 'message' AND target_id IN (SELECT id FROM deleted)`
 	_, err := r.db.Exec(query, time.Now().Add(-maxAge))
 	return err
 }
@@ -182,15 +344,15 @@ func TestPostgresRepository_CreateTopic(t *testing.T) {
 	repo, err := NewPostgresRepository(cfg)
 	assert.NoError(t, err)
 
-	topic := &models.Topic{
+	topic := &forummodels.TopicDetail{Topic: models.Topic{
 		ID:        "test-topic-id",
 		Title:     "Test Topic",
 		Content:   "Test Content",
 		UserID:    "test-user-id",
 		CreatedAt: time.Now(),
-	}
+	}}
 
-	err = repo.CreateTopic(topic)
+	err = repo.CreateTopic("default", topic)
 	assert.NoError(t, err)
 }
 
@@ -207,7 +369,7 @@ func TestPostgresRepository_GetTopics(t *testing.T) {
 	repo, err := NewPostgresRepository(cfg)
 	assert.NoError(t, err)
 
-	topics, err := repo.GetTopics()
+	topics, err := repo.GetTopics("default", forummodels.TopicFilter{}, forummodels.PageQuery{Limit: forummodels.DefaultPageSize})
 	assert.NoError(t, err)
 	assert.NotNil(t, topics)
 }
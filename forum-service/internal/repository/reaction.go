@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// AddReaction добавляет реакцию пользователя. Повторная реакция того же вида игнорируется.
+func (r *PostgresRepository) AddReaction(communityID, targetType, targetID, userID, emoji string, at time.Time) error {
+	query := `INSERT INTO reactions (community_id, target_type, target_id, user_id, emoji, created_at)
+	          VALUES ($1, $2, $3, $4, $5, $6)
+	          ON CONFLICT DO NOTHING`
+	_, err := r.db.Exec(query, communityID, targetType, targetID, userID, emoji, at)
+	return err
+}
+
+// RemoveReaction снимает реакцию пользователя
+func (r *PostgresRepository) RemoveReaction(communityID, targetType, targetID, userID, emoji string) error {
+	query := `DELETE FROM reactions
+	          WHERE community_id = $1 AND target_type = $2 AND target_id = $3 AND user_id = $4 AND emoji = $5`
+	_, err := r.db.Exec(query, communityID, targetType, targetID, userID, emoji)
+	return err
+}
+
+// GetReactions возвращает счетчики реакций для набора объектов одного типа.
+// Реакции идут в порядке появления первой реакции своего вида.
+func (r *PostgresRepository) GetReactions(communityID, targetType string, targetIDs []string, viewerID string) (map[string][]*forummodels.ReactionCount, error) {
+	query := `SELECT target_id, emoji, COUNT(*), COALESCE(BOOL_OR(user_id = $4), false)
+	          FROM reactions
+	          WHERE community_id = $1 AND target_type = $2 AND target_id = ANY($3)
+	          GROUP BY target_id, emoji
+	          ORDER BY target_id, MIN(created_at), emoji`
+	rows, err := r.db.Query(query, communityID, targetType, pq.Array(targetIDs), viewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reactions := make(map[string][]*forummodels.ReactionCount)
+	for rows.Next() {
+		var targetID string
+		var reaction forummodels.ReactionCount
+		if err := rows.Scan(&targetID, &reaction.Emoji, &reaction.Count, &reaction.ReactedByMe); err != nil {
+			return nil, err
+		}
+		reactions[targetID] = append(reactions[targetID], &reaction)
+	}
+	return reactions, rows.Err()
+}
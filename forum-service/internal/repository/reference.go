@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// ReplaceReferences заменяет цитаты и ссылки темы или сообщения новым набором
+func (r *PostgresRepository) ReplaceReferences(communityID, sourceType, sourceID, sourceTopicID string, refs []*forummodels.Reference, at time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM content_refs WHERE source_type = $1 AND source_id = $2`, sourceType, sourceID); err != nil {
+		return err
+	}
+	for i, ref := range refs {
+		_, err := tx.Exec(`INSERT INTO content_refs (community_id, source_type, source_id, source_topic_id, position,
+		                                            kind, target_type, target_id, target_topic_id, target_author_id, created_at)
+		                   VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+			communityID, sourceType, sourceID, nullString(sourceTopicID), i,
+			ref.Kind, ref.TargetType, ref.TargetID, nullString(ref.TargetTopicID), ref.TargetAuthorID, at)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetReferences возвращает цитаты и ссылки набора тем или сообщений в порядке появления в тексте
+func (r *PostgresRepository) GetReferences(communityID, sourceType string, sourceIDs []string) (map[string][]*forummodels.Reference, error) {
+	query := `SELECT source_id, kind, target_type, target_id, COALESCE(target_topic_id, ''), target_author_id
+	          FROM content_refs
+	          WHERE community_id = $1 AND source_type = $2 AND source_id = ANY($3)
+	          ORDER BY source_id, position`
+	rows, err := r.db.Query(query, communityID, sourceType, pq.Array(sourceIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refs := make(map[string][]*forummodels.Reference)
+	for rows.Next() {
+		var sourceID string
+		var ref forummodels.Reference
+		err := rows.Scan(&sourceID, &ref.Kind, &ref.TargetType, &ref.TargetID, &ref.TargetTopicID, &ref.TargetAuthorID)
+		if err != nil {
+			return nil, err
+		}
+		refs[sourceID] = append(refs[sourceID], &ref)
+	}
+	return refs, rows.Err()
+}
+
+// GetBacklinks возвращает места в других темах и в чате, где процитированы или упомянуты
+// тема и ее сообщения, от новых к старым. Удаленные темы и сообщения пропускаются.
+func (r *PostgresRepository) GetBacklinks(communityID, topicID string, limit int) ([]*forummodels.Backlink, error) {
+	query := `SELECT r.kind, r.source_type, r.source_id, COALESCE(r.source_topic_id, ''), COALESCE(st.title, ''),
+	                 COALESCE(t.user_id, m.user_id), r.target_type, r.target_id, r.created_at
+	          FROM content_refs r
+	          LEFT JOIN topics t ON r.source_type = 'topic' AND t.id = r.source_id
+	          LEFT JOIN messages m ON r.source_type = 'message' AND m.id = r.source_id
+	          LEFT JOIN topics st ON st.id = r.source_topic_id
+	          WHERE r.community_id = $1 AND r.target_topic_id = $2
+	            AND (r.source_topic_id IS NULL OR r.source_topic_id <> $2)
+	            AND ((t.id IS NOT NULL AND t.deleted = false) OR (m.id IS NOT NULL AND m.deleted_at IS NULL))
+	            AND (st.id IS NULL OR st.deleted = false)
+	          ORDER BY r.created_at DESC, r.source_id, r.position
+	          LIMIT $3`
+	rows, err := r.db.Query(query, communityID, topicID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var backlinks []*forummodels.Backlink
+	for rows.Next() {
+		var backlink forummodels.Backlink
+		err := rows.Scan(&backlink.Kind, &backlink.SourceType, &backlink.SourceID, &backlink.TopicID, &backlink.TopicTitle,
+			&backlink.AuthorID, &backlink.TargetType, &backlink.TargetID, &backlink.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		backlinks = append(backlinks, &backlink)
+	}
+	return backlinks, rows.Err()
+}
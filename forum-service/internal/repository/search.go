@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// headlineOptions параметры ts_headline для сниппетов
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2"
+
+// Search ищет темы и сообщения сообщества, результаты упорядочены по релевантности.
+// Если язык не задан, запрос разбирается всеми поддерживаемыми конфигурациями.
+func (r *PostgresRepository) Search(communityID string, q forummodels.SearchQuery) ([]*forummodels.SearchResult, error) {
+	args := []interface{}{q.Query, communityID}
+
+	tsquery := `websearch_to_tsquery('simple', $1) || websearch_to_tsquery('english', $1) || websearch_to_tsquery('russian', $1)`
+	if q.Language != "" {
+		args = append(args, q.Language)
+		tsquery = fmt.Sprintf("websearch_to_tsquery($%d::regconfig, $1)", len(args))
+	}
+
+	var parts []string
+	if q.Type != forummodels.SearchTypeMessages {
+		parts = append(parts, `SELECT 'topic' AS type, t.id, t.id AS topic_id, t.title,
+		           ts_headline(t.search_config, `+escapeHTML("t.content")+`, q.query, '`+headlineOptions+`') AS snippet,
+		           t.user_id, t.created_at, ts_rank(t.search_vector, q.query) AS rank
+		    FROM topics t, q
+		    WHERE t.community_id = $2 AND t.deleted = false AND t.search_vector @@ q.query`+
+			searchFilters("t", "t.id", q, &args))
+	}
+	if q.Type != forummodels.SearchTypeTopics {
+		parts = append(parts, `SELECT 'message' AS type, m.id, m.topic_id, t.title,
+		           ts_headline(m.search_config, `+escapeHTML("m.content")+`, q.query, '`+headlineOptions+`') AS snippet,
+		           m.user_id, m.created_at, ts_rank(m.search_vector, q.query) AS rank
+		    FROM messages m JOIN topics t ON t.id = m.topic_id, q
+		    WHERE m.community_id = $2 AND m.is_chat = false AND m.deleted_at IS NULL AND t.deleted = false AND m.search_vector @@ q.query`+
+			searchFilters("m", "m.topic_id", q, &args))
+	}
+
+	query := `WITH q AS (SELECT ` + tsquery + ` AS query)
+	          SELECT type, id, topic_id, title, snippet, user_id, created_at, rank FROM (` +
+		strings.Join(parts, " UNION ALL ") + `) results
+	          ORDER BY rank DESC, created_at DESC, id` +
+		fmt.Sprintf(" LIMIT %d OFFSET %d", q.Limit, q.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*forummodels.SearchResult
+	for rows.Next() {
+		var res forummodels.SearchResult
+		err := rows.Scan(
+			&res.Type,
+			&res.ID,
+			&res.TopicID,
+			&res.Title,
+			&res.Snippet,
+			&res.UserID,
+			&res.CreatedAt,
+			&res.Rank,
+		)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &res)
+	}
+	return results, rows.Err()
+}
+
+// searchFilters добавляет фильтры по автору, датам, теме и языку
+func searchFilters(alias, topicColumn string, q forummodels.SearchQuery, args *[]interface{}) string {
+	var b strings.Builder
+	add := func(cond string, value interface{}) {
+		*args = append(*args, value)
+		fmt.Fprintf(&b, " AND "+cond, len(*args))
+	}
+	if q.AuthorID != "" {
+		add(alias+".user_id = $%d", q.AuthorID)
+	}
+	if q.TopicID != "" {
+		add(topicColumn+" = $%d", q.TopicID)
+	}
+	if q.From != nil {
+		add(alias+".created_at >= $%d", *q.From)
+	}
+	if q.To != nil {
+		add(alias+".created_at < $%d", *q.To)
+	}
+	if q.Language != "" {
+		add(alias+".search_config = $%d::regconfig", q.Language)
+	}
+	return b.String()
+}
+
+// escapeHTML экранирует текст колонки до ts_headline, чтобы в сниппете были только теги <mark>
+func escapeHTML(column string) string {
+	return "replace(replace(replace(" + column + ", '&', '&amp;'), '<', '&lt;'), '>', '&gt;')"
+}
+
+// searchConfig выбирает конфигурацию текстового поиска по преобладающему алфавиту текста
+func searchConfig(text string) string {
+	var cyrillic, latin int
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	switch {
+	case cyrillic == 0 && latin == 0:
+		return forummodels.SearchConfigSimple
+	case cyrillic >= latin:
+		return forummodels.SearchConfigRussian
+	default:
+		return forummodels.SearchConfigEnglish
+	}
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"testing"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchConfig(t *testing.T) {
+	assert.Equal(t, forummodels.SearchConfigRussian, searchConfig("Как настроить gRPC клиент"))
+	assert.Equal(t, forummodels.SearchConfigEnglish, searchConfig("How to configure a gRPC client"))
+	assert.Equal(t, forummodels.SearchConfigSimple, searchConfig("12345 !!!"))
+}
+
+func TestSearchFilters(t *testing.T) {
+	args := []interface{}{"query", "community"}
+	where := searchFilters("m", "m.topic_id", forummodels.SearchQuery{
+		AuthorID: "user-1",
+		TopicID:  "topic-1",
+		Language: forummodels.SearchConfigEnglish,
+	}, &args)
+
+	assert.Equal(t, " AND m.user_id = $3 AND m.topic_id = $4 AND m.search_config = $5::regconfig", where)
+	assert.Equal(t, []interface{}{"query", "community", "user-1", "topic-1", "english"}, args)
+}
+
+func TestTopicKeyset_PinnedFirst(t *testing.T) {
+	args := []interface{}{"community"}
+	where, order := topicKeyset(forummodels.PageQuery{
+		Sort:  forummodels.SortNewest,
+		Limit: 21,
+		After: &forummodels.PageCursor{Sort: forummodels.SortNewest, ID: "topic-1", Pinned: true},
+	}, &args)
+
+	assert.Equal(t, " AND (pinned < $2 OR (pinned = $2 AND (created_at, id) < ($3, $4)))", where)
+	assert.Equal(t, "pinned DESC, created_at DESC, id DESC", order)
+	assert.Len(t, args, 4)
+}
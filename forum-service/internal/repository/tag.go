@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// GetTagSettings возвращает правила тегов сообщества или значения по умолчанию
+func (r *PostgresRepository) GetTagSettings(communityID string) (*forummodels.TagSettings, error) {
+	settings := forummodels.TagSettings{MaxTags: forummodels.DefaultMaxTags}
+	err := r.db.QueryRow(`SELECT restricted, max_tags FROM tag_settings WHERE community_id = $1`, communityID).
+		Scan(&settings.Restricted, &settings.MaxTags)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *PostgresRepository) SetTagSettings(communityID string, settings forummodels.TagSettings) error {
+	query := `INSERT INTO tag_settings (community_id, restricted, max_tags) VALUES ($1, $2, $3)
+	          ON CONFLICT (community_id) DO UPDATE SET restricted = EXCLUDED.restricted, max_tags = EXCLUDED.max_tags`
+	_, err := r.db.Exec(query, communityID, settings.Restricted, settings.MaxTags)
+	return err
+}
+
+// GetTags возвращает существующие теги с указанными именами
+func (r *PostgresRepository) GetTags(communityID string, names []string) ([]*forummodels.Tag, error) {
+	query := `SELECT name, official, synonym_of FROM tags WHERE community_id = $1 AND name = ANY($2)`
+	rows, err := r.db.Query(query, communityID, pq.Array(names))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []*forummodels.Tag
+	for rows.Next() {
+		var tag forummodels.Tag
+		var synonymOf sql.NullString
+		if err := rows.Scan(&tag.Name, &tag.Official, &synonymOf); err != nil {
+			return nil, err
+		}
+		tag.SynonymOf = synonymOf.String
+		tags = append(tags, &tag)
+	}
+	return tags, rows.Err()
+}
+
+// CreateOfficialTag добавляет тег в список разрешенных. Существующий тег
+// становится официальным и перестает быть синонимом.
+func (r *PostgresRepository) CreateOfficialTag(communityID, name string) error {
+	query := `INSERT INTO tags (community_id, name, official, created_at) VALUES ($1, $2, true, $3)
+	          ON CONFLICT (community_id, name) DO UPDATE SET official = true, synonym_of = NULL`
+	_, err := r.db.Exec(query, communityID, name, time.Now())
+	return err
+}
+
+// DeleteTag удаляет тег вместе с его синонимами и привязками к темам
+func (r *PostgresRepository) DeleteTag(communityID, name string) error {
+	res, err := r.db.Exec(`DELETE FROM tags WHERE community_id = $1 AND name = $2`, communityID, name)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+// MergeTag переносит темы с тега from на тег into и делает from синонимом into.
+// Синонимы from тоже начинают указывать на into.
+func (r *PostgresRepository) MergeTag(communityID, from, into string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	_, err = tx.Exec(`INSERT INTO tags (community_id, name, created_at) VALUES ($1, $2, $3)
+	                  ON CONFLICT (community_id, name) DO NOTHING`, communityID, into, now)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO topic_tags (topic_id, community_id, tag)
+	                  SELECT topic_id, community_id, $3 FROM topic_tags
+	                  WHERE community_id = $1 AND tag = $2
+	                  ON CONFLICT (topic_id, tag) DO NOTHING`, communityID, from, into)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM topic_tags WHERE community_id = $1 AND tag = $2`, communityID, from)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO tags (community_id, name, synonym_of, official, created_at) VALUES ($1, $2, $3, false, $4)
+	                  ON CONFLICT (community_id, name) DO UPDATE SET synonym_of = EXCLUDED.synonym_of, official = false`,
+		communityID, from, into, now)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE tags SET synonym_of = $3 WHERE community_id = $1 AND synonym_of = $2`, communityID, from, into)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SearchTags возвращает основные теги, которые начинаются с prefix
+// (или имеют такой синоним), упорядоченные по числу тем
+func (r *PostgresRepository) SearchTags(communityID, prefix string, limit int) ([]*forummodels.Tag, error) {
+	query := `SELECT tg.name, tg.official, COUNT(t.id) AS topic_count
+	          FROM tags tg
+	          LEFT JOIN topic_tags tt ON tt.community_id = tg.community_id AND tt.tag = tg.name
+	          LEFT JOIN topics t ON t.id = tt.topic_id AND t.deleted = false
+	          WHERE tg.community_id = $1 AND tg.synonym_of IS NULL
+	            AND (tg.name LIKE $2 OR EXISTS (
+	                SELECT 1 FROM tags s
+	                WHERE s.community_id = tg.community_id AND s.synonym_of = tg.name AND s.name LIKE $2))
+	          GROUP BY tg.name, tg.official
+	          ORDER BY topic_count DESC, tg.name
+	          LIMIT $3`
+	rows, err := r.db.Query(query, communityID, prefix+"%", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []*forummodels.Tag
+	for rows.Next() {
+		var tag forummodels.Tag
+		if err := rows.Scan(&tag.Name, &tag.Official, &tag.TopicCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, &tag)
+	}
+	return tags, rows.Err()
+}
+
+// insertTopicTags привязывает теги к теме, создавая неизвестные теги
+func insertTopicTags(tx *sql.Tx, communityID, topicID string, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err := tx.Exec(`INSERT INTO tags (community_id, name, created_at)
+	                   SELECT $1, unnest($2::varchar[]), $3
+	                   ON CONFLICT (community_id, name) DO NOTHING`,
+		communityID, pq.Array(tags), time.Now())
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO topic_tags (topic_id, community_id, tag)
+	                  SELECT $1, $2, unnest($3::varchar[])
+	                  ON CONFLICT (topic_id, tag) DO NOTHING`,
+		topicID, communityID, pq.Array(tags))
+	return err
+}
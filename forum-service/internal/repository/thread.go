@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// threadColumns колонки сообщения, из которых собирается ThreadMessage
+const threadColumns = messageColumns + `, parent_id, depth, reply_count`
+
+// GetMessage возвращает сообщение темы или чата
+func (r *PostgresRepository) GetMessage(communityID, messageID string) (*forummodels.ThreadMessage, error) {
+	query := `SELECT ` + threadColumns + `
+	          FROM messages WHERE id = $1 AND community_id = $2`
+	message, err := scanThreadMessage(r.db.QueryRow(query, messageID, communityID))
+	if err == sql.ErrNoRows {
+		return nil, ErrMessageNotFound
+	}
+	return message, err
+}
+
+// CreateThreadMessage сохраняет ответ в теме и увеличивает счетчик ответов родителя
+func (r *PostgresRepository) CreateThreadMessage(communityID string, message *forummodels.ThreadMessage) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if message.ParentID != "" {
+		res, err := tx.Exec(`UPDATE messages SET reply_count = reply_count + 1
+		                     WHERE id = $1 AND community_id = $2 AND topic_id = $3`,
+			message.ParentID, communityID, message.TopicID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return ErrMessageNotFound
+		}
+	}
+
+	query := `INSERT INTO messages (id, community_id, topic_id, user_id, content, content_html, created_at, is_chat, search_config, parent_id, depth)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, false, $8, $9, $10)`
+	_, err = tx.Exec(query,
+		message.ID,
+		communityID,
+		message.TopicID,
+		message.UserID,
+		message.Content,
+		message.ContentHTML,
+		message.CreatedAt,
+		searchConfig(message.Content),
+		nullString(message.ParentID),
+		message.Depth,
+	)
+	if err != nil {
+		return translateError(err)
+	}
+
+	if err := insertAttachmentRefs(tx, forummodels.AttachmentTargetMessage, message.ID, message.Attachments); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE topics SET message_count = message_count + 1 WHERE id = $1`, message.TopicID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetThread возвращает страницу прямых ответов на parentID (или корневых сообщений темы,
+// если parentID пуст) вместе со всеми их потомками глубиной не больше maxDepth.
+// Порядок потомков не гарантируется, дерево собирает сервис.
+func (r *PostgresRepository) GetThread(communityID, topicID, parentID string, maxDepth int, page forummodels.PageQuery) ([]*forummodels.ThreadMessage, error) {
+	args := []interface{}{communityID, topicID}
+	parent := ` AND parent_id IS NULL`
+	if parentID != "" {
+		args = append(args, parentID)
+		parent = fmt.Sprintf(` AND parent_id = $%d`, len(args))
+	}
+	where, order := keyset(page, &args)
+	args = append(args, maxDepth)
+
+	query := `WITH RECURSIVE roots AS (
+	              SELECT id FROM messages
+	              WHERE community_id = $1 AND topic_id = $2 AND is_chat = false` + parent + where +
+		` ORDER BY ` + order + fmt.Sprintf(" LIMIT %d", page.Limit) + `
+	          ), thread AS (
+	              SELECT id FROM roots
+	              UNION ALL
+	              SELECT m.id FROM messages m JOIN thread t ON m.parent_id = t.id
+	              WHERE m.depth <= ` + fmt.Sprintf("$%d", len(args)) + `
+	          )
+	          SELECT ` + threadColumns + ` FROM messages WHERE id IN (SELECT id FROM thread)`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*forummodels.ThreadMessage
+	for rows.Next() {
+		message, err := scanThreadMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, rows.Err()
+}
+
+func scanThreadMessage(row rowScanner) (*forummodels.ThreadMessage, error) {
+	var message forummodels.ThreadMessage
+	var editedAt sql.NullTime
+	var parentID sql.NullString
+	err := row.Scan(
+		&message.ID,
+		&message.TopicID,
+		&message.UserID,
+		&message.Content,
+		&message.ContentHTML,
+		&message.CreatedAt,
+		&message.IsChat,
+		&editedAt,
+		&message.Deleted,
+		&message.Score,
+		&parentID,
+		&message.Depth,
+		&message.ReplyCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if editedAt.Valid {
+		message.EditedAt = &editedAt.Time
+	}
+	message.ParentID = parentID.String
+	return &message, nil
+}
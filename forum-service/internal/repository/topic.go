@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+func (r *PostgresRepository) GetTopic(communityID, topicID string) (*forummodels.TopicDetail, error) {
+	query := `SELECT ` + topicDetailColumns + `
+	          FROM topics WHERE id = $1 AND community_id = $2 AND deleted = false`
+	return scanTopicDetail(r.db.QueryRow(query, topicID, communityID))
+}
+
+// UpdateTopic сохраняет новую версию темы и добавляет ее в topic_revisions.
+// Строка темы блокируется, чтобы параллельные правки не получили один номер ревизии.
+func (r *PostgresRepository) UpdateTopic(communityID, topicID, title, content, contentHTML, editorID string, at time.Time) (*forummodels.TopicDetail, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var revision int
+	err = tx.QueryRow(`SELECT revision FROM topics
+	                   WHERE id = $1 AND community_id = $2 AND deleted = false
+	                   FOR UPDATE`, topicID, communityID).Scan(&revision)
+	if err == sql.ErrNoRows {
+		return nil, ErrTopicNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	revision++
+
+	// search_vector пересчитывается Postgres как генерируемая колонка
+	query := `UPDATE topics SET title = $1, content = $2, content_html = $3, revision = $4, updated_at = $5, search_config = $6
+	          WHERE id = $7
+	          RETURNING ` + topicDetailColumns
+	topic, err := scanTopicDetail(tx.QueryRow(query, title, content, contentHTML, revision, at, searchConfig(title+" "+content), topicID))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`INSERT INTO topic_revisions (topic_id, revision, title, content, edited_by, created_at)
+	                  VALUES ($1, $2, $3, $4, $5, $6)`,
+		topicID, revision, title, content, editorID, at)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return topic, nil
+}
+
+func (r *PostgresRepository) GetTopicRevisions(communityID, topicID string) ([]*forummodels.TopicRevision, error) {
+	query := `SELECT tr.topic_id, tr.revision, tr.title, tr.content, tr.edited_by, tr.created_at
+	          FROM topic_revisions tr
+	          JOIN topics t ON t.id = tr.topic_id
+	          WHERE tr.topic_id = $1 AND t.community_id = $2 AND t.deleted = false
+	          ORDER BY tr.revision DESC`
+	rows, err := r.db.Query(query, topicID, communityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*forummodels.TopicRevision
+	for rows.Next() {
+		var rev forummodels.TopicRevision
+		if err := rows.Scan(&rev.TopicID, &rev.Revision, &rev.Title, &rev.Content, &rev.EditedBy, &rev.CreatedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &rev)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *PostgresRepository) GetTopicRevision(communityID, topicID string, revision int) (*forummodels.TopicRevision, error) {
+	query := `SELECT tr.topic_id, tr.revision, tr.title, tr.content, tr.edited_by, tr.created_at
+	          FROM topic_revisions tr
+	          JOIN topics t ON t.id = tr.topic_id
+	          WHERE tr.topic_id = $1 AND tr.revision = $2 AND t.community_id = $3 AND t.deleted = false`
+	var rev forummodels.TopicRevision
+	err := r.db.QueryRow(query, topicID, revision, communityID).Scan(
+		&rev.TopicID, &rev.Revision, &rev.Title, &rev.Content, &rev.EditedBy, &rev.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// topicTagsColumn выбирает теги темы одним массивом
+const topicTagsColumn = `ARRAY(SELECT tag FROM topic_tags WHERE topic_id = topics.id ORDER BY tag)`
+
+// topicDetailColumns колонки, которые читает scanTopicDetail
+const topicDetailColumns = `id, title, content, COALESCE(content_html, ''), user_id, created_at, revision, updated_at, category_id, ` +
+	topicTagsColumn + `, pinned, locked, archived, score`
+
+// SetTopicState меняет закрепление, закрытие и архивирование темы
+func (r *PostgresRepository) SetTopicState(communityID, topicID string, state forummodels.TopicState) (*forummodels.TopicDetail, error) {
+	query := `UPDATE topics SET pinned = $1, locked = $2, archived = $3
+	          WHERE id = $4 AND community_id = $5 AND deleted = false
+	          RETURNING ` + topicDetailColumns
+	return scanTopicDetail(r.db.QueryRow(query, state.Pinned, state.Locked, state.Archived, topicID, communityID))
+}
+
+func scanTopicDetail(row *sql.Row) (*forummodels.TopicDetail, error) {
+	var topic forummodels.TopicDetail
+	var updatedAt sql.NullTime
+	var categoryID sql.NullString
+	err := row.Scan(
+		&topic.ID,
+		&topic.Title,
+		&topic.Content,
+		&topic.ContentHTML,
+		&topic.UserID,
+		&topic.CreatedAt,
+		&topic.Revision,
+		&updatedAt,
+		&categoryID,
+		pq.Array(&topic.Tags),
+		&topic.Pinned,
+		&topic.Locked,
+		&topic.Archived,
+		&topic.Score,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrTopicNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	topic.CategoryID = categoryID.String
+	if updatedAt.Valid {
+		topic.UpdatedAt = &updatedAt.Time
+	}
+	return &topic, nil
+}
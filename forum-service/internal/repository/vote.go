@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// voteTables таблицы, в которых хранится рейтинг объектов голосования
+var voteTables = map[string]string{
+	forummodels.VoteTargetTopic:   "topics",
+	forummodels.VoteTargetMessage: "messages",
+}
+
+// SetVote сохраняет голос пользователя (0 — отмена) и пересчитывает рейтинг объекта.
+// Объект голосования блокируется до чтения прежнего голоса, чтобы одновременные
+// запросы, в том числе первые голоса, не изменили рейтинг дважды.
+func (r *PostgresRepository) SetVote(communityID, targetType, targetID, userID, authorID string, value int, at time.Time) (int, error) {
+	table, ok := voteTables[targetType]
+	if !ok {
+		return 0, ErrInvalidRequest
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var lockedID string
+	err = tx.QueryRow(`SELECT id FROM `+table+` WHERE id = $1 AND community_id = $2 FOR UPDATE`,
+		targetID, communityID).Scan(&lockedID)
+	if err == sql.ErrNoRows {
+		return 0, ErrInvalidRequest
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var previous int
+	err = tx.QueryRow(`SELECT value FROM votes
+	                   WHERE target_type = $1 AND target_id = $2 AND user_id = $3`,
+		targetType, targetID, userID).Scan(&previous)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	switch {
+	case value == 0:
+		_, err = tx.Exec(`DELETE FROM votes WHERE target_type = $1 AND target_id = $2 AND user_id = $3`,
+			targetType, targetID, userID)
+	default:
+		_, err = tx.Exec(`INSERT INTO votes (community_id, target_type, target_id, user_id, author_id, value, created_at)
+		                  VALUES ($1, $2, $3, $4, $5, $6, $7)
+		                  ON CONFLICT (target_type, target_id, user_id)
+		                  DO UPDATE SET value = EXCLUDED.value, created_at = EXCLUDED.created_at`,
+			communityID, targetType, targetID, userID, authorID, value, at)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var score int
+	err = tx.QueryRow(`UPDATE `+table+` SET score = score + $1
+	                   WHERE id = $2 AND community_id = $3
+	                   RETURNING score`, value-previous, targetID, communityID).Scan(&score)
+	if err == sql.ErrNoRows {
+		return 0, ErrInvalidRequest
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return score, tx.Commit()
+}
+
+// GetUserKarma суммирует голоса за темы и сообщения пользователя во всех сообществах
+func (r *PostgresRepository) GetUserKarma(userID string) (*forummodels.Karma, error) {
+	karma := forummodels.Karma{UserID: userID}
+	err := r.db.QueryRow(`SELECT COALESCE(SUM(value), 0),
+	                             COUNT(*) FILTER (WHERE value > 0),
+	                             COUNT(*) FILTER (WHERE value < 0)
+	                      FROM votes WHERE author_id = $1`, userID).
+		Scan(&karma.Karma, &karma.Upvotes, &karma.Downvotes)
+	if err != nil {
+		return nil, err
+	}
+	return &karma, nil
+}
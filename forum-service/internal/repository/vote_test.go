@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresRepository_SetVote_ConcurrentFirstVotes(t *testing.T) {
+	repo := testRepository(t)
+	topic := createTestTopic(t, repo, "default", "")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.SetVote("default", forummodels.VoteTargetTopic, topic.ID,
+				"test-user-id", topic.UserID, 1, time.Now())
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	score, err := repo.SetVote("default", forummodels.VoteTargetTopic, topic.ID, "test-user-id", topic.UserID, 1, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, score)
+}
+
+func TestPostgresRepository_SetVote_UnknownTarget(t *testing.T) {
+	repo := testRepository(t)
+
+	_, err := repo.SetVote("default", forummodels.VoteTargetTopic, uuid.NewString(), "test-user-id", "test-user-id", 1, time.Now())
+
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
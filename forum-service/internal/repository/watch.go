@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// SetWatch задает уровень слежения пользователя за темой или разделом
+func (r *PostgresRepository) SetWatch(communityID, userID, targetType, targetID, level string, at time.Time) error {
+	query := `INSERT INTO watches (community_id, user_id, target_type, target_id, level, created_at)
+	          VALUES ($1, $2, $3, $4, $5, $6)
+	          ON CONFLICT (user_id, target_type, target_id)
+	          DO UPDATE SET level = EXCLUDED.level, created_at = EXCLUDED.created_at`
+	_, err := r.db.Exec(query, communityID, userID, targetType, targetID, level, at)
+	return err
+}
+
+// AddWatch задает уровень слежения, только если пользователь еще не выбрал его сам
+func (r *PostgresRepository) AddWatch(communityID, userID, targetType, targetID, level string, at time.Time) error {
+	query := `INSERT INTO watches (community_id, user_id, target_type, target_id, level, created_at)
+	          VALUES ($1, $2, $3, $4, $5, $6)
+	          ON CONFLICT DO NOTHING`
+	_, err := r.db.Exec(query, communityID, userID, targetType, targetID, level, at)
+	return err
+}
+
+// DeleteWatch возвращает уровень слежения к значению по умолчанию
+func (r *PostgresRepository) DeleteWatch(communityID, userID, targetType, targetID string) error {
+	query := `DELETE FROM watches
+	          WHERE community_id = $1 AND user_id = $2 AND target_type = $3 AND target_id = $4`
+	_, err := r.db.Exec(query, communityID, userID, targetType, targetID)
+	return err
+}
+
+// GetWatch возвращает выбранный пользователем уровень слежения или пустую строку
+func (r *PostgresRepository) GetWatch(communityID, userID, targetType, targetID string) (string, error) {
+	var level string
+	err := r.db.QueryRow(`SELECT level FROM watches
+	                      WHERE community_id = $1 AND user_id = $2 AND target_type = $3 AND target_id = $4`,
+		communityID, userID, targetType, targetID).Scan(&level)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return level, err
+}
+
+// GetTopicWatchers возвращает действующие уровни слежения за темой по пользователям.
+// Настройка темы важнее настройки ее раздела.
+func (r *PostgresRepository) GetTopicWatchers(communityID, topicID, categoryID string) (map[string]string, error) {
+	query := `SELECT user_id, target_type, level
+	          FROM watches
+	          WHERE community_id = $1
+	            AND ((target_type = $2 AND target_id = $3) OR (target_type = $4 AND target_id = $5))`
+	rows, err := r.db.Query(query, communityID,
+		forummodels.WatchTargetTopic, topicID, forummodels.WatchTargetCategory, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	watchers := make(map[string]string)
+	for rows.Next() {
+		var userID, targetType, level string
+		if err := rows.Scan(&userID, &targetType, &level); err != nil {
+			return nil, err
+		}
+		if _, ok := watchers[userID]; ok && targetType == forummodels.WatchTargetCategory {
+			continue
+		}
+		watchers[userID] = level
+	}
+	return watchers, rows.Err()
+}
@@ -0,0 +1,385 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/luckermt/forum-app/forum-service/internal/imaging"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/repository"
+	"github.com/luckermt/forum-app/forum-service/internal/storage"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// AttachmentLimits ограничения на загружаемые файлы
+type AttachmentLimits struct {
+	MaxSize int64
+	// AllowedTypes MIME-типы, определенные по содержимому файла
+	AllowedTypes []string
+}
+
+// DefaultAttachmentLimits ограничения по умолчанию: картинки, PDF, текстовые логи и архивы до 10 МБ.
+// HTML и SVG не разрешаются, потому что их можно открыть в браузере как страницу.
+var DefaultAttachmentLimits = AttachmentLimits{
+	MaxSize: 10 << 20,
+	AllowedTypes: []string{
+		"image/png", "image/jpeg", "image/gif", "image/webp",
+		"application/pdf", "text/plain", "application/zip", "application/x-gzip",
+	},
+}
+
+// maxFilenameLength ограничение длины имени файла в символах
+const maxFilenameLength = 255
+
+// SetAttachmentStorage задает хранилище вложений и ограничения на загрузку.
+// Без хранилища загрузка и скачивание вложений недоступны.
+func (s *forumServiceImpl) SetAttachmentStorage(store storage.BlobStore, limits AttachmentLimits) {
+	s.blobs = store
+	s.attachmentLimits = limits
+}
+
+// UploadAttachment сохраняет файл пользователя. Тип определяется по содержимому,
+// а не по имени или заголовкам запроса. Содержимое, которое уже есть в хранилище, повторно не пишется.
+func (s *forumServiceImpl) UploadAttachment(communityID, userID, filename string, content io.Reader) (*forummodels.Attachment, error) {
+	if s.blobs == nil {
+		return nil, ErrAttachmentsDisabled
+	}
+	if err := s.requireMember(communityID, userID); err != nil {
+		return nil, err
+	}
+
+	// Файл сначала пишется во временный, чтобы посчитать хэш до записи в хранилище
+	tmp, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(content, s.attachmentLimits.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if size > s.attachmentLimits.MaxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+	if size == 0 {
+		return nil, ErrInvalidAttachment
+	}
+
+	head := make([]byte, 512)
+	n, err := tmp.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	contentType := sniffContentType(head[:n])
+	if !contains(s.attachmentLimits.AllowedTypes, contentType) {
+		return nil, ErrAttachmentType
+	}
+
+	key := hex.EncodeToString(hash.Sum(nil))
+	exists, err := s.blobs.Exists(key)
+	if err != nil {
+		logger.Log.Error("Failed to check attachment blob", zap.String("sha256", key), zap.Error(err))
+		return nil, err
+	}
+	if !exists {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if err := s.blobs.Put(key, tmp, size, contentType); err != nil {
+			logger.Log.Error("Failed to store attachment blob", zap.String("sha256", key), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	// Картинки отдаются остальным только после обработки в фоне
+	status := forummodels.AttachmentReady
+	if isImageType(contentType) {
+		status = forummodels.AttachmentPending
+	}
+	attachment := &forummodels.Attachment{
+		ID:          generateID(),
+		UserID:      userID,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Size:        size,
+		SHA256:      key,
+		Status:      status,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.repo.CreateAttachment(communityID, attachment); err != nil {
+		logger.Log.Error("Failed to create attachment",
+			zap.String("community_id", communityID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// OpenAttachment открывает вложение или его обработанную копию для скачивания. Свои вложения доступны
+// загрузившему всегда, чужие — участникам сообщества, если вложение прикреплено к неудаленной теме
+// или сообщению. Вместо исходной картинки отдается ее копия без метаданных; пока картинка
+// не обработана, исходный файл получает только загрузивший.
+func (s *forumServiceImpl) OpenAttachment(communityID, attachmentID, variant, userID string) (*forummodels.AttachmentFile, io.ReadCloser, error) {
+	if s.blobs == nil {
+		return nil, nil, ErrAttachmentsDisabled
+	}
+	attachment, err := s.repo.GetAttachment(communityID, attachmentID)
+	if errors.Is(err, repository.ErrAttachmentNotFound) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if attachment.UserID != userID {
+		if err := s.requireMember(communityID, userID); err != nil {
+			return nil, nil, err
+		}
+		visible, err := s.repo.IsAttachmentVisible(communityID, attachmentID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !visible {
+			return nil, nil, ErrNotFound
+		}
+	}
+
+	file, err := s.attachmentFile(attachment, variant, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := s.blobs.Get(file.Key)
+	if err != nil {
+		logger.Log.Error("Failed to open attachment blob",
+			zap.String("attachment_id", attachmentID),
+			zap.String("key", file.Key),
+			zap.Error(err))
+		return nil, nil, err
+	}
+	return file, content, nil
+}
+
+// attachmentFile выбирает файл для скачивания: исходный или обработанную копию
+func (s *forumServiceImpl) attachmentFile(attachment *forummodels.Attachment, variant, userID string) (*forummodels.AttachmentFile, error) {
+	switch attachment.Status {
+	case forummodels.AttachmentRejected, forummodels.AttachmentFailed:
+		return nil, ErrImageRejected
+	case forummodels.AttachmentPending:
+		if variant != "" || attachment.UserID != userID {
+			return nil, ErrImageProcessing
+		}
+		return &forummodels.AttachmentFile{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+			Key:         attachment.SHA256,
+		}, nil
+	}
+
+	name := variant
+	if name == "" {
+		if !isImageType(attachment.ContentType) {
+			return &forummodels.AttachmentFile{
+				Filename:    attachment.Filename,
+				ContentType: attachment.ContentType,
+				Size:        attachment.Size,
+				Key:         attachment.SHA256,
+			}, nil
+		}
+		name = imaging.OriginalName
+	}
+
+	variants, err := s.repo.GetAttachmentVariants([]string{attachment.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range variants[attachment.ID] {
+		if v.Name == name {
+			return &forummodels.AttachmentFile{
+				Filename:    variantFilename(attachment.Filename, v.Name, v.ContentType),
+				ContentType: v.ContentType,
+				Size:        v.Size,
+				Key:         v.Key,
+			}, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// resolveAttachments проверяет, что вложения для новой темы или сообщения
+// существуют и загружены их автором
+func (s *forumServiceImpl) resolveAttachments(communityID, userID string, ids []string) ([]*forummodels.Attachment, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > forummodels.MaxAttachmentsPerPost {
+		return nil, ErrInvalidAttachment
+	}
+
+	found, err := s.repo.GetAttachments(communityID, unique)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*forummodels.Attachment, len(found))
+	for _, attachment := range found {
+		byID[attachment.ID] = attachment
+	}
+
+	attachments := make([]*forummodels.Attachment, 0, len(unique))
+	for _, id := range unique {
+		attachment, ok := byID[id]
+		if !ok || attachment.UserID != userID {
+			return nil, ErrInvalidAttachment
+		}
+		if attachment.Status == forummodels.AttachmentRejected || attachment.Status == forummodels.AttachmentFailed {
+			return nil, ErrImageRejected
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+// attachTopicAttachments заполняет вложения темы
+func (s *forumServiceImpl) attachTopicAttachments(communityID string, topic *forummodels.TopicDetail) error {
+	attachments, err := s.repo.GetTargetAttachments(communityID, forummodels.AttachmentTargetTopic, []string{topic.ID})
+	if err != nil {
+		logger.Log.Error("Failed to get topic attachments",
+			zap.String("topic_id", topic.ID),
+			zap.Error(err))
+		return err
+	}
+	topic.Attachments = attachments[topic.ID]
+	return s.attachVariants(topic.Attachments)
+}
+
+// attachMessageAttachments заполняет вложения у страницы сообщений; у удаленных их не показываем
+func (s *forumServiceImpl) attachMessageAttachments(communityID string, messages []*forummodels.MessageDetail) error {
+	ids := make([]string, 0, len(messages))
+	for _, message := range messages {
+		if !message.Deleted {
+			ids = append(ids, message.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	attachments, err := s.repo.GetTargetAttachments(communityID, forummodels.AttachmentTargetMessage, ids)
+	if err != nil {
+		logger.Log.Error("Failed to get message attachments",
+			zap.String("community_id", communityID),
+			zap.Error(err))
+		return err
+	}
+	var all []*forummodels.Attachment
+	for _, message := range messages {
+		message.Attachments = attachments[message.ID]
+		all = append(all, message.Attachments...)
+	}
+	return s.attachVariants(all)
+}
+
+// attachVariants заполняет обработанные копии у готовых картинок
+func (s *forumServiceImpl) attachVariants(attachments []*forummodels.Attachment) error {
+	var ids []string
+	for _, attachment := range attachments {
+		if attachment.Status == forummodels.AttachmentReady && isImageType(attachment.ContentType) {
+			ids = append(ids, attachment.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	variants, err := s.repo.GetAttachmentVariants(ids)
+	if err != nil {
+		logger.Log.Error("Failed to get attachment variants", zap.Error(err))
+		return err
+	}
+	for _, attachment := range attachments {
+		attachment.Variants = variants[attachment.ID]
+	}
+	return nil
+}
+
+// sniffContentType определяет MIME-тип по первым байтам файла, без параметров вроде charset
+func sniffContentType(head []byte) string {
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mediaType
+}
+
+// isImageType сообщает, что вложение обрабатывается как картинка
+func isImageType(contentType string) bool {
+	switch contentType {
+	case "image/png", "image/jpeg", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
+// variantFilename имя файла обработанной копии: расширение по ее типу,
+// к имени уменьшенной копии добавляется название размера
+func variantFilename(filename, name, contentType string) string {
+	base := strings.TrimSuffix(filename, filepath.Ext(filename))
+	if base == "" {
+		base = "image"
+	}
+	if name != imaging.OriginalName {
+		base += "-" + name
+	}
+	switch contentType {
+	case "image/jpeg":
+		return base + ".jpg"
+	case "image/png":
+		return base + ".png"
+	case "image/gif":
+		return base + ".gif"
+	case "image/webp":
+		return base + ".webp"
+	}
+	return filename
+}
+
+// cleanFilename оставляет от имени файла только базовое имя без управляющих символов
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	if runes := []rune(name); len(runes) > maxFilenameLength {
+		name = string(runes[:maxFilenameLength])
+	}
+	return name
+}
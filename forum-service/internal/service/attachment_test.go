@@ -0,0 +1,58 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSniffContentType(t *testing.T) {
+	assert.Equal(t, "image/png", sniffContentType([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")))
+	assert.Equal(t, "text/plain", sniffContentType([]byte("2024-01-01 ERROR connection refused\n")))
+	assert.Equal(t, "text/html", sniffContentType([]byte("<html><script>alert(1)</script>")))
+	assert.Equal(t, "application/pdf", sniffContentType([]byte("%PDF-1.7")))
+}
+
+func TestCleanFilename(t *testing.T) {
+	assert.Equal(t, "passwd", cleanFilename("../../etc/passwd"))
+	assert.Equal(t, "log.txt", cleanFilename(`C:\Users\me\log.txt`))
+	assert.Equal(t, "ab.png", cleanFilename("a\"b\n.png"))
+	assert.Equal(t, "file", cleanFilename(""))
+	assert.Len(t, []rune(cleanFilename(strings.Repeat("я", 300))), maxFilenameLength)
+}
+
+func TestVariantFilename(t *testing.T) {
+	assert.Equal(t, "cat.jpg", variantFilename("cat.jpeg", "original", "image/jpeg"))
+	assert.Equal(t, "cat-thumb.png", variantFilename("cat.png", "thumb", "image/png"))
+	assert.Equal(t, "shot-medium.jpg", variantFilename("shot.webp", "medium", "image/jpeg"))
+	assert.Equal(t, "image-large.jpg", variantFilename(".png", "large", "image/jpeg"))
+}
+
+func TestAttachmentFile_PendingImage(t *testing.T) {
+	s := &forumServiceImpl{}
+	attachment := &forummodels.Attachment{
+		ID: "a1", UserID: "owner", Filename: "cat.png", ContentType: "image/png",
+		Size: 10, SHA256: "abc", Status: forummodels.AttachmentPending,
+	}
+
+	file, err := s.attachmentFile(attachment, "", "owner")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", file.Key)
+
+	_, err = s.attachmentFile(attachment, "", "other")
+	assert.ErrorIs(t, err, ErrImageProcessing)
+	_, err = s.attachmentFile(attachment, "thumb", "owner")
+	assert.ErrorIs(t, err, ErrImageProcessing)
+
+	attachment.Status = forummodels.AttachmentRejected
+	_, err = s.attachmentFile(attachment, "", "owner")
+	assert.ErrorIs(t, err, ErrImageRejected)
+}
+
+func TestImageRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Minute, imageRetryDelay(1))
+	assert.Equal(t, 16*time.Minute, imageRetryDelay(4))
+}
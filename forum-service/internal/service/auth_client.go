@@ -2,32 +2,62 @@ package service
 
 import (
 	"context"
+	"slices"
 
 	"github.com/luckermt/forum-app/shared/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// Области доступа токенов сторонних приложений
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+// TokenInfo результат проверки токена в auth-service
+type TokenInfo struct {
+	UserID string
+	// ClientID заполнен только для токенов сторонних OAuth приложений
+	ClientID string
+	Scopes   []string
+}
+
+// HasScope разрешает собственным токенам форума любые действия,
+// а токенам приложений только выданные пользователем области доступа
+func (t *TokenInfo) HasScope(scope string) bool {
+	return t.ClientID == "" || slices.Contains(t.Scopes, scope)
+}
+
 type AuthClient interface {
-	ValidateToken(token string) (string, error)
+	ValidateToken(token string) (*TokenInfo, error)
 	IsUserAdmin(userID string) (bool, error)
+	ResolveCommunity(host, slug string) (string, error)
+	GetMemberRole(communityID, userID string) (string, bool, error)
+	// ResolveUsernames возвращает ID незаблокированных участников сообщества
+	// по их именам (в написании auth-service)
+	ResolveUsernames(communityID string, usernames []string) (map[string]string, error)
 }
 
 type GRPCAuthClient struct {
-	client proto.AuthServiceClient
+	client          proto.AuthServiceClient
+	communityClient proto.CommunityServiceClient
+	usersClient     proto.UserDirectoryServiceClient
 }
 
-func NewGRPCAuthClient(client proto.AuthServiceClient) AuthClient {
-	return &GRPCAuthClient{client: client}
+func NewGRPCAuthClient(client proto.AuthServiceClient, communityClient proto.CommunityServiceClient, usersClient proto.UserDirectoryServiceClient) AuthClient {
+	return &GRPCAuthClient{client: client, communityClient: communityClient, usersClient: usersClient}
 }
 
-func (c *GRPCAuthClient) ValidateToken(token string) (string, error) {
+func (c *GRPCAuthClient) ValidateToken(token string) (*TokenInfo, error) {
 	resp, err := c.client.ValidateToken(context.Background(), &proto.TokenRequest{Token: token})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if !resp.Valid {
-		return "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
-	return resp.UserId, nil
+	return &TokenInfo{UserID: resp.UserId, ClientID: resp.ClientId, Scopes: resp.Scopes}, nil
 }
 
 func (c *GRPCAuthClient) IsUserAdmin(userID string) (bool, error) {
@@ -37,3 +67,43 @@ func (c *GRPCAuthClient) IsUserAdmin(userID string) (bool, error) {
 	}
 	return resp.Role == "admin", nil
 }
+
+func (c *GRPCAuthClient) ResolveCommunity(host, slug string) (string, error) {
+	resp, err := c.communityClient.ResolveCommunity(context.Background(), &proto.ResolveCommunityRequest{
+		Host: host,
+		Slug: slug,
+	})
+	if status.Code(err) == codes.NotFound {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return resp.Id, nil
+}
+
+func (c *GRPCAuthClient) GetMemberRole(communityID, userID string) (string, bool, error) {
+	resp, err := c.communityClient.GetMemberRole(context.Background(), &proto.MemberRoleRequest{
+		CommunityId: communityID,
+		UserId:      userID,
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return resp.Role, resp.Blocked, nil
+}
+
+func (c *GRPCAuthClient) ResolveUsernames(communityID string, usernames []string) (map[string]string, error) {
+	resp, err := c.usersClient.ResolveUsernames(context.Background(), &proto.ResolveUsernamesRequest{
+		Usernames:   usernames,
+		CommunityId: communityID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	users := make(map[string]string, len(resp.Users))
+	for _, user := range resp.Users {
+		users[user.Username] = user.Id
+	}
+	return users, nil
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenInfo_HasScope(t *testing.T) {
+	forumToken := &TokenInfo{UserID: "user-1"}
+	appToken := &TokenInfo{UserID: "user-1", ClientID: "client-1", Scopes: []string{ScopeRead}}
+	noScopes := &TokenInfo{UserID: "user-1", ClientID: "client-1"}
+
+	assert.True(t, forumToken.HasScope(ScopeWrite))
+	assert.True(t, appToken.HasScope(ScopeRead))
+	assert.False(t, appToken.HasScope(ScopeWrite))
+	assert.False(t, noScopes.HasScope(ScopeRead))
+}
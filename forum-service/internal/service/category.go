@@ -0,0 +1,183 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/repository"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+var categorySlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+func (s *forumServiceImpl) CreateCategory(communityID, userID string, req forummodels.CategoryRequest) (*forummodels.Category, error) {
+	if err := s.requireCommunityAdmin(communityID, userID); err != nil {
+		return nil, err
+	}
+
+	category := &forummodels.Category{
+		ID:        generateID(),
+		CreatedAt: time.Now(),
+	}
+	if err := s.applyCategoryRequest(communityID, category, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateCategory(communityID, category); err != nil {
+		return nil, categoryError(err)
+	}
+	return category, nil
+}
+
+func (s *forumServiceImpl) UpdateCategory(communityID, userID, categoryID string, req forummodels.CategoryRequest) (*forummodels.Category, error) {
+	if err := s.requireCommunityAdmin(communityID, userID); err != nil {
+		return nil, err
+	}
+
+	category, err := s.getCategory(communityID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyCategoryRequest(communityID, category, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateCategory(communityID, category); err != nil {
+		return nil, categoryError(err)
+	}
+	return category, nil
+}
+
+func (s *forumServiceImpl) DeleteCategory(communityID, userID, categoryID string) error {
+	if err := s.requireCommunityAdmin(communityID, userID); err != nil {
+		return err
+	}
+	return categoryError(s.repo.DeleteCategory(communityID, categoryID))
+}
+
+// GetCategoryTree возвращает корневые разделы сообщества с вложенными подразделами
+func (s *forumServiceImpl) GetCategoryTree(communityID string) ([]*forummodels.Category, error) {
+	categories, err := s.repo.GetCategories(communityID)
+	if err != nil {
+		logger.Log.Error("Failed to get categories",
+			zap.String("community_id", communityID),
+			zap.Error(err))
+		return nil, err
+	}
+	return buildCategoryTree(categories), nil
+}
+
+// buildCategoryTree раскладывает плоский список по родителям с сохранением порядка
+func buildCategoryTree(categories []*forummodels.Category) []*forummodels.Category {
+	byID := make(map[string]*forummodels.Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	roots := []*forummodels.Category{}
+	for _, c := range categories {
+		if parent, ok := byID[c.ParentID]; ok {
+			parent.Children = append(parent.Children, c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+	sortCategories(roots)
+	return roots
+}
+
+func sortCategories(categories []*forummodels.Category) {
+	sort.SliceStable(categories, func(i, j int) bool {
+		if categories[i].SortOrder != categories[j].SortOrder {
+			return categories[i].SortOrder < categories[j].SortOrder
+		}
+		return categories[i].Name < categories[j].Name
+	})
+	for _, c := range categories {
+		sortCategories(c.Children)
+	}
+}
+
+// applyCategoryRequest переносит поля запроса в раздел и проверяет родителя
+func (s *forumServiceImpl) applyCategoryRequest(communityID string, category *forummodels.Category, req forummodels.CategoryRequest) error {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !categorySlugPattern.MatchString(slug) {
+		return ErrInvalidCategory
+	}
+
+	if req.ParentID != "" {
+		categories, err := s.repo.GetCategories(communityID)
+		if err != nil {
+			return err
+		}
+		if createsCycle(categories, category.ID, req.ParentID) {
+			return ErrInvalidCategory
+		}
+	}
+
+	category.ParentID = req.ParentID
+	category.Name = strings.TrimSpace(req.Name)
+	category.Slug = slug
+	category.Description = req.Description
+	category.SortOrder = req.SortOrder
+	category.PostRole = req.PostRole
+	if category.PostRole == "" {
+		category.PostRole = "user"
+	}
+	return nil
+}
+
+// createsCycle проверяет, что parentID существует и не является самим разделом или его потомком
+func createsCycle(categories []*forummodels.Category, categoryID, parentID string) bool {
+	parents := make(map[string]string, len(categories))
+	for _, c := range categories {
+		parents[c.ID] = c.ParentID
+	}
+	if _, ok := parents[parentID]; !ok {
+		return true
+	}
+	for id := parentID; id != ""; id = parents[id] {
+		if id == categoryID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *forumServiceImpl) getCategory(communityID, categoryID string) (*forummodels.Category, error) {
+	category, err := s.repo.GetCategory(communityID, categoryID)
+	if err != nil {
+		return nil, categoryError(err)
+	}
+	return category, nil
+}
+
+// requireCommunityAdmin проверяет, что пользователь администратор сообщества
+func (s *forumServiceImpl) requireCommunityAdmin(communityID, userID string) error {
+	role, err := s.memberRole(communityID, userID)
+	if err != nil {
+		return err
+	}
+	if role != "admin" {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// categoryError переводит ошибки репозитория в ошибки сервиса
+func categoryError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrCategoryNotFound):
+		return ErrNotFound
+	case errors.Is(err, repository.ErrCategoryNotEmpty):
+		return ErrCategoryNotEmpty
+	case errors.Is(err, repository.ErrAlreadyExists):
+		return ErrCategoryExists
+	}
+	return err
+}
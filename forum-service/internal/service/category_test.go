@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCategoryTree(t *testing.T) {
+	tree := buildCategoryTree([]*forummodels.Category{
+		{ID: "go", Name: "Go", SortOrder: 2},
+		{ID: "grpc", Name: "gRPC", ParentID: "go"},
+		{ID: "news", Name: "News", SortOrder: 1},
+		{ID: "generics", Name: "Generics", ParentID: "go"},
+	})
+
+	assert.Len(t, tree, 2)
+	assert.Equal(t, "news", tree[0].ID)
+	assert.Equal(t, "go", tree[1].ID)
+	assert.Equal(t, "generics", tree[1].Children[0].ID)
+	assert.Equal(t, "grpc", tree[1].Children[1].ID)
+}
+
+func TestCreatesCycle(t *testing.T) {
+	categories := []*forummodels.Category{
+		{ID: "a"},
+		{ID: "b", ParentID: "a"},
+		{ID: "c", ParentID: "b"},
+	}
+
+	assert.False(t, createsCycle(categories, "", "c"))
+	assert.False(t, createsCycle(categories, "c", "a"))
+	assert.True(t, createsCycle(categories, "a", "c"))
+	assert.True(t, createsCycle(categories, "a", "a"))
+	assert.True(t, createsCycle(categories, "", "missing"))
+}
+
+func TestRoleAtLeast(t *testing.T) {
+	assert.True(t, roleAtLeast("admin", "moderator"))
+	assert.True(t, roleAtLeast("user", "user"))
+	assert.False(t, roleAtLeast("user", "admin"))
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/repository"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// CreateConversation начинает личную переписку с участниками сообщества.
+// Переписка двух пользователей у них одна: повторный запрос вернет существующую.
+func (s *forumServiceImpl) CreateConversation(communityID, userID string, req forummodels.ConversationRequest) (*forummodels.Conversation, error) {
+	members, err := conversationMembers(userID, req.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireMember(communityID, userID); err != nil {
+		return nil, err
+	}
+	for _, memberID := range members[1:] {
+		role, _, err := s.authClient.GetMemberRole(communityID, memberID)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			return nil, ErrInvalidConversation
+		}
+	}
+	if err := s.requireNotBlocked(userID, members); err != nil {
+		return nil, err
+	}
+
+	conversation := &forummodels.Conversation{
+		ID:        generateID(),
+		Members:   members,
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+	}
+	created, err := s.repo.CreateConversation(communityID, conversation, directKey(members))
+	if err != nil {
+		logger.Log.Error("Failed to create conversation",
+			zap.String("community_id", communityID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetConversations возвращает переписки пользователя с числом непрочитанных сообщений
+func (s *forumServiceImpl) GetConversations(communityID, userID string) ([]*forummodels.Conversation, error) {
+	conversations, err := s.repo.GetConversations(communityID, userID)
+	if err != nil {
+		logger.Log.Error("Failed to get conversations",
+			zap.String("community_id", communityID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+	return conversations, nil
+}
+
+// GetConversation возвращает переписку, если пользователь в ней участвует.
+// Для остальных переписка не существует.
+func (s *forumServiceImpl) GetConversation(communityID, conversationID, userID string) (*forummodels.Conversation, error) {
+	conversation, err := s.repo.GetConversation(communityID, conversationID, userID)
+	if errors.Is(err, repository.ErrConversationNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		logger.Log.Error("Failed to get conversation",
+			zap.String("conversation_id", conversationID),
+			zap.Error(err))
+		return nil, err
+	}
+	if !contains(conversation.Members, userID) {
+		return nil, ErrNotFound
+	}
+	return conversation, nil
+}
+
+// GetDirectMessages возвращает страницу истории переписки, новые сообщения первыми
+func (s *forumServiceImpl) GetDirectMessages(communityID, conversationID, userID string, params forummodels.PageParams) (*forummodels.DirectMessagePage, error) {
+	query, err := pageQuery(params, forummodels.SortNewest, forummodels.SortNewest, forummodels.SortOldest)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.GetConversation(communityID, conversationID, userID); err != nil {
+		return nil, err
+	}
+
+	messages, err := s.repo.GetDirectMessages(conversationID, query)
+	if err != nil {
+		logger.Log.Error("Failed to get direct messages",
+			zap.String("conversation_id", conversationID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	for _, message := range messages {
+		message.ContentHTML = s.contentHTML(message.Content, message.ContentHTML)
+	}
+
+	page := &forummodels.DirectMessagePage{Items: messages}
+	if len(messages) == query.Limit {
+		page.Items = messages[:len(messages)-1]
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = encodeCursor(forummodels.PageCursor{
+			Sort:      query.Sort,
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+	}
+	return page, nil
+}
+
+// SendDirectMessage сохраняет сообщение и доставляет его в открытые соединения участников
+func (s *forumServiceImpl) SendDirectMessage(communityID, conversationID, userID string, req forummodels.DirectMessageRequest) (*forummodels.DirectMessage, error) {
+	conversation, err := s.GetConversation(communityID, conversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireMember(communityID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.requireNotBlocked(userID, conversation.Members); err != nil {
+		return nil, err
+	}
+
+	message := &forummodels.DirectMessage{
+		ID:             generateID(),
+		ConversationID: conversationID,
+		UserID:         userID,
+		Content:        req.Content,
+		ContentHTML:    s.markdown.Render(req.Content),
+		CreatedAt:      time.Now(),
+	}
+	if err := s.repo.CreateDirectMessage(message); err != nil {
+		logger.Log.Error("Failed to create direct message",
+			zap.String("conversation_id", conversationID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	event := forummodels.DirectMessageEvent{Type: forummodels.DirectMessageEventType, Message: message}
+	for _, memberID := range conversation.Members {
+		s.broadcastChan <- chatBroadcast{communityID: communityID, userID: memberID, message: event}
+	}
+	return message, nil
+}
+
+// MarkConversationRead отмечает переписку прочитанной
+func (s *forumServiceImpl) MarkConversationRead(communityID, conversationID, userID string) (*forummodels.Conversation, error) {
+	if _, err := s.GetConversation(communityID, conversationID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.MarkConversationRead(conversationID, userID, time.Now()); err != nil {
+		logger.Log.Error("Failed to mark conversation read",
+			zap.String("conversation_id", conversationID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+	return s.GetConversation(communityID, conversationID, userID)
+}
+
+// BlockUser запрещает пользователю blockedID начинать переписку и писать userID
+func (s *forumServiceImpl) BlockUser(userID, blockedID string) error {
+	if userID == blockedID {
+		return ErrSelfBlock
+	}
+	if err := s.repo.BlockUser(userID, blockedID, time.Now()); err != nil {
+		logger.Log.Error("Failed to block user",
+			zap.String("user_id", userID),
+			zap.String("blocked_id", blockedID),
+			zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// UnblockUser снимает блокировку
+func (s *forumServiceImpl) UnblockUser(userID, blockedID string) error {
+	if err := s.repo.UnblockUser(userID, blockedID); err != nil {
+		logger.Log.Error("Failed to unblock user",
+			zap.String("user_id", userID),
+			zap.String("blocked_id", blockedID),
+			zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetBlockedUsers возвращает пользователей, которых заблокировал userID
+func (s *forumServiceImpl) GetBlockedUsers(userID string) ([]string, error) {
+	blocked, err := s.repo.GetBlockedUsers(userID)
+	if err != nil {
+		logger.Log.Error("Failed to get blocked users",
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+	return blocked, nil
+}
+
+// requireNotBlocked проверяет, что никто из участников не заблокировал отправителя
+func (s *forumServiceImpl) requireNotBlocked(senderID string, members []string) error {
+	blockers, err := s.repo.BlockedBy(senderID, members)
+	if err != nil {
+		logger.Log.Error("Failed to check user blocks",
+			zap.String("user_id", senderID),
+			zap.Error(err))
+		return err
+	}
+	if len(blockers) > 0 {
+		return ErrBlockedByUser
+	}
+	return nil
+}
+
+// conversationMembers собирает участников переписки: создатель первым, без повторов
+func conversationMembers(creatorID string, userIDs []string) ([]string, error) {
+	members := []string{creatorID}
+	for _, userID := range userIDs {
+		if userID == "" || contains(members, userID) {
+			continue
+		}
+		members = append(members, userID)
+	}
+	if len(members) < 2 || len(members) > forummodels.MaxConversationMembers {
+		return nil, ErrInvalidConversation
+	}
+	return members, nil
+}
+
+// directKey ключ переписки двух пользователей, не зависящий от того, кто ее начал.
+// У групповых переписок ключа нет.
+func directKey(members []string) string {
+	if len(members) != 2 {
+		return ""
+	}
+	pair := []string{members[0], members[1]}
+	sort.Strings(pair)
+	return strings.Join(pair, ":")
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversationMembers(t *testing.T) {
+	members, err := conversationMembers("me", []string{"bob", "me", "bob", "", "alice"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"me", "bob", "alice"}, members)
+
+	_, err = conversationMembers("me", []string{"me"})
+	assert.ErrorIs(t, err, ErrInvalidConversation)
+
+	many := []string{"u1", "u2", "u3", "u4", "u5", "u6", "u7", "u8", "u9", "u10"}
+	_, err = conversationMembers("me", many)
+	assert.ErrorIs(t, err, ErrInvalidConversation)
+}
+
+func TestDirectKey(t *testing.T) {
+	assert.Equal(t, directKey([]string{"b", "a"}), directKey([]string{"a", "b"}))
+	assert.Equal(t, "a:b", directKey([]string{"b", "a"}))
+	assert.Empty(t, directKey([]string{"a", "b", "c"}))
+}
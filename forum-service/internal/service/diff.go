@@ -0,0 +1,95 @@
+package service
+
+import (
+	"strings"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// maxDiffLines предел числа измененных строк с каждой стороны. Таблица LCS занимает
+// квадрат от этого числа, поэтому большие правки не сравниваются
+const maxDiffLines = 1000
+
+// DiffLines строит построчный дифф двух текстов по наибольшей общей подпоследовательности.
+// Общие начало и конец текстов в таблицу не попадают; если измененная часть длиннее
+// maxDiffLines строк, возвращается ErrDiffTooLarge
+func DiffLines(oldText, newText string) ([]forummodels.DiffLine, error) {
+	a := splitLines(oldText)
+	b := splitLines(newText)
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	diff := make([]forummodels.DiffLine, 0, max(len(a), len(b)))
+	for _, line := range a[:prefix] {
+		diff = append(diff, forummodels.DiffLine{Op: forummodels.DiffEqual, Text: line})
+	}
+	middle, err := diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	if err != nil {
+		return nil, err
+	}
+	diff = append(diff, middle...)
+	for _, line := range a[len(a)-suffix:] {
+		diff = append(diff, forummodels.DiffLine{Op: forummodels.DiffEqual, Text: line})
+	}
+	return diff, nil
+}
+
+// diffMiddle сравнивает измененную часть текстов через таблицу LCS
+func diffMiddle(a, b []string) ([]forummodels.DiffLine, error) {
+	if len(a) > maxDiffLines || len(b) > maxDiffLines {
+		return nil, ErrDiffTooLarge
+	}
+
+	// lcs[i][j] длина общей подпоследовательности для a[i:] и b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := make([]forummodels.DiffLine, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, forummodels.DiffLine{Op: forummodels.DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, forummodels.DiffLine{Op: forummodels.DiffDelete, Text: a[i]})
+			i++
+		default:
+			diff = append(diff, forummodels.DiffLine{Op: forummodels.DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, forummodels.DiffLine{Op: forummodels.DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, forummodels.DiffLine{Op: forummodels.DiffInsert, Text: b[j]})
+	}
+	return diff, nil
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
@@ -0,0 +1,66 @@
+package service_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffLines(t *testing.T) {
+	diff, err := service.DiffLines("first\nsecond\nthird", "first\nchanged\nthird\nfourth")
+
+	require.NoError(t, err)
+	assert.Equal(t, []forummodels.DiffLine{
+		{Op: forummodels.DiffEqual, Text: "first"},
+		{Op: forummodels.DiffDelete, Text: "second"},
+		{Op: forummodels.DiffInsert, Text: "changed"},
+		{Op: forummodels.DiffEqual, Text: "third"},
+		{Op: forummodels.DiffInsert, Text: "fourth"},
+	}, diff)
+}
+
+func TestDiffLines_Empty(t *testing.T) {
+	diff, err := service.DiffLines("", "new")
+	require.NoError(t, err)
+	assert.Equal(t, []forummodels.DiffLine{
+		{Op: forummodels.DiffInsert, Text: "new"},
+	}, diff)
+
+	diff, err = service.DiffLines("", "")
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestDiffLines_TooLarge(t *testing.T) {
+	oldLines := make([]string, 5000)
+	newLines := make([]string, 5000)
+	for i := range oldLines {
+		oldLines[i] = "old " + strconv.Itoa(i)
+		newLines[i] = "new " + strconv.Itoa(i)
+	}
+
+	_, err := service.DiffLines(strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"))
+
+	assert.ErrorIs(t, err, service.ErrDiffTooLarge)
+}
+
+func TestDiffLines_LargeTextSmallEdit(t *testing.T) {
+	lines := make([]string, 5000)
+	for i := range lines {
+		lines[i] = "line " + strconv.Itoa(i)
+	}
+	oldText := strings.Join(lines, "\n")
+	lines[2500] = "edited"
+
+	diff, err := service.DiffLines(oldText, strings.Join(lines, "\n"))
+
+	require.NoError(t, err)
+	assert.Len(t, diff, 5001)
+	assert.Equal(t, forummodels.DiffLine{Op: forummodels.DiffDelete, Text: "line 2500"}, diff[2500])
+	assert.Equal(t, forummodels.DiffLine{Op: forummodels.DiffInsert, Text: "edited"}, diff[2501])
+}
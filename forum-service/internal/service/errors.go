@@ -1,9 +1,62 @@
 package service
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/luckermt/forum-app/forum-service/internal/repository"
+)
 
 var (
-	ErrNotFound     = errors.New("not found")
-	ErrForbidden    = errors.New("forbidden")
-	ErrInvalidToken = errors.New("invalid token")
+	ErrNotFound          = errors.New("not found")
+	ErrForbidden         = errors.New("forbidden")
+	ErrInvalidToken      = errors.New("invalid token")
+	ErrInsufficientScope = errors.New("token does not grant the required scope")
+	ErrNotMember         = errors.New("user is not a member of the community")
+	ErrUserBlocked       = errors.New("user is blocked in the community")
+
+	ErrTopicLocked    = errors.New("topic is locked")
+	ErrTopicArchived  = errors.New("topic is archived")
+	ErrDiffTooLarge   = errors.New("revisions differ in too many lines to compare")
+	ErrContentTooLong = errors.New("content must be at most 10000 characters long")
+
+	ErrInvalidParent = errors.New("parent message not found in this topic")
+	ErrThreadTooDeep = errors.New("maximum reply depth reached")
+	ErrInvalidThread = errors.New("invalid thread depth or format")
+
+	ErrEditWindowClosed = errors.New("edit window has closed")
+	ErrInvalidReaction  = errors.New("unknown reaction")
+
+	ErrInvalidVote = errors.New("vote must be -1, 0 or 1")
+	ErrSelfVote    = errors.New("cannot vote for own content")
+
+	ErrInvalidWatch = errors.New("unknown watch level")
+
+	ErrInvalidPoll     = errors.New("poll needs a question and 2 to 10 distinct options")
+	ErrInvalidPollVote = errors.New("invalid poll options")
+	ErrPollClosed      = errors.New("poll is closed")
+	ErrAlreadyVoted    = errors.New("you have already voted in this poll")
+
+	ErrInvalidConversation = errors.New("conversation needs 1 to 9 other community members")
+	ErrBlockedByUser       = errors.New("user does not accept messages from you")
+	ErrSelfBlock           = errors.New("cannot block yourself")
+
+	ErrAttachmentsDisabled = errors.New("attachment storage is not configured")
+	ErrAttachmentTooLarge  = errors.New("attachment is too large")
+	ErrAttachmentType      = errors.New("attachment type is not allowed")
+	ErrInvalidAttachment   = errors.New("attachment is empty or not found")
+	ErrImageProcessing     = errors.New("image is still being processed")
+	ErrImageRejected       = errors.New("image could not be processed")
+
+	ErrInvalidCursor = errors.New("invalid page cursor")
+	ErrInvalidSort   = errors.New("invalid sort mode")
+	ErrInvalidSearch = errors.New("invalid search request")
+
+	ErrInvalidCategory  = errors.New("invalid category slug or parent")
+	ErrCategoryExists   = errors.New("category with this slug already exists")
+	ErrCategoryNotEmpty = repository.ErrCategoryNotEmpty
+
+	ErrInvalidTag         = errors.New("invalid tag")
+	ErrTooManyTags        = errors.New("too many tags")
+	ErrTagNotAllowed      = errors.New("tag is not in the allowed list")
+	ErrInvalidTagSettings = errors.New("max_tags must be between 1 and 10")
 )
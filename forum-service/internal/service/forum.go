@@ -1,114 +1,352 @@
 package service
 
 import (
+	"errors"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/luckermt/forum-app/forum-service/internal/imaging"
+	"github.com/luckermt/forum-app/forum-service/internal/markdown"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/repository"
+	"github.com/luckermt/forum-app/forum-service/internal/storage"
 	"github.com/luckermt/forum-app/shared/pkg/logger"
 	"github.com/luckermt/forum-app/shared/pkg/models"
 	"go.uber.org/zap"
 )
 
+// DefaultCommunityID сообщество, к которому относятся запросы без хоста или префикса /c/{slug}
+const DefaultCommunityID = "default"
+
+// communityCacheTTL время жизни закэшированного соответствия хоста/slug и сообщества
+const communityCacheTTL = 5 * time.Minute
+
+// maxCommunityCacheEntries ограничивает кэш сообществ: ключ берется из заголовка Host
+const maxCommunityCacheEntries = 1024
+
+// DefaultEditWindow сколько времени автор может править и удалять свое сообщение
+const DefaultEditWindow = 15 * time.Minute
+
+// chatBroadcast сообщение вместе с сообществом, в котором его нужно разослать.
+// Сообщения с topicID получают только слушатели этой темы,
+// сообщения с userID — только соединения этого пользователя.
+type chatBroadcast struct {
+	communityID string
+	topicID     string
+	userID      string
+	message     interface{}
+}
+
+type communityCacheEntry struct {
+	communityID string
+	expiresAt   time.Time
+}
+
 type forumServiceImpl struct {
 	repo          Repository
 	authClient    AuthClient
-	chatClients   map[string]*websocket.Conn
+	chatClients   map[string]map[*websocket.Conn]string
+	topicClients  map[string]map[*websocket.Conn]string
 	clientsMutex  sync.Mutex
-	broadcastChan chan models.Message
+	broadcastChan chan chatBroadcast
+
+	communityCache map[string]communityCacheEntry
+	cacheMutex     sync.Mutex
+
+	editWindow time.Duration
+	markdown   *markdown.Renderer
+
+	blobs            storage.BlobStore
+	attachmentLimits AttachmentLimits
+	imageLimits      imaging.Limits
 }
 
 func NewForumService(repo Repository, authClient AuthClient) *forumServiceImpl {
 	service := &forumServiceImpl{
-		repo:          repo,
-		authClient:    authClient,
-		chatClients:   make(map[string]*websocket.Conn),
-		broadcastChan: make(chan models.Message, 100),
+		repo:           repo,
+		authClient:     authClient,
+		chatClients:    make(map[string]map[*websocket.Conn]string),
+		topicClients:   make(map[string]map[*websocket.Conn]string),
+		broadcastChan:  make(chan chatBroadcast, 100),
+		communityCache: make(map[string]communityCacheEntry),
+		editWindow:     DefaultEditWindow,
+		markdown:       markdown.NewRenderer(markdown.DefaultOptions),
+
+		attachmentLimits: DefaultAttachmentLimits,
+		imageLimits:      imaging.DefaultLimits,
 	}
 	go service.startMessageBroadcaster()
 	return service
 }
 
 // Topic methods
-func (s *forumServiceImpl) CreateTopic(userID, title, content string) (*models.Topic, error) {
-	topic := &models.Topic{
-		ID:        generateID(),
-		Title:     title,
-		Content:   content,
-		UserID:    userID,
-		CreatedAt: time.Now(),
+func (s *forumServiceImpl) CreateTopic(communityID, userID string, req forummodels.CreateTopicRequest) (*forummodels.TopicDetail, error) {
+	if err := checkContentLength(req.Content); err != nil {
+		return nil, err
+	}
+	role, err := s.memberRole(communityID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.resolveTopicTags(communityID, req.Tags)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.repo.CreateTopic(topic); err != nil {
+	attachments, err := s.resolveAttachments(communityID, userID, req.AttachmentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var poll *forummodels.Poll
+	if req.Poll != nil {
+		if poll, err = newPoll(req.Poll, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.CategoryID != "" {
+		category, err := s.getCategory(communityID, req.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		if !roleAtLeast(role, category.PostRole) {
+			return nil, ErrForbidden
+		}
+	}
+
+	topic := &forummodels.TopicDetail{
+		Topic: models.Topic{
+			ID:        generateID(),
+			Title:     req.Title,
+			Content:   req.Content,
+			UserID:    userID,
+			CreatedAt: time.Now(),
+		},
+		ContentHTML: s.markdown.Render(req.Content),
+		CategoryID:  req.CategoryID,
+		Tags:        tags,
+		Revision:    1,
+		Attachments: attachments,
+		Poll:        poll,
+	}
+	if poll != nil {
+		poll.TopicID = topic.ID
+	}
+
+	if err := s.repo.CreateTopic(communityID, topic); err != nil {
 		logger.Log.Error("Failed to create topic",
+			zap.String("community_id", communityID),
 			zap.String("user_id", userID),
 			zap.Error(err))
 		return nil, err
 	}
+	if poll != nil {
+		applyPollVisibility(poll, time.Now())
+	}
+	refs := s.syncReferences(communityID, forummodels.ReferenceTopic, topic.ID, topic.ID, topic.Content, true)
+	topic.Entities = referenceEntities(topic.Content, refs)
 
+	// Автор следит за своей темой
+	if err := s.repo.AddWatch(communityID, userID, forummodels.WatchTargetTopic, topic.ID, forummodels.WatchWatching, time.Now()); err != nil {
+		logger.Log.Error("Failed to watch created topic",
+			zap.String("topic_id", topic.ID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+	}
 	return topic, nil
 }
 
-func (s *forumServiceImpl) GetTopics() ([]*models.Topic, error) {
-	topics, err := s.repo.GetTopics()
+func (s *forumServiceImpl) GetTopics(communityID string, filter forummodels.TopicFilter, params forummodels.PageParams) (*forummodels.TopicPage, error) {
+	query, err := pageQuery(params, forummodels.SortNewest,
+		forummodels.SortNewest, forummodels.SortOldest, forummodels.SortActive, forummodels.SortTop)
 	if err != nil {
-		logger.Log.Error("Failed to get topics", zap.Error(err))
 		return nil, err
 	}
-	return topics, nil
+
+	if filter.CategoryID != "" {
+		if _, err := s.getCategory(communityID, filter.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(filter.Tags) > 0 {
+		tags, err := normalizeTags(filter.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if filter.Tags, err = s.canonicalTags(communityID, tags, false); err != nil {
+			return nil, err
+		}
+	}
+
+	topics, err := s.repo.GetTopics(communityID, filter, query)
+	if err != nil {
+		logger.Log.Error("Failed to get topics",
+			zap.String("community_id", communityID),
+			zap.Error(err))
+		return nil, err
+	}
+	for _, topic := range topics {
+		topic.ContentHTML = s.contentHTML(topic.Content, topic.ContentHTML)
+	}
+
+	page := &forummodels.TopicPage{Items: topics}
+	if len(topics) == query.Limit {
+		page.Items = topics[:len(topics)-1]
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = encodeCursor(forummodels.PageCursor{
+			Sort:         query.Sort,
+			CreatedAt:    last.CreatedAt,
+			ID:           last.ID,
+			MessageCount: last.MessageCount,
+			Pinned:       last.Pinned,
+			Score:        last.Score,
+		})
+	}
+	return page, nil
 }
 
-func (s *forumServiceImpl) DeleteTopic(topicID, userID string) error {
-	isAdmin, err := s.authClient.IsUserAdmin(userID)
+func (s *forumServiceImpl) DeleteTopic(communityID, topicID, userID string) error {
+	// GetMemberRole возвращает admin и для глобальных администраторов
+	role, _, err := s.authClient.GetMemberRole(communityID, userID)
 	if err != nil {
-		logger.Log.Error("Failed to check admin status",
+		logger.Log.Error("Failed to check community role",
+			zap.String("community_id", communityID),
 			zap.String("user_id", userID),
 			zap.Error(err))
 		return err
 	}
 
-	if !isAdmin {
+	if role != "admin" {
 		return ErrForbidden
 	}
 
-	return s.repo.DeleteTopic(topicID)
+	topic, err := s.GetTopic(communityID, topicID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteTopic(communityID, topicID); err != nil {
+		if errors.Is(err, repository.ErrTopicNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	s.notifyModeration(communityID, userID, topic.UserID, forummodels.ModerationTopicDeleted, topicID, "")
+	return nil
 }
 
 // Message methods
-func (s *forumServiceImpl) CreateMessage(message *models.Message) error {
-	if err := s.repo.CreateMessage(message); err != nil {
+func (s *forumServiceImpl) CreateMessage(communityID string, message *models.Message) error {
+	if message.TopicID != "" {
+		if err := s.requireWritableTopic(communityID, message.TopicID); err != nil {
+			return err
+		}
+	}
+
+	detail := &forummodels.MessageDetail{Message: *message, ContentHTML: s.markdown.Render(message.Content)}
+	if err := s.repo.CreateMessage(communityID, message, detail.ContentHTML); err != nil {
 		logger.Log.Error("Failed to create message",
+			zap.String("community_id", communityID),
 			zap.String("user_id", message.UserID),
 			zap.Error(err))
 		return err
 	}
 
-	if message.IsChat {
-		s.broadcastChan <- *message
+	mentions := s.syncMentions(communityID, detail, true)
+	s.syncMessageReferences(communityID, detail, true)
+	if !message.IsChat && message.TopicID != "" {
+		s.notifyReply(communityID, detail, "", mentions)
+	} else {
+		s.notify(communityID, mentions)
 	}
 
+	s.broadcastMessageEvent(communityID, forummodels.MessageEventCreated, detail)
 	return nil
 }
 
-func (s *forumServiceImpl) GetTopicMessages(topicID string) ([]*models.Message, error) {
-	messages, err := s.repo.GetMessagesByTopic(topicID)
+func (s *forumServiceImpl) GetTopicMessages(communityID, topicID, viewerID string, params forummodels.PageParams) (*forummodels.MessagePage, error) {
+	query, err := pageQuery(params, forummodels.SortOldest, forummodels.SortOldest, forummodels.SortNewest, forummodels.SortTop)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.repo.GetMessagesByTopic(communityID, topicID, query)
 	if err != nil {
 		logger.Log.Error("Failed to get topic messages",
+			zap.String("community_id", communityID),
 			zap.String("topic_id", topicID),
 			zap.Error(err))
 		return nil, err
 	}
-	return messages, nil
+
+	page := messagePage(messages, query)
+	s.fillMessageHTML(page.Items)
+	if err := s.attachReactions(communityID, viewerID, page.Items); err != nil {
+		return nil, err
+	}
+	if err := s.attachMentions(communityID, page.Items); err != nil {
+		return nil, err
+	}
+	if err := s.attachReferences(communityID, page.Items); err != nil {
+		return nil, err
+	}
+	if err := s.attachMessageAttachments(communityID, page.Items); err != nil {
+		return nil, err
+	}
+	return page, nil
 }
 
-func (s *forumServiceImpl) GetChatMessages() ([]*models.Message, error) {
-	messages, err := s.repo.GetChatMessages()
+func (s *forumServiceImpl) GetChatMessages(communityID, viewerID string, params forummodels.PageParams) (*forummodels.MessagePage, error) {
+	query, err := pageQuery(params, forummodels.SortOldest, forummodels.SortOldest, forummodels.SortNewest)
 	if err != nil {
-		logger.Log.Error("Failed to get chat messages", zap.Error(err))
 		return nil, err
 	}
-	return messages, nil
+
+	messages, err := s.repo.GetChatMessages(communityID, query)
+	if err != nil {
+		logger.Log.Error("Failed to get chat messages",
+			zap.String("community_id", communityID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	page := messagePage(messages, query)
+	s.fillMessageHTML(page.Items)
+	if err := s.attachReactions(communityID, viewerID, page.Items); err != nil {
+		return nil, err
+	}
+	if err := s.attachMentions(communityID, page.Items); err != nil {
+		return nil, err
+	}
+	if err := s.attachReferences(communityID, page.Items); err != nil {
+		return nil, err
+	}
+	if err := s.attachMessageAttachments(communityID, page.Items); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// messagePage отрезает лишнее сообщение и формирует курсор следующей страницы
+func messagePage(messages []*forummodels.MessageDetail, query forummodels.PageQuery) *forummodels.MessagePage {
+	page := &forummodels.MessagePage{Items: messages}
+	if len(messages) == query.Limit {
+		page.Items = messages[:len(messages)-1]
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = encodeCursor(forummodels.PageCursor{
+			Sort:      query.Sort,
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+			Score:     last.Score,
+		})
+	}
+	return page
 }
 
 func (s *forumServiceImpl) DeleteMessagesOlderThan(maxAge time.Duration) error {
@@ -122,23 +360,69 @@ func (s *forumServiceImpl) DeleteMessagesOlderThan(maxAge time.Duration) error {
 }
 
 // Chat methods
-func (s *forumServiceImpl) RegisterClient(userID string, conn *websocket.Conn) {
+func (s *forumServiceImpl) RegisterClient(communityID, userID string, conn *websocket.Conn) {
 	s.clientsMutex.Lock()
 	defer s.clientsMutex.Unlock()
-	s.chatClients[userID] = conn
+	clients, ok := s.chatClients[communityID]
+	if !ok {
+		clients = make(map[*websocket.Conn]string)
+		s.chatClients[communityID] = clients
+	}
+	// У пользователя может быть несколько соединений, например в разных вкладках
+	clients[conn] = userID
 	logger.Log.Info("New chat client registered",
+		zap.String("community_id", communityID),
 		zap.String("user_id", userID))
 }
 
-func (s *forumServiceImpl) UnregisterClient(userID string) {
+// UnregisterClient удаляет только закрытое соединение; остальные соединения
+// пользователя продолжают получать сообщения
+func (s *forumServiceImpl) UnregisterClient(communityID string, conn *websocket.Conn) {
 	s.clientsMutex.Lock()
 	defer s.clientsMutex.Unlock()
-	delete(s.chatClients, userID)
+	userID := s.chatClients[communityID][conn]
+	s.removeClient(communityID, conn)
 	logger.Log.Info("Chat client unregistered",
+		zap.String("community_id", communityID),
 		zap.String("user_id", userID))
 }
 
-func (s *forumServiceImpl) HandleChatMessage(userID, text string) error {
+// RegisterTopicListener подписывает соединение на новые ответы в теме
+func (s *forumServiceImpl) RegisterTopicListener(communityID, topicID, userID string, conn *websocket.Conn) error {
+	if _, err := s.GetTopic(communityID, topicID); err != nil {
+		return err
+	}
+
+	key := topicListenerKey(communityID, topicID)
+	s.clientsMutex.Lock()
+	defer s.clientsMutex.Unlock()
+	listeners, ok := s.topicClients[key]
+	if !ok {
+		listeners = make(map[*websocket.Conn]string)
+		s.topicClients[key] = listeners
+	}
+	listeners[conn] = userID
+	logger.Log.Info("New topic listener registered",
+		zap.String("community_id", communityID),
+		zap.String("topic_id", topicID),
+		zap.String("user_id", userID))
+	return nil
+}
+
+func (s *forumServiceImpl) UnregisterTopicListener(communityID, topicID string, conn *websocket.Conn) {
+	s.clientsMutex.Lock()
+	defer s.clientsMutex.Unlock()
+	s.removeTopicListener(topicListenerKey(communityID, topicID), conn)
+}
+
+func (s *forumServiceImpl) HandleChatMessage(communityID, userID, text string) error {
+	if err := checkContentLength(text); err != nil {
+		return err
+	}
+	if err := s.requireMember(communityID, userID); err != nil {
+		return err
+	}
+
 	msg := models.Message{
 		ID:        generateID(),
 		UserID:    userID,
@@ -147,7 +431,7 @@ func (s *forumServiceImpl) HandleChatMessage(userID, text string) error {
 		IsChat:    true,
 	}
 
-	return s.CreateMessage(&msg)
+	return s.CreateMessage(communityID, &msg)
 }
 
 func (s *forumServiceImpl) CleanOldMessages(maxAge time.Duration) {
@@ -162,30 +446,220 @@ func (s *forumServiceImpl) CleanOldMessages(maxAge time.Duration) {
 }
 
 // Auth methods
-func (s *forumServiceImpl) ValidateUser(token string) (string, error) {
-	return s.authClient.ValidateToken(token)
+
+// ValidateUser проверяет токен и возвращает ID пользователя. Токен стороннего
+// приложения должен включать область доступа scope, иначе возвращается ErrInsufficientScope
+func (s *forumServiceImpl) ValidateUser(token, scope string) (string, error) {
+	info, err := s.authClient.ValidateToken(token)
+	if err != nil {
+		return "", err
+	}
+	if !info.HasScope(scope) {
+		return "", ErrInsufficientScope
+	}
+	return info.UserID, nil
 }
 
 func (s *forumServiceImpl) IsUserAdmin(userID string) (bool, error) {
 	return s.authClient.IsUserAdmin(userID)
 }
 
+// Community methods
+
+// ResolveCommunity определяет ID сообщества по slug из пути или по хосту.
+// Результаты кэшируются, чтобы не ходить в auth-service на каждый запрос.
+func (s *forumServiceImpl) ResolveCommunity(host, slug string) (string, error) {
+	if host == "" && slug == "" {
+		return DefaultCommunityID, nil
+	}
+
+	// Slug однозначно задает сообщество, поэтому хост в ключ не входит
+	key := "host:" + strings.ToLower(host)
+	if slug != "" {
+		key = "slug:" + strings.ToLower(slug)
+	}
+	s.cacheMutex.Lock()
+	entry, ok := s.communityCache[key]
+	s.cacheMutex.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.communityID, nil
+	}
+
+	communityID, err := s.authClient.ResolveCommunity(host, slug)
+	if err != nil {
+		return "", err
+	}
+	// Неизвестные хосты получают сообщество по умолчанию — их не кэшируем,
+	// иначе произвольные заголовки Host заполнят кэш
+	if slug == "" && communityID == DefaultCommunityID {
+		return communityID, nil
+	}
+
+	s.cacheMutex.Lock()
+	s.cacheCommunity(key, communityID)
+	s.cacheMutex.Unlock()
+	return communityID, nil
+}
+
+// cacheCommunity сохраняет запись, вытесняя устаревшие, а при переполнении
+// и любую другую. Вызывается под cacheMutex.
+func (s *forumServiceImpl) cacheCommunity(key, communityID string) {
+	if _, ok := s.communityCache[key]; !ok && len(s.communityCache) >= maxCommunityCacheEntries {
+		now := time.Now()
+		for k, entry := range s.communityCache {
+			if !now.Before(entry.expiresAt) {
+				delete(s.communityCache, k)
+			}
+		}
+		for k := range s.communityCache {
+			if len(s.communityCache) < maxCommunityCacheEntries {
+				break
+			}
+			delete(s.communityCache, k)
+		}
+	}
+	s.communityCache[key] = communityCacheEntry{
+		communityID: communityID,
+		expiresAt:   time.Now().Add(communityCacheTTL),
+	}
+}
+
+// requireMember проверяет, что пользователь может писать в сообществе
+func (s *forumServiceImpl) requireMember(communityID, userID string) error {
+	_, err := s.memberRole(communityID, userID)
+	return err
+}
+
+// memberRole возвращает роль участника, который может писать в сообществе
+func (s *forumServiceImpl) memberRole(communityID, userID string) (string, error) {
+	role, blocked, err := s.authClient.GetMemberRole(communityID, userID)
+	if err != nil {
+		logger.Log.Error("Failed to check community membership",
+			zap.String("community_id", communityID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return "", err
+	}
+	if blocked {
+		return "", ErrUserBlocked
+	}
+	if role == "" {
+		return "", ErrNotMember
+	}
+	return role, nil
+}
+
+// roleRanks упорядочивает роли сообщества по правам
+var roleRanks = map[string]int{
+	"user":      1,
+	"moderator": 2,
+	"admin":     3,
+}
+
+// roleAtLeast сообщает, что роль role не ниже роли required
+func roleAtLeast(role, required string) bool {
+	return roleRanks[role] >= roleRanks[required]
+}
+
 // Internal methods
 func (s *forumServiceImpl) startMessageBroadcaster() {
-	for msg := range s.broadcastChan {
+	for broadcast := range s.broadcastChan {
 		s.clientsMutex.Lock()
-		for userID, conn := range s.chatClients {
-			if err := conn.WriteJSON(msg); err != nil {
+		if broadcast.userID != "" {
+			s.sendToUser(broadcast)
+			s.clientsMutex.Unlock()
+			continue
+		}
+		if broadcast.topicID != "" {
+			s.sendToTopicListeners(broadcast)
+			s.clientsMutex.Unlock()
+			continue
+		}
+		for conn, userID := range s.chatClients[broadcast.communityID] {
+			if err := conn.WriteJSON(broadcast.message); err != nil {
 				logger.Log.Error("Failed to send message",
+					zap.String("community_id", broadcast.communityID),
 					zap.String("user_id", userID),
 					zap.Error(err))
-				s.UnregisterClient(userID)
+				s.removeClient(broadcast.communityID, conn)
 			}
 		}
 		s.clientsMutex.Unlock()
 	}
 }
 
+// removeClient удаляет соединение; вызывается под clientsMutex
+func (s *forumServiceImpl) removeClient(communityID string, conn *websocket.Conn) {
+	clients := s.chatClients[communityID]
+	delete(clients, conn)
+	if len(clients) == 0 {
+		delete(s.chatClients, communityID)
+	}
+}
+
+// sendToTopicListeners рассылает сообщение слушателям темы; вызывается под clientsMutex
+func (s *forumServiceImpl) sendToTopicListeners(broadcast chatBroadcast) {
+	key := topicListenerKey(broadcast.communityID, broadcast.topicID)
+	for conn, userID := range s.topicClients[key] {
+		if err := conn.WriteJSON(broadcast.message); err != nil {
+			logger.Log.Error("Failed to send topic message",
+				zap.String("topic_id", broadcast.topicID),
+				zap.String("user_id", userID),
+				zap.Error(err))
+			s.removeTopicListener(key, conn)
+		}
+	}
+}
+
+// sendToUser отправляет сообщение во все соединения пользователя в сообществе:
+// в чат и в подписки на темы; вызывается под clientsMutex
+func (s *forumServiceImpl) sendToUser(broadcast chatBroadcast) {
+	for conn, userID := range s.chatClients[broadcast.communityID] {
+		if userID != broadcast.userID {
+			continue
+		}
+		if err := conn.WriteJSON(broadcast.message); err != nil {
+			logger.Log.Error("Failed to send user message",
+				zap.String("community_id", broadcast.communityID),
+				zap.String("user_id", userID),
+				zap.Error(err))
+			s.removeClient(broadcast.communityID, conn)
+		}
+	}
+
+	prefix := topicListenerKey(broadcast.communityID, "")
+	for key, listeners := range s.topicClients {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		for conn, userID := range listeners {
+			if userID != broadcast.userID {
+				continue
+			}
+			if err := conn.WriteJSON(broadcast.message); err != nil {
+				logger.Log.Error("Failed to send user message",
+					zap.String("community_id", broadcast.communityID),
+					zap.String("user_id", userID),
+					zap.Error(err))
+				s.removeTopicListener(key, conn)
+			}
+		}
+	}
+}
+
+// removeTopicListener удаляет слушателя темы; вызывается под clientsMutex
+func (s *forumServiceImpl) removeTopicListener(key string, conn *websocket.Conn) {
+	listeners := s.topicClients[key]
+	delete(listeners, conn)
+	if len(listeners) == 0 {
+		delete(s.topicClients, key)
+	}
+}
+
+func topicListenerKey(communityID, topicID string) string {
+	return communityID + "/" + topicID
+}
+
 func generateID() string {
 	return uuid.New().String()
 }
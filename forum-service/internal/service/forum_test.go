@@ -0,0 +1,150 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dialTabs открывает websocket соединения, как если бы пользователь держал
+// открытыми несколько вкладок; полученные сервером сообщения помечаются именем вкладки
+func dialTabs(t *testing.T, received chan<- string, tabs ...string) []*websocket.Conn {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tab := r.URL.Query().Get("tab")
+		for {
+			var message string
+			if err := conn.ReadJSON(&message); err != nil {
+				return
+			}
+			received <- tab + ":" + message
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	conns := make([]*websocket.Conn, 0, len(tabs))
+	for _, tab := range tabs {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"?tab="+tab, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		conns = append(conns, conn)
+	}
+	return conns
+}
+
+func receiveAll(t *testing.T, received <-chan string, n int) []string {
+	var messages []string
+	for range n {
+		select {
+		case message := <-received:
+			messages = append(messages, message)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d of %d messages", len(messages), n)
+		}
+	}
+	return messages
+}
+
+func TestSendToUser_AllConnections(t *testing.T) {
+	require.NoError(t, logger.Init())
+	s := &forumServiceImpl{
+		chatClients:  make(map[string]map[*websocket.Conn]string),
+		topicClients: make(map[string]map[*websocket.Conn]string),
+	}
+	received := make(chan string, 4)
+	conns := dialTabs(t, received, "first", "second")
+	s.RegisterClient("community-1", "user-1", conns[0])
+	s.RegisterClient("community-1", "user-1", conns[1])
+
+	s.sendToUser(chatBroadcast{communityID: "community-1", userID: "user-1", message: "hello"})
+	assert.ElementsMatch(t, []string{"first:hello", "second:hello"}, receiveAll(t, received, 2))
+
+	// Закрытие первой вкладки не должно отключать вторую
+	conns[0].Close()
+	s.UnregisterClient("community-1", conns[0])
+	s.sendToUser(chatBroadcast{communityID: "community-1", userID: "user-1", message: "again"})
+	assert.Equal(t, []string{"second:again"}, receiveAll(t, received, 1))
+	assert.Len(t, s.chatClients["community-1"], 1)
+}
+
+// communityAuthClient отвечает только на ResolveCommunity и считает обращения
+type communityAuthClient struct {
+	AuthClient
+	hosts map[string]string
+	calls int
+}
+
+func (c *communityAuthClient) ResolveCommunity(host, slug string) (string, error) {
+	c.calls++
+	if slug != "" {
+		return "community-" + slug, nil
+	}
+	if id, ok := c.hosts[host]; ok {
+		return id, nil
+	}
+	return DefaultCommunityID, nil
+}
+
+func TestResolveCommunity_Cache(t *testing.T) {
+	auth := &communityAuthClient{hosts: map[string]string{"go.example.com": "community-go"}}
+	svc := NewForumService(nil, auth)
+
+	for range 2 {
+		id, err := svc.ResolveCommunity("go.example.com", "")
+		require.NoError(t, err)
+		assert.Equal(t, "community-go", id)
+	}
+	assert.Equal(t, 1, auth.calls)
+
+	// Slug задает сообщество независимо от хоста
+	_, err := svc.ResolveCommunity("a.example.com", "rust")
+	require.NoError(t, err)
+	_, err = svc.ResolveCommunity("b.example.com", "rust")
+	require.NoError(t, err)
+	assert.Equal(t, 2, auth.calls)
+
+	// Сообщество по умолчанию для неизвестных хостов не кэшируется
+	for i := range maxCommunityCacheEntries + 10 {
+		id, err := svc.ResolveCommunity(fmt.Sprintf("unknown-%d.example.com", i), "")
+		require.NoError(t, err)
+		assert.Equal(t, DefaultCommunityID, id)
+	}
+	assert.Len(t, svc.communityCache, 2)
+}
+
+func TestResolveCommunity_CacheIsBounded(t *testing.T) {
+	hosts := make(map[string]string)
+	for i := range maxCommunityCacheEntries + 10 {
+		host := fmt.Sprintf("c%d.example.com", i)
+		hosts[host] = "community-" + host
+	}
+	svc := NewForumService(nil, &communityAuthClient{hosts: hosts})
+
+	for host := range hosts {
+		_, err := svc.ResolveCommunity(host, "")
+		require.NoError(t, err)
+	}
+	assert.Len(t, svc.communityCache, maxCommunityCacheEntries)
+}
+
+func TestHandleChatMessage_TooLong(t *testing.T) {
+	svc := NewForumService(nil, nil)
+
+	err := svc.HandleChatMessage(DefaultCommunityID, "user-1", strings.Repeat("я", forummodels.MaxContentLength+1))
+
+	assert.ErrorIs(t, err, ErrContentTooLong)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/luckermt/forum-app/forum-service/internal/imaging"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// imageJobLease время, на которое воркер блокирует задачу; после него задачу заберет другой воркер
+	imageJobLease = 5 * time.Minute
+	// maxImageJobAttempts после стольких неудачных попыток картинка считается необработанной
+	maxImageJobAttempts = 5
+)
+
+// SetImageLimits задает ограничения на размеры обрабатываемых картинок
+func (s *forumServiceImpl) SetImageLimits(limits imaging.Limits) {
+	s.imageLimits = limits
+}
+
+// ProcessImages обрабатывает очередь загруженных картинок: очищает их от метаданных,
+// строит уменьшенные копии и записывает размеры. Очередь опрашивается раз в interval
+// и за один проход разбирается до конца.
+func (s *forumServiceImpl) ProcessImages(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		for s.processNextImage() {
+		}
+	}
+}
+
+// processNextImage обрабатывает одну задачу из очереди; возвращает false, если очередь пуста
+func (s *forumServiceImpl) processNextImage() bool {
+	if s.blobs == nil {
+		return false
+	}
+	job, err := s.repo.ClaimImageJob(time.Now(), imageJobLease)
+	if err != nil {
+		logger.Log.Error("Failed to claim image job", zap.Error(err))
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	err = s.processImage(job)
+	switch {
+	case err == nil:
+	case errors.Is(err, imaging.ErrTooLarge), errors.Is(err, imaging.ErrInvalidImage):
+		logger.Log.Warn("Image rejected",
+			zap.String("attachment_id", job.AttachmentID),
+			zap.Error(err))
+		if err := s.repo.FailImageJob(job.AttachmentID, forummodels.AttachmentRejected); err != nil {
+			logger.Log.Error("Failed to reject image", zap.String("attachment_id", job.AttachmentID), zap.Error(err))
+		}
+	case job.Attempts >= maxImageJobAttempts:
+		logger.Log.Error("Image processing failed",
+			zap.String("attachment_id", job.AttachmentID),
+			zap.Int("attempts", job.Attempts),
+			zap.Error(err))
+		if err := s.repo.FailImageJob(job.AttachmentID, forummodels.AttachmentFailed); err != nil {
+			logger.Log.Error("Failed to mark image as failed", zap.String("attachment_id", job.AttachmentID), zap.Error(err))
+		}
+	default:
+		logger.Log.Warn("Image processing will be retried",
+			zap.String("attachment_id", job.AttachmentID),
+			zap.Int("attempts", job.Attempts),
+			zap.Error(err))
+		if err := s.repo.RetryImageJob(job.AttachmentID, time.Now().Add(imageRetryDelay(job.Attempts)), err.Error()); err != nil {
+			logger.Log.Error("Failed to reschedule image job", zap.String("attachment_id", job.AttachmentID), zap.Error(err))
+		}
+	}
+	return true
+}
+
+// processImage строит варианты картинки и сохраняет их в хранилище вложений.
+// Ключ варианта строится из хэша исходного файла, поэтому одинаковые картинки
+// разных вложений делят одни и те же файлы.
+func (s *forumServiceImpl) processImage(job *forummodels.ImageJob) error {
+	content, err := s.blobs.Get(job.SHA256)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(content)
+	content.Close()
+	if err != nil {
+		return err
+	}
+
+	result, err := imaging.Process(data, s.imageLimits, imaging.DefaultSizes)
+	if err != nil {
+		return err
+	}
+
+	variants := make([]*forummodels.AttachmentVariant, 0, len(result.Outputs))
+	for _, output := range result.Outputs {
+		key := job.SHA256 + "-" + output.Name
+		exists, err := s.blobs.Exists(key)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			size := int64(len(output.Data))
+			if err := s.blobs.Put(key, bytes.NewReader(output.Data), size, output.ContentType); err != nil {
+				return err
+			}
+		}
+		variants = append(variants, &forummodels.AttachmentVariant{
+			Name:        output.Name,
+			ContentType: output.ContentType,
+			Size:        int64(len(output.Data)),
+			Width:       output.Width,
+			Height:      output.Height,
+			Key:         key,
+		})
+	}
+	return s.repo.CompleteImageJob(job.AttachmentID, result.Width, result.Height, variants)
+}
+
+// imageRetryDelay задержка перед повторной попыткой: 1, 4, 9, 16 минут
+func imageRetryDelay(attempts int) time.Duration {
+	return time.Duration(attempts*attempts) * time.Minute
+}
@@ -1,44 +1,216 @@
 package service
 
 import (
+	"io"
 	"time"
 
 	"github.com/gorilla/websocket"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
 	"github.com/luckermt/forum-app/shared/pkg/models"
 )
 
 
 type ForumService interface {
 	// Topics
-	CreateTopic(userID, title, content string) (*models.Topic, error)
-	GetTopics() ([]*models.Topic, error)
-	DeleteTopic(topicID, userID string) error
+	CreateTopic(communityID, userID string, req forummodels.CreateTopicRequest) (*forummodels.TopicDetail, error)
+	GetTopics(communityID string, filter forummodels.TopicFilter, params forummodels.PageParams) (*forummodels.TopicPage, error)
+	DeleteTopic(communityID, topicID, userID string) error
+	GetTopic(communityID, topicID string) (*forummodels.TopicDetail, error)
+	UpdateTopic(communityID, topicID, userID string, req forummodels.TopicUpdateRequest) (*forummodels.TopicDetail, error)
+	GetTopicRevisions(communityID, topicID string) ([]*forummodels.TopicRevision, error)
+	DiffTopicRevisions(communityID, topicID string, from, to int) (*forummodels.TopicDiff, error)
+	SetTopicState(communityID, topicID, userID string, req forummodels.TopicStateRequest) (*forummodels.TopicDetail, error)
+
+	// Categories
+	CreateCategory(communityID, userID string, req forummodels.CategoryRequest) (*forummodels.Category, error)
+	UpdateCategory(communityID, userID, categoryID string, req forummodels.CategoryRequest) (*forummodels.Category, error)
+	DeleteCategory(communityID, userID, categoryID string) error
+	GetCategoryTree(communityID string) ([]*forummodels.Category, error)
+
+	// Tags
+	GetTagSettings(communityID string) (*forummodels.TagSettings, error)
+	SetTagSettings(communityID, userID string, settings forummodels.TagSettings) error
+	CreateOfficialTag(communityID, userID, name string) (*forummodels.Tag, error)
+	DeleteTag(communityID, userID, name string) error
+	MergeTag(communityID, userID, from, into string) error
+	SearchTags(communityID, prefix string, limit int) ([]*forummodels.Tag, error)
 
 	// Messages
-	CreateMessage(message *models.Message) error
-	GetTopicMessages(topicID string) ([]*models.Message, error)
-	GetChatMessages() ([]*models.Message, error)
+	CreateMessage(communityID string, message *models.Message) error
+	CreateTopicMessage(communityID, topicID, userID string, req forummodels.MessageRequest) (*forummodels.ThreadMessage, error)
+	GetThread(communityID, topicID string, params forummodels.ThreadParams) (*forummodels.ThreadPage, error)
+	GetTopicMessages(communityID, topicID, viewerID string, params forummodels.PageParams) (*forummodels.MessagePage, error)
+	GetChatMessages(communityID, viewerID string, params forummodels.PageParams) (*forummodels.MessagePage, error)
+	UpdateMessage(communityID, messageID, userID string, req forummodels.MessageUpdateRequest) (*forummodels.MessageDetail, error)
+	DeleteMessage(communityID, messageID, userID string) error
+	GetMessageRevisions(communityID, messageID string) ([]*forummodels.MessageRevision, error)
 	DeleteMessagesOlderThan(maxAge time.Duration) error
 
+	// Reactions
+	AddReaction(communityID, targetType, targetID, userID, emoji string) ([]*forummodels.ReactionCount, error)
+	RemoveReaction(communityID, targetType, targetID, userID, emoji string) ([]*forummodels.ReactionCount, error)
+	GetReactions(communityID, targetType, targetID, viewerID string) ([]*forummodels.ReactionCount, error)
+
+	// Votes
+	Vote(communityID, targetType, targetID, userID string, value int) (*forummodels.VoteResult, error)
+	GetUserKarma(userID string) (*forummodels.Karma, error)
+
+	// Notifications
+	GetNotifications(communityID, userID string, unreadOnly bool, params forummodels.PageParams) (*forummodels.NotificationPage, error)
+	GetUnreadNotificationCount(communityID, userID string) (int, error)
+	MarkNotificationRead(communityID, userID, notificationID string) (int, error)
+	MarkNotificationsRead(communityID, userID string, ids []string) (int, error)
+
+	// Watches
+	SetWatch(communityID, targetType, targetID, userID, level string) (*forummodels.Watch, error)
+	RemoveWatch(communityID, targetType, targetID, userID string) (*forummodels.Watch, error)
+	GetTopicWatch(communityID, topicID, userID string) (*forummodels.Watch, error)
+
+	// Direct messages
+	CreateConversation(communityID, userID string, req forummodels.ConversationRequest) (*forummodels.Conversation, error)
+	GetConversations(communityID, userID string) ([]*forummodels.Conversation, error)
+	GetConversation(communityID, conversationID, userID string) (*forummodels.Conversation, error)
+	GetDirectMessages(communityID, conversationID, userID string, params forummodels.PageParams) (*forummodels.DirectMessagePage, error)
+	SendDirectMessage(communityID, conversationID, userID string, req forummodels.DirectMessageRequest) (*forummodels.DirectMessage, error)
+	MarkConversationRead(communityID, conversationID, userID string) (*forummodels.Conversation, error)
+	BlockUser(userID, blockedID string) error
+	UnblockUser(userID, blockedID string) error
+	GetBlockedUsers(userID string) ([]string, error)
+
+	// Attachments
+	UploadAttachment(communityID, userID, filename string, content io.Reader) (*forummodels.Attachment, error)
+	OpenAttachment(communityID, attachmentID, variant, userID string) (*forummodels.AttachmentFile, io.ReadCloser, error)
+
+	// Polls
+	GetPoll(communityID, topicID, viewerID string) (*forummodels.Poll, error)
+	VotePoll(communityID, topicID, userID string, optionIDs []string) (*forummodels.Poll, error)
+	ChangePollVote(communityID, topicID, userID string, optionIDs []string) (*forummodels.Poll, error)
+	RetractPollVote(communityID, topicID, userID string) (*forummodels.Poll, error)
+
+	// Backlinks
+	GetTopicBacklinks(communityID, topicID string) ([]*forummodels.Backlink, error)
+
+	// Search
+	Search(communityID string, q forummodels.SearchQuery) ([]*forummodels.SearchResult, error)
+
 	// Chat
-	RegisterClient(userID string, conn *websocket.Conn)
-	UnregisterClient(userID string)
-	HandleChatMessage(userID, text string) error
+	RegisterClient(communityID, userID string, conn *websocket.Conn)
+	UnregisterClient(communityID string, conn *websocket.Conn)
+	RegisterTopicListener(communityID, topicID, userID string, conn *websocket.Conn) error
+	UnregisterTopicListener(communityID, topicID string, conn *websocket.Conn)
+	HandleChatMessage(communityID, userID, text string) error
 	CleanOldMessages(maxAge time.Duration)
 	
 	// Auth
-	ValidateUser(token string) (string, error)
+	ValidateUser(token, scope string) (string, error)
 	IsUserAdmin(userID string) (bool, error)
+
+	// Communities
+	ResolveCommunity(host, slug string) (string, error)
 }
 type Repository interface {
 	// Topics
-	CreateTopic(topic *models.Topic) error
-	GetTopics() ([]*models.Topic, error)
-	DeleteTopic(topicID string) error
+	CreateTopic(communityID string, topic *forummodels.TopicDetail) error
+	GetTopics(communityID string, filter forummodels.TopicFilter, page forummodels.PageQuery) ([]*forummodels.TopicSummary, error)
+	DeleteTopic(communityID, topicID string) error
+	GetTopic(communityID, topicID string) (*forummodels.TopicDetail, error)
+	UpdateTopic(communityID, topicID, title, content, contentHTML, editorID string, at time.Time) (*forummodels.TopicDetail, error)
+	GetTopicRevisions(communityID, topicID string) ([]*forummodels.TopicRevision, error)
+	GetTopicRevision(communityID, topicID string, revision int) (*forummodels.TopicRevision, error)
+	SetTopicState(communityID, topicID string, state forummodels.TopicState) (*forummodels.TopicDetail, error)
+
+	// Categories
+	CreateCategory(communityID string, category *forummodels.Category) error
+	UpdateCategory(communityID string, category *forummodels.Category) error
+	DeleteCategory(communityID, categoryID string) error
+	GetCategory(communityID, categoryID string) (*forummodels.Category, error)
+	GetCategories(communityID string) ([]*forummodels.Category, error)
+
+	// Tags
+	GetTagSettings(communityID string) (*forummodels.TagSettings, error)
+	SetTagSettings(communityID string, settings forummodels.TagSettings) error
+	GetTags(communityID string, names []string) ([]*forummodels.Tag, error)
+	CreateOfficialTag(communityID, name string) error
+	DeleteTag(communityID, name string) error
+	MergeTag(communityID, from, into string) error
+	SearchTags(communityID, prefix string, limit int) ([]*forummodels.Tag, error)
 
 	// Messages
-	CreateMessage(message *models.Message) error
-	GetMessagesByTopic(topicID string) ([]*models.Message, error)
-	GetChatMessages() ([]*models.Message, error)
+	CreateMessage(communityID string, message *models.Message, contentHTML string) error
+	GetMessagesByTopic(communityID, topicID string, page forummodels.PageQuery) ([]*forummodels.MessageDetail, error)
+	GetChatMessages(communityID string, page forummodels.PageQuery) ([]*forummodels.MessageDetail, error)
+	GetMessage(communityID, messageID string) (*forummodels.ThreadMessage, error)
+	CreateThreadMessage(communityID string, message *forummodels.ThreadMessage) error
+	GetThread(communityID, topicID, parentID string, maxDepth int, page forummodels.PageQuery) ([]*forummodels.ThreadMessage, error)
+	UpdateMessage(communityID, messageID, content, contentHTML, editorID string, at time.Time) (*forummodels.MessageDetail, error)
+	DeleteMessage(communityID, messageID, userID string, at time.Time) (*forummodels.MessageDetail, error)
+	GetMessageRevisions(communityID, messageID string) ([]*forummodels.MessageRevision, error)
 	DeleteMessagesOlderThan(maxAge time.Duration) error
+
+	// Reactions
+	AddReaction(communityID, targetType, targetID, userID, emoji string, at time.Time) error
+	RemoveReaction(communityID, targetType, targetID, userID, emoji string) error
+	GetReactions(communityID, targetType string, targetIDs []string, viewerID string) (map[string][]*forummodels.ReactionCount, error)
+
+	// Votes
+	SetVote(communityID, targetType, targetID, userID, authorID string, value int, at time.Time) (int, error)
+	GetUserKarma(userID string) (*forummodels.Karma, error)
+
+	// Mentions
+	ReplaceMentions(communityID, messageID string, mentions []*forummodels.Mention, at time.Time) ([]*forummodels.Mention, error)
+	GetMentions(communityID string, messageIDs []string) (map[string][]*forummodels.Mention, error)
+
+	// Notifications
+	CreateNotifications(communityID string, notifications []*forummodels.Notification) error
+	GetNotifications(communityID, userID string, unreadOnly bool, page forummodels.PageQuery) ([]*forummodels.Notification, error)
+	MarkNotificationsRead(communityID, userID string, ids []string, at time.Time) (int, error)
+	CountUnreadNotifications(communityID, userID string) (int, error)
+
+	// Watches
+	SetWatch(communityID, userID, targetType, targetID, level string, at time.Time) error
+	AddWatch(communityID, userID, targetType, targetID, level string, at time.Time) error
+	DeleteWatch(communityID, userID, targetType, targetID string) error
+	GetWatch(communityID, userID, targetType, targetID string) (string, error)
+	GetTopicWatchers(communityID, topicID, categoryID string) (map[string]string, error)
+
+	// Direct messages
+	CreateConversation(communityID string, conversation *forummodels.Conversation, directKey string) (*forummodels.Conversation, error)
+	GetConversation(communityID, conversationID, viewerID string) (*forummodels.Conversation, error)
+	GetConversations(communityID, userID string) ([]*forummodels.Conversation, error)
+	CreateDirectMessage(message *forummodels.DirectMessage) error
+	GetDirectMessages(conversationID string, page forummodels.PageQuery) ([]*forummodels.DirectMessage, error)
+	MarkConversationRead(conversationID, userID string, at time.Time) error
+	BlockUser(userID, blockedID string, at time.Time) error
+	UnblockUser(userID, blockedID string) error
+	GetBlockedUsers(userID string) ([]string, error)
+	BlockedBy(senderID string, userIDs []string) ([]string, error)
+
+	// Attachments
+	CreateAttachment(communityID string, attachment *forummodels.Attachment) error
+	GetAttachment(communityID, attachmentID string) (*forummodels.Attachment, error)
+	GetAttachments(communityID string, ids []string) ([]*forummodels.Attachment, error)
+	GetTargetAttachments(communityID, targetType string, targetIDs []string) (map[string][]*forummodels.Attachment, error)
+	IsAttachmentVisible(communityID, attachmentID string) (bool, error)
+	GetAttachmentVariants(attachmentIDs []string) (map[string][]*forummodels.AttachmentVariant, error)
+
+	// Image jobs
+	ClaimImageJob(at time.Time, lease time.Duration) (*forummodels.ImageJob, error)
+	CompleteImageJob(attachmentID string, width, height int, variants []*forummodels.AttachmentVariant) error
+	FailImageJob(attachmentID, status string) error
+	RetryImageJob(attachmentID string, runAfter time.Time, lastError string) error
+
+	// Polls
+	GetTopicPoll(communityID, topicID string) (*forummodels.Poll, error)
+	GetPollChoices(pollID, userID string) ([]string, error)
+	CreatePollVote(pollID, userID string, optionIDs []string, at time.Time) error
+	ReplacePollVote(pollID, userID string, optionIDs []string, at time.Time) error
+	DeletePollVote(pollID, userID string) error
+
+	// References
+	ReplaceReferences(communityID, sourceType, sourceID, sourceTopicID string, refs []*forummodels.Reference, at time.Time) error
+	GetReferences(communityID, sourceType string, sourceIDs []string) (map[string][]*forummodels.Reference, error)
+	GetBacklinks(communityID, topicID string, limit int) ([]*forummodels.Backlink, error)
+
+	// Search
+	Search(communityID string, q forummodels.SearchQuery) ([]*forummodels.SearchResult, error)
 }
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"unicode/utf8"
+
+	"github.com/luckermt/forum-app/forum-service/internal/markdown"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// SetMarkdownRenderer задает рендерер текста тем и сообщений с ограничениями из конфигурации
+func (s *forumServiceImpl) SetMarkdownRenderer(renderer *markdown.Renderer) {
+	s.markdown = renderer
+}
+
+// contentHTML возвращает сохраненный HTML, а для записей, созданных до появления
+// content_html, рендерит его из исходного текста
+func (s *forumServiceImpl) contentHTML(content, stored string) string {
+	if stored != "" {
+		return stored
+	}
+	return s.markdown.Render(content)
+}
+
+// fillMessageHTML дополняет HTML у старых сообщений; у удаленных текста нет
+func (s *forumServiceImpl) fillMessageHTML(messages []*forummodels.MessageDetail) {
+	for _, message := range messages {
+		if !message.Deleted {
+			message.ContentHTML = s.contentHTML(message.Content, message.ContentHTML)
+		}
+	}
+}
+
+// checkContentLength ограничивает текст, который будет отрендерен в HTML.
+// Тексты из REST запросов проверяются тегами binding, а чат и создание темы — здесь.
+func checkContentLength(content string) error {
+	if utf8.RuneCountInString(content) > forummodels.MaxContentLength {
+		return ErrContentTooLong
+	}
+	return nil
+}
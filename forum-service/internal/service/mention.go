@@ -0,0 +1,148 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxMentionsPerMessage сколько разных пользователей можно упомянуть в одном сообщении
+const maxMentionsPerMessage = 20
+
+// mentionPattern упоминание вида @username из букв любого алфавита, цифр и символов _ . -;
+// точка и дефис не могут завершать имя, чтобы не захватывать знаки препинания.
+// Символ перед @ не должен быть частью слова, чтобы адреса почты вроде
+// john@example.com не считались упоминаниями.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@])@([\p{L}\p{N}_][\p{L}\p{N}_.-]{1,48}[\p{L}\p{N}_])`)
+
+// parseMentions возвращает имена упомянутых пользователей в нижнем регистре без повторов
+func parseMentions(content string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		username := strings.ToLower(match[1])
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+		if len(usernames) == maxMentionsPerMessage {
+			break
+		}
+	}
+	return usernames
+}
+
+// mentionEntities размечает в тексте упоминания пользователей из mentions
+func mentionEntities(content string, mentions []*forummodels.Mention) []*forummodels.MessageEntity {
+	if len(mentions) == 0 {
+		return nil
+	}
+	byName := make(map[string]*forummodels.Mention, len(mentions))
+	for _, mention := range mentions {
+		byName[strings.ToLower(mention.Username)] = mention
+	}
+
+	var entities []*forummodels.MessageEntity
+	for _, loc := range mentionPattern.FindAllStringSubmatchIndex(content, -1) {
+		mention, ok := byName[strings.ToLower(content[loc[2]:loc[3]])]
+		if !ok {
+			continue
+		}
+		// Позиция символа @ прямо перед именем
+		start := loc[2] - 1
+		entities = append(entities, &forummodels.MessageEntity{
+			Type:     forummodels.EntityMention,
+			Offset:   utf8.RuneCountInString(content[:start]),
+			Length:   utf8.RuneCountInString(content[start:loc[3]]),
+			UserID:   mention.UserID,
+			Username: mention.Username,
+		})
+	}
+	return entities
+}
+
+// syncMentions сохраняет упоминания из текста сообщения, размечает их в message.Entities
+// и возвращает уведомления для впервые упомянутых пользователей. Ошибки только логируются:
+// сообщение к этому моменту уже сохранено.
+func (s *forumServiceImpl) syncMentions(communityID string, message *forummodels.MessageDetail, isNew bool) []*forummodels.Notification {
+	usernames := parseMentions(message.Content)
+	if len(usernames) == 0 && isNew {
+		return nil
+	}
+
+	mentions, err := s.resolveMentions(communityID, message.UserID, usernames)
+	if err != nil {
+		logger.Log.Error("Failed to resolve mentions",
+			zap.String("community_id", communityID),
+			zap.String("message_id", message.ID),
+			zap.Error(err))
+		return nil
+	}
+
+	added, err := s.repo.ReplaceMentions(communityID, message.ID, mentions, time.Now())
+	if err != nil {
+		logger.Log.Error("Failed to save mentions",
+			zap.String("community_id", communityID),
+			zap.String("message_id", message.ID),
+			zap.Error(err))
+		return nil
+	}
+	message.Entities = mentionEntities(message.Content, mentions)
+
+	notifications := make([]*forummodels.Notification, 0, len(added))
+	for _, mention := range added {
+		notifications = append(notifications, newNotification(mention.UserID, forummodels.NotificationMention,
+			message.UserID, message.TopicID, message.ID))
+	}
+	return notifications
+}
+
+// resolveMentions находит участников сообщества по именам одним запросом в auth-service.
+// Автор сообщения, заблокированные и те, кто не состоит в сообществе, пропускаются.
+func (s *forumServiceImpl) resolveMentions(communityID, authorID string, usernames []string) ([]*forummodels.Mention, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+	users, err := s.authClient.ResolveUsernames(communityID, usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions := make([]*forummodels.Mention, 0, len(users))
+	for username, userID := range users {
+		if userID == authorID {
+			continue
+		}
+		mentions = append(mentions, &forummodels.Mention{UserID: userID, Username: username})
+	}
+	return mentions, nil
+}
+
+// attachMentions размечает упоминания у страницы сообщений
+func (s *forumServiceImpl) attachMentions(communityID string, messages []*forummodels.MessageDetail) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+
+	mentions, err := s.repo.GetMentions(communityID, ids)
+	if err != nil {
+		logger.Log.Error("Failed to get message mentions",
+			zap.String("community_id", communityID),
+			zap.Error(err))
+		return err
+	}
+	for _, message := range messages {
+		message.Entities = mentionEntities(message.Content, mentions[message.ID])
+	}
+	return nil
+}
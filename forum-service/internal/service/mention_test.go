@@ -0,0 +1,37 @@
+package service
+
+import (
+	"testing"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	usernames := parseMentions("@John привет, @anna и снова @john! Пиши на john@example.com, @ab слишком короткий")
+
+	assert.Equal(t, []string{"john", "anna"}, usernames)
+	assert.Empty(t, parseMentions("без упоминаний"))
+}
+
+func TestParseMentions_Unicode(t *testing.T) {
+	usernames := parseMentions("Спасибо, @Алиса и @jan.kowalski-2. Передай @Zoë_99!")
+
+	assert.Equal(t, []string{"алиса", "jan.kowalski-2", "zoë_99"}, usernames)
+}
+
+func TestMentionEntities(t *testing.T) {
+	mentions := []*forummodels.Mention{{UserID: "u1", Username: "john"}}
+
+	entities := mentionEntities("Привет, @John и @anna", mentions)
+
+	assert.Len(t, entities, 1)
+	assert.Equal(t, &forummodels.MessageEntity{
+		Type:     forummodels.EntityMention,
+		Offset:   8,
+		Length:   5,
+		UserID:   "u1",
+		Username: "john",
+	}, entities[0])
+	assert.Nil(t, mentionEntities("@john", nil))
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/repository"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// SetEditWindow задает, сколько времени автор может править и удалять свое сообщение.
+// Модераторов ограничение не касается.
+func (s *forumServiceImpl) SetEditWindow(window time.Duration) {
+	s.editWindow = window
+}
+
+// UpdateMessage меняет текст сообщения, сохраняя прежнюю версию
+func (s *forumServiceImpl) UpdateMessage(communityID, messageID, userID string, req forummodels.MessageUpdateRequest) (*forummodels.MessageDetail, error) {
+	message, err := s.getMessage(communityID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireMessageEditor(communityID, userID, message); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateMessage(communityID, messageID, req.Content, s.markdown.Render(req.Content), userID, time.Now())
+	if errors.Is(err, repository.ErrMessageNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		logger.Log.Error("Failed to update message",
+			zap.String("message_id", messageID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	s.notify(communityID, s.syncMentions(communityID, updated, false))
+	s.syncMessageReferences(communityID, updated, false)
+	s.notifyModeration(communityID, userID, message.UserID, forummodels.ModerationMessageEdited, message.TopicID, messageID)
+	s.broadcastMessageEvent(communityID, forummodels.MessageEventEdited, updated)
+	return updated, nil
+}
+
+// DeleteMessage удаляет сообщение, оставляя на его месте заглушку
+func (s *forumServiceImpl) DeleteMessage(communityID, messageID, userID string) error {
+	message, err := s.getMessage(communityID, messageID)
+	if err != nil {
+		return err
+	}
+	if err := s.requireMessageEditor(communityID, userID, message); err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteMessage(communityID, messageID, userID, time.Now())
+	if errors.Is(err, repository.ErrMessageNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		logger.Log.Error("Failed to delete message",
+			zap.String("message_id", messageID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return err
+	}
+
+	logger.Log.Info("Message deleted",
+		zap.String("message_id", messageID),
+		zap.String("user_id", userID))
+	s.notifyModeration(communityID, userID, message.UserID, forummodels.ModerationMessageDeleted, message.TopicID, messageID)
+	s.broadcastMessageEvent(communityID, forummodels.MessageEventDeleted, deleted)
+	return nil
+}
+
+// GetMessageRevisions возвращает прежние версии сообщения. История удаленных сообщений скрыта.
+func (s *forumServiceImpl) GetMessageRevisions(communityID, messageID string) ([]*forummodels.MessageRevision, error) {
+	if _, err := s.getMessage(communityID, messageID); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.repo.GetMessageRevisions(communityID, messageID)
+	if err != nil {
+		logger.Log.Error("Failed to get message revisions",
+			zap.String("message_id", messageID),
+			zap.Error(err))
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// getMessage возвращает неудаленное сообщение
+func (s *forumServiceImpl) getMessage(communityID, messageID string) (*forummodels.ThreadMessage, error) {
+	message, err := s.repo.GetMessage(communityID, messageID)
+	if errors.Is(err, repository.ErrMessageNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if message.Deleted {
+		return nil, ErrNotFound
+	}
+	return message, nil
+}
+
+// requireMessageEditor проверяет право изменить сообщение: модераторы могут всегда,
+// автор — пока не истекло окно правки и тема открыта для ответов
+func (s *forumServiceImpl) requireMessageEditor(communityID, userID string, message *forummodels.ThreadMessage) error {
+	role, err := s.requireEditor(communityID, userID, message.UserID)
+	if err != nil {
+		return err
+	}
+	if roleAtLeast(role, "moderator") {
+		return nil
+	}
+	if time.Since(message.CreatedAt) > s.editWindow {
+		return ErrEditWindowClosed
+	}
+	if message.TopicID != "" {
+		return s.requireWritableTopic(communityID, message.TopicID)
+	}
+	return nil
+}
+
+// broadcastMessageEvent рассылает событие клиентам чата или слушателям темы сообщения
+func (s *forumServiceImpl) broadcastMessageEvent(communityID, eventType string, message *forummodels.MessageDetail) {
+	event := forummodels.MessageEvent{Type: eventType, Message: message}
+	if message.IsChat {
+		s.broadcastChan <- chatBroadcast{communityID: communityID, message: event}
+	} else if message.TopicID != "" {
+		s.broadcastChan <- chatBroadcast{communityID: communityID, topicID: message.TopicID, message: event}
+	}
+}
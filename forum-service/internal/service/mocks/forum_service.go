@@ -1,9 +1,11 @@
 package mocks
 
 import (
+	"io"
 	"time"
 
 	"github.com/gorilla/websocket"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
 	"github.com/luckermt/forum-app/shared/pkg/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -13,46 +15,409 @@ type ForumService struct {
 	mock.Mock
 }
 
-func (m *ForumService) CreateTopic(userID, title, content string) (*models.Topic, error) {
-	args := m.Called(userID, title, content)
+func (m *ForumService) CreateTopic(communityID, userID string, req forummodels.CreateTopicRequest) (*forummodels.TopicDetail, error) {
+	args := m.Called(communityID, userID, req)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.Topic), args.Error(1)
+	return args.Get(0).(*forummodels.TopicDetail), args.Error(1)
 }
 
-func (m *ForumService) GetTopics() ([]*models.Topic, error) {
-	args := m.Called()
+func (m *ForumService) GetTopics(communityID string, filter forummodels.TopicFilter, params forummodels.PageParams) (*forummodels.TopicPage, error) {
+	args := m.Called(communityID, filter, params)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*models.Topic), args.Error(1)
+	return args.Get(0).(*forummodels.TopicPage), args.Error(1)
 }
 
-func (m *ForumService) DeleteTopic(topicID, userID string) error {
-	args := m.Called(topicID, userID)
+func (m *ForumService) DeleteTopic(communityID, topicID, userID string) error {
+	args := m.Called(communityID, topicID, userID)
 	return args.Error(0)
 }
 
-func (m *ForumService) CreateMessage(message *models.Message) error {
-	args := m.Called(message)
+func (m *ForumService) GetTopic(communityID, topicID string) (*forummodels.TopicDetail, error) {
+	args := m.Called(communityID, topicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.TopicDetail), args.Error(1)
+}
+
+func (m *ForumService) UpdateTopic(communityID, topicID, userID string, req forummodels.TopicUpdateRequest) (*forummodels.TopicDetail, error) {
+	args := m.Called(communityID, topicID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.TopicDetail), args.Error(1)
+}
+
+func (m *ForumService) GetTopicRevisions(communityID, topicID string) ([]*forummodels.TopicRevision, error) {
+	args := m.Called(communityID, topicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*forummodels.TopicRevision), args.Error(1)
+}
+
+func (m *ForumService) DiffTopicRevisions(communityID, topicID string, from, to int) (*forummodels.TopicDiff, error) {
+	args := m.Called(communityID, topicID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.TopicDiff), args.Error(1)
+}
+
+func (m *ForumService) SetTopicState(communityID, topicID, userID string, req forummodels.TopicStateRequest) (*forummodels.TopicDetail, error) {
+	args := m.Called(communityID, topicID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.TopicDetail), args.Error(1)
+}
+
+func (m *ForumService) CreateTopicMessage(communityID, topicID, userID string, req forummodels.MessageRequest) (*forummodels.ThreadMessage, error) {
+	args := m.Called(communityID, topicID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.ThreadMessage), args.Error(1)
+}
+
+func (m *ForumService) GetThread(communityID, topicID string, params forummodels.ThreadParams) (*forummodels.ThreadPage, error) {
+	args := m.Called(communityID, topicID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.ThreadPage), args.Error(1)
+}
+
+func (m *ForumService) RegisterTopicListener(communityID, topicID, userID string, conn *websocket.Conn) error {
+	args := m.Called(communityID, topicID, userID, conn)
 	return args.Error(0)
 }
 
-func (m *ForumService) GetTopicMessages(topicID string) ([]*models.Message, error) {
-	args := m.Called(topicID)
+func (m *ForumService) UnregisterTopicListener(communityID, topicID string, conn *websocket.Conn) {
+	m.Called(communityID, topicID, conn)
+}
+
+func (m *ForumService) UpdateMessage(communityID, messageID, userID string, req forummodels.MessageUpdateRequest) (*forummodels.MessageDetail, error) {
+	args := m.Called(communityID, messageID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.MessageDetail), args.Error(1)
+}
+
+func (m *ForumService) DeleteMessage(communityID, messageID, userID string) error {
+	args := m.Called(communityID, messageID, userID)
+	return args.Error(0)
+}
+
+func (m *ForumService) GetMessageRevisions(communityID, messageID string) ([]*forummodels.MessageRevision, error) {
+	args := m.Called(communityID, messageID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*forummodels.MessageRevision), args.Error(1)
+}
+
+func (m *ForumService) AddReaction(communityID, targetType, targetID, userID, emoji string) ([]*forummodels.ReactionCount, error) {
+	args := m.Called(communityID, targetType, targetID, userID, emoji)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*forummodels.ReactionCount), args.Error(1)
+}
+
+func (m *ForumService) RemoveReaction(communityID, targetType, targetID, userID, emoji string) ([]*forummodels.ReactionCount, error) {
+	args := m.Called(communityID, targetType, targetID, userID, emoji)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*forummodels.ReactionCount), args.Error(1)
+}
+
+func (m *ForumService) GetReactions(communityID, targetType, targetID, viewerID string) ([]*forummodels.ReactionCount, error) {
+	args := m.Called(communityID, targetType, targetID, viewerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*forummodels.ReactionCount), args.Error(1)
+}
+
+func (m *ForumService) Vote(communityID, targetType, targetID, userID string, value int) (*forummodels.VoteResult, error) {
+	args := m.Called(communityID, targetType, targetID, userID, value)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.VoteResult), args.Error(1)
+}
+
+func (m *ForumService) GetUserKarma(userID string) (*forummodels.Karma, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.Karma), args.Error(1)
+}
+
+func (m *ForumService) GetNotifications(communityID, userID string, unreadOnly bool, params forummodels.PageParams) (*forummodels.NotificationPage, error) {
+	args := m.Called(communityID, userID, unreadOnly, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.NotificationPage), args.Error(1)
+}
+
+func (m *ForumService) GetUnreadNotificationCount(communityID, userID string) (int, error) {
+	args := m.Called(communityID, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *ForumService) MarkNotificationRead(communityID, userID, notificationID string) (int, error) {
+	args := m.Called(communityID, userID, notificationID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *ForumService) MarkNotificationsRead(communityID, userID string, ids []string) (int, error) {
+	args := m.Called(communityID, userID, ids)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *ForumService) SetWatch(communityID, targetType, targetID, userID, level string) (*forummodels.Watch, error) {
+	args := m.Called(communityID, targetType, targetID, userID, level)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*models.Message), args.Error(1)
+	return args.Get(0).(*forummodels.Watch), args.Error(1)
 }
 
-func (m *ForumService) GetChatMessages() ([]*models.Message, error) {
-	args := m.Called()
+func (m *ForumService) RemoveWatch(communityID, targetType, targetID, userID string) (*forummodels.Watch, error) {
+	args := m.Called(communityID, targetType, targetID, userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*models.Message), args.Error(1)
+	return args.Get(0).(*forummodels.Watch), args.Error(1)
+}
+
+func (m *ForumService) GetTopicWatch(communityID, topicID, userID string) (*forummodels.Watch, error) {
+	args := m.Called(communityID, topicID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.Watch), args.Error(1)
+}
+
+func (m *ForumService) CreateConversation(communityID, userID string, req forummodels.ConversationRequest) (*forummodels.Conversation, error) {
+	args := m.Called(communityID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.Conversation), args.Error(1)
+}
+
+func (m *ForumService) GetConversations(communityID, userID string) ([]*forummodels.Conversation, error) {
+	args := m.Called(communityID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*forummodels.Conversation), args.Error(1)
+}
+
+func (m *ForumService) GetConversation(communityID, conversationID, userID string) (*forummodels.Conversation, error) {
+	args := m.Called(communityID, conversationID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.Conversation), args.Error(1)
+}
+
+func (m *ForumService) GetDirectMessages(communityID, conversationID, userID string, params forummodels.PageParams) (*forummodels.DirectMessagePage, error) {
+	args := m.Called(communityID, conversationID, userID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.DirectMessagePage), args.Error(1)
+}
+
+func (m *ForumService) SendDirectMessage(communityID, conversationID, userID string, req forummodels.DirectMessageRequest) (*forummodels.DirectMessage, error) {
+	args := m.Called(communityID, conversationID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.DirectMessage), args.Error(1)
+}
+
+func (m *ForumService) MarkConversationRead(communityID, conversationID, userID string) (*forummodels.Conversation, error) {
+	args := m.Called(communityID, conversationID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.Conversation), args.Error(1)
+}
+
+func (m *ForumService) BlockUser(userID, blockedID string) error {
+	args := m.Called(userID, blockedID)
+	return args.Error(0)
+}
+
+func (m *ForumService) UnblockUser(userID, blockedID string) error {
+	args := m.Called(userID, blockedID)
+	return args.Error(0)
+}
+
+func (m *ForumService) GetBlockedUsers(userID string) ([]string, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *ForumService) UploadAttachment(communityID, userID, filename string, content io.Reader) (*forummodels.Attachment, error) {
+	args := m.Called(communityID, userID, filename, content)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.Attachment), args.Error(1)
+}
+
+func (m *ForumService) OpenAttachment(communityID, attachmentID, variant, userID string) (*forummodels.AttachmentFile, io.ReadCloser, error) {
+	args := m.Called(communityID, attachmentID, variant, userID)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*forummodels.AttachmentFile), args.Get(1).(io.ReadCloser), args.Error(2)
+}
+
+func (m *ForumService) GetPoll(communityID, topicID, viewerID string) (*forummodels.Poll, error) {
+	args := m.Called(communityID, topicID, viewerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.Poll), args.Error(1)
+}
+
+func (m *ForumService) VotePoll(communityID, topicID, userID string, optionIDs []string) (*forummodels.Poll, error) {
+	args := m.Called(communityID, topicID, userID, optionIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.Poll), args.Error(1)
+}
+
+func (m *ForumService) ChangePollVote(communityID, topicID, userID string, optionIDs []string) (*forummodels.Poll, error) {
+	args := m.Called(communityID, topicID, userID, optionIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.Poll), args.Error(1)
+}
+
+func (m *ForumService) RetractPollVote(communityID, topicID, userID string) (*forummodels.Poll, error) {
+	args := m.Called(communityID, topicID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.Poll), args.Error(1)
+}
+
+func (m *ForumService) GetTopicBacklinks(communityID, topicID string) ([]*forummodels.Backlink, error) {
+	args := m.Called(communityID, topicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*forummodels.Backlink), args.Error(1)
+}
+
+func (m *ForumService) CreateCategory(communityID, userID string, req forummodels.CategoryRequest) (*forummodels.Category, error) {
+	args := m.Called(communityID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.Category), args.Error(1)
+}
+
+func (m *ForumService) UpdateCategory(communityID, userID, categoryID string, req forummodels.CategoryRequest) (*forummodels.Category, error) {
+	args := m.Called(communityID, userID, categoryID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.Category), args.Error(1)
+}
+
+func (m *ForumService) DeleteCategory(communityID, userID, categoryID string) error {
+	args := m.Called(communityID, userID, categoryID)
+	return args.Error(0)
+}
+
+func (m *ForumService) GetCategoryTree(communityID string) ([]*forummodels.Category, error) {
+	args := m.Called(communityID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*forummodels.Category), args.Error(1)
+}
+
+func (m *ForumService) GetTagSettings(communityID string) (*forummodels.TagSettings, error) {
+	args := m.Called(communityID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.TagSettings), args.Error(1)
+}
+
+func (m *ForumService) SetTagSettings(communityID, userID string, settings forummodels.TagSettings) error {
+	args := m.Called(communityID, userID, settings)
+	return args.Error(0)
+}
+
+func (m *ForumService) CreateOfficialTag(communityID, userID, name string) (*forummodels.Tag, error) {
+	args := m.Called(communityID, userID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.Tag), args.Error(1)
+}
+
+func (m *ForumService) DeleteTag(communityID, userID, name string) error {
+	args := m.Called(communityID, userID, name)
+	return args.Error(0)
+}
+
+func (m *ForumService) MergeTag(communityID, userID, from, into string) error {
+	args := m.Called(communityID, userID, from, into)
+	return args.Error(0)
+}
+
+func (m *ForumService) SearchTags(communityID, prefix string, limit int) ([]*forummodels.Tag, error) {
+	args := m.Called(communityID, prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*forummodels.Tag), args.Error(1)
+}
+
+func (m *ForumService) CreateMessage(communityID string, message *models.Message) error {
+	args := m.Called(communityID, message)
+	return args.Error(0)
+}
+
+func (m *ForumService) GetTopicMessages(communityID, topicID, viewerID string, params forummodels.PageParams) (*forummodels.MessagePage, error) {
+	args := m.Called(communityID, topicID, viewerID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.MessagePage), args.Error(1)
+}
+
+func (m *ForumService) GetChatMessages(communityID, viewerID string, params forummodels.PageParams) (*forummodels.MessagePage, error) {
+	args := m.Called(communityID, viewerID, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forummodels.MessagePage), args.Error(1)
 }
 
 func (m *ForumService) DeleteMessagesOlderThan(maxAge time.Duration) error {
@@ -60,16 +425,24 @@ func (m *ForumService) DeleteMessagesOlderThan(maxAge time.Duration) error {
 	return args.Error(0)
 }
 
-func (m *ForumService) RegisterClient(userID string, conn *websocket.Conn) {
-	m.Called(userID, conn)
+func (m *ForumService) Search(communityID string, q forummodels.SearchQuery) ([]*forummodels.SearchResult, error) {
+	args := m.Called(communityID, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*forummodels.SearchResult), args.Error(1)
+}
+
+func (m *ForumService) RegisterClient(communityID, userID string, conn *websocket.Conn) {
+	m.Called(communityID, userID, conn)
 }
 
-func (m *ForumService) UnregisterClient(userID string) {
-	m.Called(userID)
+func (m *ForumService) UnregisterClient(communityID string, conn *websocket.Conn) {
+	m.Called(communityID, conn)
 }
 
-func (m *ForumService) HandleChatMessage(userID, text string) error {
-	args := m.Called(userID, text)
+func (m *ForumService) HandleChatMessage(communityID, userID, text string) error {
+	args := m.Called(communityID, userID, text)
 	return args.Error(0)
 }
 
@@ -77,8 +450,8 @@ func (m *ForumService) CleanOldMessages(maxAge time.Duration) {
 	m.Called(maxAge)
 }
 
-func (m *ForumService) ValidateUser(token string) (string, error) {
-	args := m.Called(token)
+func (m *ForumService) ValidateUser(token, scope string) (string, error) {
+	args := m.Called(token, scope)
 	return args.String(0), args.Error(1)
 }
 
@@ -86,3 +459,8 @@ func (m *ForumService) IsUserAdmin(userID string) (bool, error) {
 	args := m.Called(userID)
 	return args.Bool(0), args.Error(1)
 }
+
+func (m *ForumService) ResolveCommunity(host, slug string) (string, error) {
+	args := m.Called(host, slug)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// GetNotifications возвращает страницу уведомлений пользователя, новые первыми
+func (s *forumServiceImpl) GetNotifications(communityID, userID string, unreadOnly bool, params forummodels.PageParams) (*forummodels.NotificationPage, error) {
+	query, err := pageQuery(params, forummodels.SortNewest, forummodels.SortNewest)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications, err := s.repo.GetNotifications(communityID, userID, unreadOnly, query)
+	if err != nil {
+		logger.Log.Error("Failed to get notifications",
+			zap.String("community_id", communityID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	page := &forummodels.NotificationPage{Items: notifications}
+	if len(notifications) == query.Limit {
+		page.Items = notifications[:len(notifications)-1]
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = encodeCursor(forummodels.PageCursor{
+			Sort:      query.Sort,
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+	}
+	return page, nil
+}
+
+// GetUnreadNotificationCount возвращает число непрочитанных уведомлений пользователя
+func (s *forumServiceImpl) GetUnreadNotificationCount(communityID, userID string) (int, error) {
+	count, err := s.repo.CountUnreadNotifications(communityID, userID)
+	if err != nil {
+		logger.Log.Error("Failed to count unread notifications",
+			zap.String("community_id", communityID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return 0, err
+	}
+	return count, nil
+}
+
+// MarkNotificationRead отмечает прочитанным одно уведомление и возвращает число непрочитанных
+func (s *forumServiceImpl) MarkNotificationRead(communityID, userID, notificationID string) (int, error) {
+	marked, err := s.repo.MarkNotificationsRead(communityID, userID, []string{notificationID}, time.Now())
+	if err != nil {
+		logger.Log.Error("Failed to mark notification read",
+			zap.String("notification_id", notificationID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return 0, err
+	}
+	if marked == 0 {
+		return 0, ErrNotFound
+	}
+	return s.notificationsRead(communityID, userID)
+}
+
+// MarkNotificationsRead отмечает прочитанными уведомления из списка или все,
+// если список пуст, и возвращает число непрочитанных
+func (s *forumServiceImpl) MarkNotificationsRead(communityID, userID string, ids []string) (int, error) {
+	if _, err := s.repo.MarkNotificationsRead(communityID, userID, ids, time.Now()); err != nil {
+		logger.Log.Error("Failed to mark notifications read",
+			zap.String("community_id", communityID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return 0, err
+	}
+	return s.notificationsRead(communityID, userID)
+}
+
+// notificationsRead сообщает остальным соединениям пользователя новое число непрочитанных
+func (s *forumServiceImpl) notificationsRead(communityID, userID string) (int, error) {
+	unread, err := s.GetUnreadNotificationCount(communityID, userID)
+	if err != nil {
+		return 0, err
+	}
+	s.broadcastChan <- chatBroadcast{
+		communityID: communityID,
+		userID:      userID,
+		message:     forummodels.NotificationEvent{Type: forummodels.NotificationEventRead, Unread: unread},
+	}
+	return unread, nil
+}
+
+// notify сохраняет уведомления и доставляет их в открытые соединения получателей.
+// Ошибки только логируются: уведомления не должны срывать основное действие.
+func (s *forumServiceImpl) notify(communityID string, notifications []*forummodels.Notification) {
+	if len(notifications) == 0 {
+		return
+	}
+	if err := s.repo.CreateNotifications(communityID, notifications); err != nil {
+		logger.Log.Error("Failed to create notifications",
+			zap.String("community_id", communityID),
+			zap.Int("count", len(notifications)),
+			zap.Error(err))
+		return
+	}
+
+	for _, notification := range notifications {
+		unread, err := s.repo.CountUnreadNotifications(communityID, notification.UserID)
+		if err != nil {
+			logger.Log.Error("Failed to count unread notifications",
+				zap.String("community_id", communityID),
+				zap.String("user_id", notification.UserID),
+				zap.Error(err))
+			continue
+		}
+		s.broadcastChan <- chatBroadcast{
+			communityID: communityID,
+			userID:      notification.UserID,
+			message: forummodels.NotificationEvent{
+				Type:         forummodels.NotificationEventCreated,
+				Notification: notification,
+				Unread:       unread,
+			},
+		}
+	}
+}
+
+// newNotification создает уведомление пользователю userID о действии actorID
+func newNotification(userID, notificationType, actorID, topicID, messageID string) *forummodels.Notification {
+	return &forummodels.Notification{
+		ID:        generateID(),
+		UserID:    userID,
+		Type:      notificationType,
+		ActorID:   actorID,
+		TopicID:   topicID,
+		MessageID: messageID,
+		CreatedAt: time.Now(),
+	}
+}
+
+// replyNotifications уведомляет автора темы и автора сообщения, на которое ответили.
+// Автор ответа и уже получившие уведомление о сообщении пользователи пропускаются.
+func replyNotifications(topicAuthorID string, message *forummodels.MessageDetail, parentAuthorID string, notified []*forummodels.Notification) []*forummodels.Notification {
+	skip := map[string]bool{message.UserID: true}
+	for _, n := range notified {
+		skip[n.UserID] = true
+	}
+
+	notifications := notified
+	for _, userID := range []string{topicAuthorID, parentAuthorID} {
+		if userID == "" || skip[userID] {
+			continue
+		}
+		skip[userID] = true
+		notifications = append(notifications, newNotification(userID, forummodels.NotificationReply,
+			message.UserID, message.TopicID, message.ID))
+	}
+	return notifications
+}
+
+// notifyModeration сообщает автору контента о действии модератора над ним
+func (s *forumServiceImpl) notifyModeration(communityID, moderatorID, authorID, action, topicID, messageID string) {
+	if moderatorID == authorID {
+		return
+	}
+	notification := newNotification(authorID, forummodels.NotificationModeration, moderatorID, topicID, messageID)
+	notification.Action = action
+	s.notify(communityID, []*forummodels.Notification{notification})
+}
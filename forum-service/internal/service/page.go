@@ -0,0 +1,70 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+)
+
+// pageQuery проверяет параметры клиента и превращает их в запрос к репозиторию.
+// Лимит увеличивается на единицу, чтобы понять, есть ли следующая страница.
+func pageQuery(params forummodels.PageParams, defaultSort string, sorts ...string) (forummodels.PageQuery, error) {
+	query := forummodels.PageQuery{
+		Sort:  params.Sort,
+		Limit: params.Limit,
+	}
+	if query.Sort == "" {
+		query.Sort = defaultSort
+	}
+	if !contains(sorts, query.Sort) {
+		return query, ErrInvalidSort
+	}
+
+	switch {
+	case query.Limit <= 0:
+		query.Limit = forummodels.DefaultPageSize
+	case query.Limit > forummodels.MaxPageSize:
+		query.Limit = forummodels.MaxPageSize
+	}
+
+	if params.Cursor != "" {
+		cursor, err := decodeCursor(params.Cursor)
+		if err != nil || cursor.Sort != query.Sort {
+			return query, ErrInvalidCursor
+		}
+		query.After = cursor
+	}
+
+	query.Limit++
+	return query, nil
+}
+
+func encodeCursor(cursor forummodels.PageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(token string) (*forummodels.PageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	var cursor forummodels.PageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.ID == "" || cursor.CreatedAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
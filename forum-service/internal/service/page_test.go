@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageQuery_CursorRoundTrip(t *testing.T) {
+	cursor := forummodels.PageCursor{
+		Sort:         forummodels.SortActive,
+		CreatedAt:    time.Date(2024, 5, 1, 12, 0, 0, 123000, time.UTC),
+		ID:           "topic-1",
+		MessageCount: 7,
+	}
+
+	query, err := pageQuery(forummodels.PageParams{
+		Sort:   forummodels.SortActive,
+		Cursor: encodeCursor(cursor),
+		Limit:  500,
+	}, forummodels.SortNewest, forummodels.SortNewest, forummodels.SortActive)
+
+	assert.NoError(t, err)
+	assert.Equal(t, forummodels.MaxPageSize+1, query.Limit)
+	assert.True(t, cursor.CreatedAt.Equal(query.After.CreatedAt))
+	assert.Equal(t, cursor.ID, query.After.ID)
+	assert.Equal(t, cursor.MessageCount, query.After.MessageCount)
+}
+
+func TestPageQuery_Errors(t *testing.T) {
+	_, err := pageQuery(forummodels.PageParams{Sort: "random"}, forummodels.SortOldest, forummodels.SortOldest)
+	assert.ErrorIs(t, err, ErrInvalidSort)
+
+	_, err = pageQuery(forummodels.PageParams{Cursor: "%%%"}, forummodels.SortOldest, forummodels.SortOldest)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	// Курсор другой сортировки не принимается
+	newest := encodeCursor(forummodels.PageCursor{Sort: forummodels.SortNewest, CreatedAt: time.Now(), ID: "1"})
+	_, err = pageQuery(forummodels.PageParams{Cursor: newest}, forummodels.SortOldest, forummodels.SortOldest, forummodels.SortNewest)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
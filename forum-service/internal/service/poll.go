@@ -0,0 +1,231 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/repository"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// GetPoll возвращает опрос темы с отметкой вариантов, выбранных пользователем viewerID
+func (s *forumServiceImpl) GetPoll(communityID, topicID, viewerID string) (*forummodels.Poll, error) {
+	topic, err := s.GetTopic(communityID, topicID)
+	if err != nil {
+		return nil, err
+	}
+	if topic.Poll == nil {
+		return nil, ErrNotFound
+	}
+	if err := s.attachPollChoices(topic.Poll, viewerID); err != nil {
+		return nil, err
+	}
+	return topic.Poll, nil
+}
+
+// VotePoll голосует в опросе темы. Голосовать можно один раз, изменить голос — через ChangePollVote.
+func (s *forumServiceImpl) VotePoll(communityID, topicID, userID string, optionIDs []string) (*forummodels.Poll, error) {
+	return s.changePollVote(communityID, topicID, userID, func(poll *forummodels.Poll) error {
+		choices, err := pollChoices(poll, optionIDs)
+		if err != nil {
+			return err
+		}
+		err = s.repo.CreatePollVote(poll.ID, userID, choices, time.Now())
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return ErrAlreadyVoted
+		}
+		return err
+	})
+}
+
+// ChangePollVote заменяет ранее выбранные варианты
+func (s *forumServiceImpl) ChangePollVote(communityID, topicID, userID string, optionIDs []string) (*forummodels.Poll, error) {
+	return s.changePollVote(communityID, topicID, userID, func(poll *forummodels.Poll) error {
+		choices, err := pollChoices(poll, optionIDs)
+		if err != nil {
+			return err
+		}
+		err = s.repo.ReplacePollVote(poll.ID, userID, choices, time.Now())
+		if errors.Is(err, repository.ErrPollVoteNotFound) {
+			return ErrNotFound
+		}
+		return err
+	})
+}
+
+// RetractPollVote отзывает голос пользователя
+func (s *forumServiceImpl) RetractPollVote(communityID, topicID, userID string) (*forummodels.Poll, error) {
+	return s.changePollVote(communityID, topicID, userID, func(poll *forummodels.Poll) error {
+		err := s.repo.DeletePollVote(poll.ID, userID)
+		if errors.Is(err, repository.ErrPollVoteNotFound) {
+			return ErrNotFound
+		}
+		return err
+	})
+}
+
+// changePollVote проверяет, что в опросе можно голосовать, применяет изменение
+// и рассылает новые результаты слушателям темы
+func (s *forumServiceImpl) changePollVote(communityID, topicID, userID string, change func(poll *forummodels.Poll) error) (*forummodels.Poll, error) {
+	if err := s.requireMember(communityID, userID); err != nil {
+		return nil, err
+	}
+
+	topic, err := s.GetTopic(communityID, topicID)
+	if err != nil {
+		return nil, err
+	}
+	if topic.Poll == nil {
+		return nil, ErrNotFound
+	}
+	if topic.Archived {
+		return nil, ErrTopicArchived
+	}
+	if topic.Locked {
+		return nil, ErrTopicLocked
+	}
+	if topic.Poll.Closed {
+		return nil, ErrPollClosed
+	}
+
+	if err := change(topic.Poll); err != nil {
+		if !errors.Is(err, ErrInvalidPollVote) && !errors.Is(err, ErrAlreadyVoted) && !errors.Is(err, ErrNotFound) {
+			logger.Log.Error("Failed to change poll vote",
+				zap.String("topic_id", topicID),
+				zap.String("user_id", userID),
+				zap.Error(err))
+		}
+		return nil, err
+	}
+
+	poll, err := s.topicPoll(communityID, topicID)
+	if err != nil {
+		return nil, err
+	}
+	s.broadcastChan <- chatBroadcast{
+		communityID: communityID,
+		topicID:     topicID,
+		message:     forummodels.PollEvent{Type: forummodels.PollEventUpdated, TopicID: topicID, Poll: poll},
+	}
+
+	// Копия, чтобы my_votes не попал в разосланное событие
+	viewed := *poll
+	if err := s.attachPollChoices(&viewed, userID); err != nil {
+		return nil, err
+	}
+	return &viewed, nil
+}
+
+// topicPoll загружает опрос темы для показа всем; если опроса нет, возвращает nil
+func (s *forumServiceImpl) topicPoll(communityID, topicID string) (*forummodels.Poll, error) {
+	poll, err := s.repo.GetTopicPoll(communityID, topicID)
+	if errors.Is(err, repository.ErrPollNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		logger.Log.Error("Failed to get poll",
+			zap.String("topic_id", topicID),
+			zap.Error(err))
+		return nil, err
+	}
+	applyPollVisibility(poll, time.Now())
+	return poll, nil
+}
+
+// attachPollChoices заполняет варианты, выбранные пользователем
+func (s *forumServiceImpl) attachPollChoices(poll *forummodels.Poll, viewerID string) error {
+	if viewerID == "" {
+		return nil
+	}
+	choices, err := s.repo.GetPollChoices(poll.ID, viewerID)
+	if err != nil {
+		logger.Log.Error("Failed to get poll choices",
+			zap.String("poll_id", poll.ID),
+			zap.Error(err))
+		return err
+	}
+	poll.MyVotes = choices
+	return nil
+}
+
+// newPoll проверяет опрос из запроса создания темы
+func newPoll(req *forummodels.PollRequest, now time.Time) (*forummodels.Poll, error) {
+	question := strings.TrimSpace(req.Question)
+	if question == "" || utf8.RuneCountInString(question) > forummodels.MaxPollQuestionLength {
+		return nil, ErrInvalidPoll
+	}
+	if len(req.Options) < 2 || len(req.Options) > forummodels.MaxPollOptions {
+		return nil, ErrInvalidPoll
+	}
+	if req.ClosesAt != nil && !req.ClosesAt.After(now) {
+		return nil, ErrInvalidPoll
+	}
+
+	poll := &forummodels.Poll{
+		ID:          generateID(),
+		Question:    question,
+		Multiple:    req.Multiple,
+		Anonymous:   req.Anonymous,
+		HideResults: req.HideResults,
+		ClosesAt:    req.ClosesAt,
+		CreatedAt:   now,
+	}
+	seen := make(map[string]bool)
+	for _, text := range req.Options {
+		text = strings.TrimSpace(text)
+		key := strings.ToLower(text)
+		if text == "" || utf8.RuneCountInString(text) > forummodels.MaxPollOptionLength || seen[key] {
+			return nil, ErrInvalidPoll
+		}
+		seen[key] = true
+		poll.Options = append(poll.Options, &forummodels.PollOption{ID: generateID(), Text: text, Votes: new(int)})
+	}
+	return poll, nil
+}
+
+// pollChoices проверяет выбранные варианты: все из этого опроса, без повторов,
+// ровно один для опроса с единственным выбором
+func pollChoices(poll *forummodels.Poll, optionIDs []string) ([]string, error) {
+	valid := make(map[string]bool, len(poll.Options))
+	for _, option := range poll.Options {
+		valid[option.ID] = true
+	}
+
+	choices := make([]string, 0, len(optionIDs))
+	seen := make(map[string]bool)
+	for _, id := range optionIDs {
+		if !valid[id] {
+			return nil, ErrInvalidPollVote
+		}
+		if !seen[id] {
+			seen[id] = true
+			choices = append(choices, id)
+		}
+	}
+	if len(choices) == 0 || (!poll.Multiple && len(choices) > 1) {
+		return nil, ErrInvalidPollVote
+	}
+	return choices, nil
+}
+
+// applyPollVisibility отмечает закрытие опроса и скрывает результаты,
+// если автор попросил не показывать их до закрытия
+func applyPollVisibility(poll *forummodels.Poll, now time.Time) {
+	poll.Closed = poll.ClosesAt != nil && !now.Before(*poll.ClosesAt)
+	poll.ResultsHidden = poll.HideResults && !poll.Closed
+	if poll.ResultsHidden {
+		poll.Voters = 0
+	}
+	if poll.Anonymous || poll.ResultsHidden {
+		for _, option := range poll.Options {
+			option.Voters = nil
+			if poll.ResultsHidden {
+				option.Votes = nil
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPoll(t *testing.T) {
+	now := time.Now()
+	poll, err := newPoll(&forummodels.PollRequest{Question: " Какой язык? ", Options: []string{"Go", " Rust "}}, now)
+	require.NoError(t, err)
+	assert.Equal(t, "Какой язык?", poll.Question)
+	require.Len(t, poll.Options, 2)
+	assert.Equal(t, "Rust", poll.Options[1].Text)
+	assert.NotEqual(t, poll.Options[0].ID, poll.Options[1].ID)
+
+	past := now.Add(-time.Hour)
+	invalid := []forummodels.PollRequest{
+		{Question: "", Options: []string{"a", "b"}},
+		{Question: "q", Options: []string{"a"}},
+		{Question: "q", Options: []string{"Go", "go"}},
+		{Question: "q", Options: []string{"a", " "}},
+		{Question: "q", Options: []string{"a", "b"}, ClosesAt: &past},
+	}
+	for _, req := range invalid {
+		_, err := newPoll(&req, now)
+		assert.ErrorIs(t, err, ErrInvalidPoll, "%+v", req)
+	}
+}
+
+func TestPollChoices(t *testing.T) {
+	poll := &forummodels.Poll{Options: []*forummodels.PollOption{{ID: "a"}, {ID: "b"}}}
+
+	choices, err := pollChoices(poll, []string{"a", "a"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, choices)
+
+	_, err = pollChoices(poll, []string{"a", "b"})
+	assert.ErrorIs(t, err, ErrInvalidPollVote)
+	_, err = pollChoices(poll, []string{"c"})
+	assert.ErrorIs(t, err, ErrInvalidPollVote)
+	_, err = pollChoices(poll, nil)
+	assert.ErrorIs(t, err, ErrInvalidPollVote)
+
+	poll.Multiple = true
+	choices, err = pollChoices(poll, []string{"b", "a"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, choices)
+}
+
+func TestApplyPollVisibility(t *testing.T) {
+	now := time.Now()
+	closesAt := now.Add(time.Hour)
+	votes := 3
+	poll := &forummodels.Poll{
+		HideResults: true,
+		ClosesAt:    &closesAt,
+		Voters:      3,
+		Options:     []*forummodels.PollOption{{ID: "a", Votes: &votes, Voters: []string{"u1"}}},
+	}
+
+	applyPollVisibility(poll, now)
+	assert.False(t, poll.Closed)
+	assert.True(t, poll.ResultsHidden)
+	assert.Zero(t, poll.Voters)
+	assert.Nil(t, poll.Options[0].Votes)
+	assert.Nil(t, poll.Options[0].Voters)
+
+	poll.Options[0].Votes = &votes
+	applyPollVisibility(poll, closesAt)
+	assert.True(t, poll.Closed)
+	assert.False(t, poll.ResultsHidden)
+	assert.Equal(t, 3, *poll.Options[0].Votes)
+}
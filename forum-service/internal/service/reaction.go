@@ -0,0 +1,152 @@
+package service
+
+import (
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// AddReaction добавляет реакцию пользователя на тему или сообщение и возвращает итоговые счетчики
+func (s *forumServiceImpl) AddReaction(communityID, targetType, targetID, userID, emoji string) ([]*forummodels.ReactionCount, error) {
+	return s.changeReaction(communityID, targetType, targetID, userID, emoji, forummodels.ReactionEventAdded)
+}
+
+// RemoveReaction снимает реакцию пользователя и возвращает итоговые счетчики
+func (s *forumServiceImpl) RemoveReaction(communityID, targetType, targetID, userID, emoji string) ([]*forummodels.ReactionCount, error) {
+	return s.changeReaction(communityID, targetType, targetID, userID, emoji, forummodels.ReactionEventRemoved)
+}
+
+// GetReactions возвращает счетчики реакций на тему или сообщение с отметкой реакций пользователя viewerID
+func (s *forumServiceImpl) GetReactions(communityID, targetType, targetID, viewerID string) ([]*forummodels.ReactionCount, error) {
+	if _, err := s.reactionTarget(communityID, targetType, targetID); err != nil {
+		return nil, err
+	}
+	return s.targetReactions(communityID, targetType, targetID, viewerID)
+}
+
+func (s *forumServiceImpl) changeReaction(communityID, targetType, targetID, userID, emoji, eventType string) ([]*forummodels.ReactionCount, error) {
+	if _, ok := forummodels.ReactionEmoji[emoji]; !ok {
+		return nil, ErrInvalidReaction
+	}
+	if err := s.requireMember(communityID, userID); err != nil {
+		return nil, err
+	}
+
+	target, err := s.reactionTarget(communityID, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.topicID != "" {
+		if err := s.requireWritableTopic(communityID, target.topicID); err != nil {
+			return nil, err
+		}
+	}
+
+	if eventType == forummodels.ReactionEventAdded {
+		err = s.repo.AddReaction(communityID, targetType, targetID, userID, emoji, time.Now())
+	} else {
+		err = s.repo.RemoveReaction(communityID, targetType, targetID, userID, emoji)
+	}
+	if err != nil {
+		logger.Log.Error("Failed to change reaction",
+			zap.String("target_type", targetType),
+			zap.String("target_id", targetID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	reactions, err := s.targetReactions(communityID, targetType, targetID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := forummodels.ReactionEvent{
+		Type:       eventType,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Emoji:      emoji,
+		UserID:     userID,
+		Reactions:  withoutViewer(reactions),
+	}
+	if target.chat {
+		s.broadcastChan <- chatBroadcast{communityID: communityID, message: event}
+	} else {
+		s.broadcastChan <- chatBroadcast{communityID: communityID, topicID: target.topicID, message: event}
+	}
+	return reactions, nil
+}
+
+// reactionTarget описывает, куда рассылать изменения реакций объекта
+type reactionTarget struct {
+	chat    bool
+	topicID string
+}
+
+// reactionTarget проверяет, что объект реакции существует
+func (s *forumServiceImpl) reactionTarget(communityID, targetType, targetID string) (reactionTarget, error) {
+	switch targetType {
+	case forummodels.ReactionTargetTopic:
+		if _, err := s.GetTopic(communityID, targetID); err != nil {
+			return reactionTarget{}, err
+		}
+		return reactionTarget{topicID: targetID}, nil
+	case forummodels.ReactionTargetMessage:
+		message, err := s.getMessage(communityID, targetID)
+		if err != nil {
+			return reactionTarget{}, err
+		}
+		return reactionTarget{chat: message.IsChat, topicID: message.TopicID}, nil
+	default:
+		return reactionTarget{}, ErrInvalidReaction
+	}
+}
+
+func (s *forumServiceImpl) targetReactions(communityID, targetType, targetID, viewerID string) ([]*forummodels.ReactionCount, error) {
+	reactions, err := s.repo.GetReactions(communityID, targetType, []string{targetID}, viewerID)
+	if err != nil {
+		logger.Log.Error("Failed to get reactions",
+			zap.String("target_type", targetType),
+			zap.String("target_id", targetID),
+			zap.Error(err))
+		return nil, err
+	}
+	if reactions[targetID] == nil {
+		return []*forummodels.ReactionCount{}, nil
+	}
+	return reactions[targetID], nil
+}
+
+// attachReactions заполняет счетчики реакций у страницы сообщений
+func (s *forumServiceImpl) attachReactions(communityID, viewerID string, messages []*forummodels.MessageDetail) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+
+	reactions, err := s.repo.GetReactions(communityID, forummodels.ReactionTargetMessage, ids, viewerID)
+	if err != nil {
+		logger.Log.Error("Failed to get message reactions",
+			zap.String("community_id", communityID),
+			zap.Error(err))
+		return err
+	}
+	for _, message := range messages {
+		message.Reactions = reactions[message.ID]
+	}
+	return nil
+}
+
+// withoutViewer копирует счетчики без признака reacted_by_me для рассылки всем клиентам
+func withoutViewer(reactions []*forummodels.ReactionCount) []*forummodels.ReactionCount {
+	shared := make([]*forummodels.ReactionCount, 0, len(reactions))
+	for _, reaction := range reactions {
+		shared = append(shared, &forummodels.ReactionCount{Emoji: reaction.Emoji, Count: reaction.Count})
+	}
+	return shared
+}
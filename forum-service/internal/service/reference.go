@@ -0,0 +1,308 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/luckermt/forum-app/forum-service/internal/markdown"
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/repository"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxReferencesPerPost сколько разных цитат и ссылок учитывается в одном тексте
+	maxReferencesPerPost = 20
+	// maxBacklinks сколько последних обратных ссылок показывается у темы
+	maxBacklinks = 100
+)
+
+// linkPattern внутренняя ссылка на тему или сообщение: полный адрес, путь с префиксом
+// сообщества /c/slug или просто /topics/<id>
+var linkPattern = regexp.MustCompile(`(?:https?://[^\s/<>()\[\]]+)?(?:/c/[\w-]+)?/(topics|messages)/` +
+	`([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})\b`)
+
+// parsedReference цитата или ссылка, найденная в тексте; start и end — байтовые смещения,
+// body — текст цитаты
+type parsedReference struct {
+	kind       string
+	targetType string
+	targetID   string
+	start      int
+	end        int
+	body       string
+}
+
+func (p parsedReference) key() string {
+	return p.kind + ":" + p.targetType + ":" + p.targetID
+}
+
+func referenceKey(ref *forummodels.Reference) string {
+	return ref.Kind + ":" + ref.TargetType + ":" + ref.TargetID
+}
+
+// parseReferences находит в тексте цитаты и внутренние ссылки в порядке появления.
+// Ссылки внутри цитат относятся к цитируемому тексту и не учитываются.
+func parseReferences(content string) []parsedReference {
+	quotes := markdown.Quotes(content)
+	refs := make([]parsedReference, 0, len(quotes))
+	for _, quote := range quotes {
+		refs = append(refs, parsedReference{
+			kind:       forummodels.ReferenceQuote,
+			targetType: forummodels.ReferenceMessage,
+			targetID:   strings.ToLower(quote.MessageID),
+			start:      quote.Start,
+			end:        quote.End,
+			body:       quote.Body,
+		})
+	}
+
+	for _, loc := range linkPattern.FindAllStringSubmatchIndex(content, -1) {
+		inQuote := false
+		for _, quote := range quotes {
+			if loc[0] >= quote.Start && loc[0] < quote.End {
+				inQuote = true
+				break
+			}
+		}
+		if inQuote {
+			continue
+		}
+		targetType := forummodels.ReferenceTopic
+		if content[loc[2]:loc[3]] == "messages" {
+			targetType = forummodels.ReferenceMessage
+		}
+		refs = append(refs, parsedReference{
+			kind:       forummodels.ReferenceLink,
+			targetType: targetType,
+			targetID:   strings.ToLower(content[loc[4]:loc[5]]),
+			start:      loc[0],
+			end:        loc[1],
+		})
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool { return refs[i].start < refs[j].start })
+	return refs
+}
+
+// syncReferences сохраняет цитаты и ссылки из текста темы или сообщения. Уже сохраненные
+// цитаты не пересчитываются, поэтому подпись остается прежней, даже если оригинал потом
+// правили или удалили. Ошибки только логируются: текст к этому моменту уже сохранен.
+func (s *forumServiceImpl) syncReferences(communityID, sourceType, sourceID, sourceTopicID, content string, isNew bool) []*forummodels.Reference {
+	parsed := parseReferences(content)
+	if len(parsed) == 0 && isNew {
+		return nil
+	}
+
+	previous := make(map[string]*forummodels.Reference)
+	if !isNew {
+		stored, err := s.repo.GetReferences(communityID, sourceType, []string{sourceID})
+		if err != nil {
+			logger.Log.Error("Failed to get references",
+				zap.String("source_type", sourceType),
+				zap.String("source_id", sourceID),
+				zap.Error(err))
+			return nil
+		}
+		for _, ref := range stored[sourceID] {
+			previous[referenceKey(ref)] = ref
+		}
+	}
+
+	var refs []*forummodels.Reference
+	seen := make(map[string]bool)
+	for _, p := range parsed {
+		if seen[p.key()] || (p.targetType == sourceType && p.targetID == sourceID) {
+			continue
+		}
+		seen[p.key()] = true
+
+		ref, ok := previous[p.key()]
+		if !ok {
+			var err error
+			if ref, err = s.resolveReference(communityID, p); err != nil {
+				logger.Log.Error("Failed to resolve reference",
+					zap.String("target_type", p.targetType),
+					zap.String("target_id", p.targetID),
+					zap.Error(err))
+				return nil
+			}
+		}
+		if ref == nil {
+			continue
+		}
+		refs = append(refs, ref)
+		if len(refs) == maxReferencesPerPost {
+			break
+		}
+	}
+
+	if err := s.repo.ReplaceReferences(communityID, sourceType, sourceID, sourceTopicID, refs, time.Now()); err != nil {
+		logger.Log.Error("Failed to save references",
+			zap.String("source_type", sourceType),
+			zap.String("source_id", sourceID),
+			zap.Error(err))
+		return nil
+	}
+	return refs
+}
+
+// resolveReference находит тему или сообщение, на которое ссылается текст.
+// Ссылки на несуществующие и удаленные объекты пропускаются, как и цитаты с текстом,
+// которого нет в оригинале: иначе чужому автору можно приписать любые слова.
+func (s *forumServiceImpl) resolveReference(communityID string, p parsedReference) (*forummodels.Reference, error) {
+	ref := &forummodels.Reference{Kind: p.kind, TargetType: p.targetType, TargetID: p.targetID}
+	if p.targetType == forummodels.ReferenceTopic {
+		topic, err := s.repo.GetTopic(communityID, p.targetID)
+		if errors.Is(err, repository.ErrTopicNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		ref.TargetTopicID, ref.TargetAuthorID = topic.ID, topic.UserID
+		return ref, nil
+	}
+
+	message, err := s.repo.GetMessage(communityID, p.targetID)
+	if errors.Is(err, repository.ErrMessageNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if message.Deleted {
+		return nil, nil
+	}
+	if p.kind == forummodels.ReferenceQuote && !quotedFrom(message.Content, p.body) {
+		return nil, nil
+	}
+	ref.TargetTopicID, ref.TargetAuthorID = message.TopicID, message.UserID
+	return ref, nil
+}
+
+// quotedFrom проверяет, что текст цитаты взят из оригинала. Пробелы и переводы строк
+// сравниваются без учета количества, чтобы цитату можно было переносить по-другому.
+func quotedFrom(original, quote string) bool {
+	return strings.Contains(strings.Join(strings.Fields(original), " "), strings.Join(strings.Fields(quote), " "))
+}
+
+// referenceEntities размечает в тексте сохраненные цитаты и ссылки
+func referenceEntities(content string, refs []*forummodels.Reference) []*forummodels.MessageEntity {
+	if len(refs) == 0 {
+		return nil
+	}
+	byKey := make(map[string]*forummodels.Reference, len(refs))
+	for _, ref := range refs {
+		byKey[referenceKey(ref)] = ref
+	}
+
+	var entities []*forummodels.MessageEntity
+	for _, p := range parseReferences(content) {
+		ref, ok := byKey[p.key()]
+		if !ok {
+			continue
+		}
+		entity := &forummodels.MessageEntity{
+			Type:    forummodels.EntityLink,
+			Offset:  utf8.RuneCountInString(content[:p.start]),
+			Length:  utf8.RuneCountInString(content[p.start:p.end]),
+			UserID:  ref.TargetAuthorID,
+			TopicID: ref.TargetTopicID,
+		}
+		if ref.Kind == forummodels.ReferenceQuote {
+			entity.Type = forummodels.EntityQuote
+		}
+		if ref.TargetType == forummodels.ReferenceMessage {
+			entity.MessageID = ref.TargetID
+		}
+		entities = append(entities, entity)
+	}
+	return entities
+}
+
+// mergeEntities объединяет разметку и упорядочивает ее по положению в тексте
+func mergeEntities(entities, more []*forummodels.MessageEntity) []*forummodels.MessageEntity {
+	if len(more) == 0 {
+		return entities
+	}
+	entities = append(entities, more...)
+	sort.SliceStable(entities, func(i, j int) bool { return entities[i].Offset < entities[j].Offset })
+	return entities
+}
+
+// syncMessageReferences сохраняет цитаты и ссылки сообщения и добавляет их в message.Entities
+func (s *forumServiceImpl) syncMessageReferences(communityID string, message *forummodels.MessageDetail, isNew bool) {
+	refs := s.syncReferences(communityID, forummodels.ReferenceMessage, message.ID, message.TopicID, message.Content, isNew)
+	message.Entities = mergeEntities(message.Entities, referenceEntities(message.Content, refs))
+}
+
+// attachReferences добавляет цитаты и ссылки в разметку страницы сообщений
+func (s *forumServiceImpl) attachReferences(communityID string, messages []*forummodels.MessageDetail) error {
+	ids := make([]string, 0, len(messages))
+	for _, message := range messages {
+		if !message.Deleted {
+			ids = append(ids, message.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	refs, err := s.repo.GetReferences(communityID, forummodels.ReferenceMessage, ids)
+	if err != nil {
+		logger.Log.Error("Failed to get message references",
+			zap.String("community_id", communityID),
+			zap.Error(err))
+		return err
+	}
+	for _, message := range messages {
+		message.Entities = mergeEntities(message.Entities, referenceEntities(message.Content, refs[message.ID]))
+	}
+	return nil
+}
+
+// attachTopicReferences размечает цитаты и ссылки в тексте темы
+func (s *forumServiceImpl) attachTopicReferences(communityID string, topic *forummodels.TopicDetail) error {
+	refs, err := s.repo.GetReferences(communityID, forummodels.ReferenceTopic, []string{topic.ID})
+	if err != nil {
+		logger.Log.Error("Failed to get topic references",
+			zap.String("topic_id", topic.ID),
+			zap.Error(err))
+		return err
+	}
+	topic.Entities = referenceEntities(topic.Content, refs[topic.ID])
+	return nil
+}
+
+// GetTopicBacklinks возвращает места, где процитированы или упомянуты тема и ее сообщения.
+// Каждое место показывается один раз, даже если оно ссылается на тему несколько раз.
+func (s *forumServiceImpl) GetTopicBacklinks(communityID, topicID string) ([]*forummodels.Backlink, error) {
+	if _, err := s.GetTopic(communityID, topicID); err != nil {
+		return nil, err
+	}
+
+	backlinks, err := s.repo.GetBacklinks(communityID, topicID, maxBacklinks)
+	if err != nil {
+		logger.Log.Error("Failed to get backlinks",
+			zap.String("topic_id", topicID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	unique := make([]*forummodels.Backlink, 0, len(backlinks))
+	seen := make(map[string]bool)
+	for _, backlink := range backlinks {
+		key := backlink.SourceType + ":" + backlink.SourceID
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, backlink)
+		}
+	}
+	return unique, nil
+}
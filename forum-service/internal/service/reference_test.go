@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	refTopicID   = "6f1c2a1e-0b7d-4c1a-9a55-0d1e2f3a4b5c"
+	refMessageID = "9a8b7c6d-1e2f-4a3b-8c9d-0e1f2a3b4c5d"
+)
+
+func TestParseReferences(t *testing.T) {
+	content := "См. https://forum.example.com/c/dev/topics/" + refTopicID + " и\n" +
+		"[quote=" + refMessageID + "]\nсмотри /topics/" + refTopicID + "\n[/quote]\n" +
+		"[сообщение](/messages/" + refMessageID + ")"
+
+	refs := parseReferences(content)
+
+	require.Len(t, refs, 3)
+	assert.Equal(t, forummodels.ReferenceLink, refs[0].kind)
+	assert.Equal(t, forummodels.ReferenceTopic, refs[0].targetType)
+	assert.Equal(t, "https://forum.example.com/c/dev/topics/"+refTopicID, content[refs[0].start:refs[0].end])
+	assert.Equal(t, forummodels.ReferenceQuote, refs[1].kind)
+	assert.Equal(t, refMessageID, refs[1].targetID)
+	assert.Equal(t, "смотри /topics/"+refTopicID, refs[1].body)
+	assert.Equal(t, forummodels.ReferenceLink, refs[2].kind)
+	assert.Equal(t, forummodels.ReferenceMessage, refs[2].targetType)
+}
+
+func TestQuotedFrom(t *testing.T) {
+	original := "Первая строка.\r\nВторая  строка с деталями."
+
+	assert.True(t, quotedFrom(original, "Вторая строка"))
+	assert.True(t, quotedFrom(original, "строка.\nВторая"))
+	assert.True(t, quotedFrom(original, ""))
+	assert.False(t, quotedFrom(original, "Третья строка"))
+	assert.False(t, quotedFrom(original, "Первая строка. Я согласен"))
+}
+
+func TestReferenceEntities(t *testing.T) {
+	content := "Ответ:\n[quote=" + refMessageID + "]\nтекст\n[/quote]\nи /topics/" + refTopicID
+	refs := []*forummodels.Reference{{
+		Kind:           forummodels.ReferenceQuote,
+		TargetType:     forummodels.ReferenceMessage,
+		TargetID:       refMessageID,
+		TargetTopicID:  refTopicID,
+		TargetAuthorID: "author",
+	}}
+
+	entities := referenceEntities(content, refs)
+
+	require.Len(t, entities, 1)
+	assert.Equal(t, forummodels.EntityQuote, entities[0].Type)
+	assert.Equal(t, 7, entities[0].Offset)
+	assert.Equal(t, "author", entities[0].UserID)
+	assert.Equal(t, refMessageID, entities[0].MessageID)
+	assert.Equal(t, refTopicID, entities[0].TopicID)
+}
+
+func TestMergeEntities(t *testing.T) {
+	mentions := []*forummodels.MessageEntity{{Type: forummodels.EntityMention, Offset: 10}}
+	links := []*forummodels.MessageEntity{{Type: forummodels.EntityLink, Offset: 2}}
+
+	merged := mergeEntities(mentions, links)
+
+	require.Len(t, merged, 2)
+	assert.Equal(t, forummodels.EntityLink, merged[0].Type)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxSearchQueryLength ограничивает длину поискового запроса в символах
+const maxSearchQueryLength = 200
+
+func (s *forumServiceImpl) Search(communityID string, q forummodels.SearchQuery) ([]*forummodels.SearchResult, error) {
+	q.Query = strings.TrimSpace(q.Query)
+	if q.Query == "" {
+		return nil, fmt.Errorf("%w: query is required", ErrInvalidSearch)
+	}
+	if len([]rune(q.Query)) > maxSearchQueryLength {
+		return nil, fmt.Errorf("%w: query must be at most %d characters long", ErrInvalidSearch, maxSearchQueryLength)
+	}
+
+	switch q.Type {
+	case "":
+		q.Type = forummodels.SearchTypeAll
+	case forummodels.SearchTypeAll, forummodels.SearchTypeTopics, forummodels.SearchTypeMessages:
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidSearch, q.Type)
+	}
+
+	switch q.Language {
+	case "", forummodels.SearchConfigSimple, forummodels.SearchConfigEnglish, forummodels.SearchConfigRussian:
+	default:
+		return nil, fmt.Errorf("%w: unsupported language %q", ErrInvalidSearch, q.Language)
+	}
+
+	if q.From != nil && q.To != nil && !q.From.Before(*q.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidSearch)
+	}
+
+	switch {
+	case q.Limit <= 0:
+		q.Limit = forummodels.DefaultPageSize
+	case q.Limit > forummodels.MaxPageSize:
+		q.Limit = forummodels.MaxPageSize
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+
+	results, err := s.repo.Search(communityID, q)
+	if err != nil {
+		logger.Log.Error("Failed to search",
+			zap.String("community_id", communityID),
+			zap.String("query", q.Query),
+			zap.Error(err))
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/repository"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// maxTagSuggestions ограничивает количество подсказок автодополнения
+const maxTagSuggestions = 50
+
+// normalizeTag приводит тег к каноническому виду: нижний регистр, без ведущего #,
+// пробелы и подчеркивания заменены дефисами. Допустимы буквы, цифры и символы + # . -
+func normalizeTag(raw string) (string, error) {
+	tag := strings.ToLower(strings.TrimSpace(raw))
+	tag = strings.TrimLeft(tag, "#")
+
+	var b strings.Builder
+	lastDash := false
+	for _, r := range tag {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' || r == '#' || r == '.':
+			b.WriteRune(r)
+			lastDash = false
+		case r == '-' || r == '_' || unicode.IsSpace(r):
+			if !lastDash && b.Len() > 0 {
+				b.WriteRune('-')
+				lastDash = true
+			}
+		default:
+			return "", fmt.Errorf("%w: %q contains unsupported characters", ErrInvalidTag, raw)
+		}
+	}
+
+	tag = strings.Trim(b.String(), "-.")
+	if tag == "" {
+		return "", fmt.Errorf("%w: %q is empty", ErrInvalidTag, raw)
+	}
+	if utf8.RuneCountInString(tag) > forummodels.MaxTagLength {
+		return "", fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidTag, raw, forummodels.MaxTagLength)
+	}
+	return tag, nil
+}
+
+// normalizeTags нормализует список тегов и убирает повторы
+func normalizeTags(raw []string) ([]string, error) {
+	tags := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, r := range raw {
+		tag, err := normalizeTag(r)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// canonicalTags заменяет синонимы основными тегами. Если strict, неизвестные
+// и неофициальные теги считаются недопустимыми.
+func (s *forumServiceImpl) canonicalTags(communityID string, tags []string, strict bool) ([]string, error) {
+	if len(tags) == 0 {
+		return tags, nil
+	}
+
+	known, err := s.repo.GetTags(communityID, tags)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*forummodels.Tag, len(known))
+	for _, tag := range known {
+		byName[tag.Name] = tag
+	}
+
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, name := range tags {
+		tag, ok := byName[name]
+		switch {
+		case ok && tag.SynonymOf != "":
+			// Синонимы заводит администратор, поэтому они допустимы и в ограниченном списке
+			name = tag.SynonymOf
+		case strict && (!ok || !tag.Official):
+			return nil, fmt.Errorf("%w: %s", ErrTagNotAllowed, name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+// resolveTopicTags проверяет теги новой темы по правилам сообщества
+func (s *forumServiceImpl) resolveTopicTags(communityID string, raw []string) ([]string, error) {
+	tags, err := normalizeTags(raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) == 0 {
+		return tags, nil
+	}
+
+	settings, err := s.repo.GetTagSettings(communityID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err = s.canonicalTags(communityID, tags, settings.Restricted)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) > settings.MaxTags {
+		return nil, fmt.Errorf("%w: at most %d tags per topic", ErrTooManyTags, settings.MaxTags)
+	}
+	return tags, nil
+}
+
+func (s *forumServiceImpl) GetTagSettings(communityID string) (*forummodels.TagSettings, error) {
+	return s.repo.GetTagSettings(communityID)
+}
+
+func (s *forumServiceImpl) SetTagSettings(communityID, userID string, settings forummodels.TagSettings) error {
+	if err := s.requireCommunityAdmin(communityID, userID); err != nil {
+		return err
+	}
+	if settings.MaxTags < 1 || settings.MaxTags > forummodels.MaxTagsPerTopic {
+		return ErrInvalidTagSettings
+	}
+	return s.repo.SetTagSettings(communityID, settings)
+}
+
+func (s *forumServiceImpl) CreateOfficialTag(communityID, userID, name string) (*forummodels.Tag, error) {
+	if err := s.requireCommunityAdmin(communityID, userID); err != nil {
+		return nil, err
+	}
+	tag, err := normalizeTag(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateOfficialTag(communityID, tag); err != nil {
+		logger.Log.Error("Failed to create tag",
+			zap.String("community_id", communityID),
+			zap.String("tag", tag),
+			zap.Error(err))
+		return nil, err
+	}
+	return &forummodels.Tag{Name: tag, Official: true}, nil
+}
+
+func (s *forumServiceImpl) DeleteTag(communityID, userID, name string) error {
+	if err := s.requireCommunityAdmin(communityID, userID); err != nil {
+		return err
+	}
+	tag, err := normalizeTag(name)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteTag(communityID, tag); err != nil {
+		if errors.Is(err, repository.ErrTagNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// MergeTag делает тег from синонимом тега into и переносит на into все темы
+func (s *forumServiceImpl) MergeTag(communityID, userID, from, into string) error {
+	if err := s.requireCommunityAdmin(communityID, userID); err != nil {
+		return err
+	}
+	tags, err := normalizeTags([]string{from, into})
+	if err != nil {
+		return err
+	}
+	if len(tags) < 2 {
+		return fmt.Errorf("%w: cannot merge a tag into itself", ErrInvalidTag)
+	}
+
+	// Целевой тег сам может оказаться синонимом
+	target, err := s.canonicalTags(communityID, tags[1:], false)
+	if err != nil {
+		return err
+	}
+	if target[0] == tags[0] {
+		return fmt.Errorf("%w: %s is already a synonym of %s", ErrInvalidTag, tags[1], tags[0])
+	}
+
+	if err := s.repo.MergeTag(communityID, tags[0], target[0]); err != nil {
+		logger.Log.Error("Failed to merge tags",
+			zap.String("community_id", communityID),
+			zap.String("from", tags[0]),
+			zap.String("into", target[0]),
+			zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// SearchTags возвращает подсказки тегов по префиксу с количеством тем.
+// Пустой префикс возвращает самые популярные теги.
+func (s *forumServiceImpl) SearchTags(communityID, prefix string, limit int) ([]*forummodels.Tag, error) {
+	if prefix != "" {
+		var err error
+		if prefix, err = normalizeTag(prefix); err != nil {
+			return nil, err
+		}
+	}
+	if limit <= 0 || limit > maxTagSuggestions {
+		limit = maxTagSuggestions
+	}
+
+	tags, err := s.repo.SearchTags(communityID, prefix, limit)
+	if err != nil {
+		logger.Log.Error("Failed to search tags",
+			zap.String("community_id", communityID),
+			zap.Error(err))
+		return nil, err
+	}
+	return tags, nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	cases := map[string]string{
+		"Go":                   "go",
+		"#gRPC":                "grpc",
+		"  Machine  Learning ": "machine-learning",
+		"c++":                  "c++",
+		"node.js":              "node.js",
+		"snake_case":           "snake-case",
+		"Базы данных":          "базы-данных",
+	}
+	for raw, want := range cases {
+		got, err := normalizeTag(raw)
+		assert.NoError(t, err, raw)
+		assert.Equal(t, want, got, raw)
+	}
+
+	for _, raw := range []string{"", "###", "go/grpc", "<script>", strings.Repeat("a", 33)} {
+		_, err := normalizeTag(raw)
+		assert.ErrorIs(t, err, ErrInvalidTag, raw)
+	}
+}
+
+func TestNormalizeTags_Deduplicates(t *testing.T) {
+	tags, err := normalizeTags([]string{"Go", "go", "#GO", "grpc"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"go", "grpc"}, tags)
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/repository"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"github.com/luckermt/forum-app/shared/pkg/models"
+	"go.uber.org/zap"
+)
+
+// CreateTopicMessage публикует ответ пользователя в теме, при необходимости — ответ на другое сообщение
+func (s *forumServiceImpl) CreateTopicMessage(communityID, topicID, userID string, req forummodels.MessageRequest) (*forummodels.ThreadMessage, error) {
+	if err := s.requireMember(communityID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.requireWritableTopic(communityID, topicID); err != nil {
+		return nil, err
+	}
+	attachments, err := s.resolveAttachments(communityID, userID, req.AttachmentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &forummodels.ThreadMessage{
+		MessageDetail: forummodels.MessageDetail{
+			Message: models.Message{
+				ID:        generateID(),
+				TopicID:   topicID,
+				UserID:    userID,
+				Content:   req.Content,
+				CreatedAt: time.Now(),
+			},
+			ContentHTML: s.markdown.Render(req.Content),
+			Attachments: attachments,
+		},
+		ParentID: req.ParentID,
+	}
+
+	var parentAuthorID string
+	if req.ParentID != "" {
+		parent, err := s.repo.GetMessage(communityID, req.ParentID)
+		if errors.Is(err, repository.ErrMessageNotFound) {
+			return nil, ErrInvalidParent
+		}
+		if err != nil {
+			return nil, err
+		}
+		if parent.TopicID != topicID || parent.Deleted {
+			return nil, ErrInvalidParent
+		}
+		if parent.Depth+1 > forummodels.MaxThreadDepth {
+			return nil, ErrThreadTooDeep
+		}
+		message.Depth = parent.Depth + 1
+		parentAuthorID = parent.UserID
+	}
+
+	err = s.repo.CreateThreadMessage(communityID, message)
+	if errors.Is(err, repository.ErrMessageNotFound) {
+		return nil, ErrInvalidParent
+	}
+	if err != nil {
+		logger.Log.Error("Failed to create topic message",
+			zap.String("community_id", communityID),
+			zap.String("topic_id", topicID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	mentions := s.syncMentions(communityID, &message.MessageDetail, true)
+	s.syncMessageReferences(communityID, &message.MessageDetail, true)
+	s.notifyReply(communityID, &message.MessageDetail, parentAuthorID, mentions)
+	s.broadcastChan <- chatBroadcast{
+		communityID: communityID,
+		topicID:     topicID,
+		message:     forummodels.ThreadMessageEvent{Type: forummodels.MessageEventCreated, Message: message},
+	}
+	return message, nil
+}
+
+// GetThread возвращает страницу ветки обсуждения в виде дерева или плоского списка с глубиной
+func (s *forumServiceImpl) GetThread(communityID, topicID string, params forummodels.ThreadParams) (*forummodels.ThreadPage, error) {
+	query, err := pageQuery(params.PageParams, forummodels.SortOldest,
+		forummodels.SortOldest, forummodels.SortNewest, forummodels.SortTop)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := params.Levels
+	if levels == 0 {
+		levels = forummodels.DefaultThreadLevels
+	}
+	if levels < 0 || levels > forummodels.MaxThreadDepth+1 {
+		return nil, ErrInvalidThread
+	}
+	switch params.Format {
+	case "", forummodels.ThreadFormatTree, forummodels.ThreadFormatFlat:
+	default:
+		return nil, ErrInvalidThread
+	}
+
+	if _, err := s.GetTopic(communityID, topicID); err != nil {
+		return nil, err
+	}
+
+	// Глубина верхнего уровня выборки: 0 для корневых сообщений или глубина родителя + 1
+	rootDepth := 0
+	if params.ParentID != "" {
+		parent, err := s.repo.GetMessage(communityID, params.ParentID)
+		if errors.Is(err, repository.ErrMessageNotFound) {
+			return nil, ErrNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		if parent.TopicID != topicID {
+			return nil, ErrNotFound
+		}
+		rootDepth = parent.Depth + 1
+	}
+
+	rows, err := s.repo.GetThread(communityID, topicID, params.ParentID, rootDepth+levels-1, query)
+	if err != nil {
+		logger.Log.Error("Failed to get thread",
+			zap.String("community_id", communityID),
+			zap.String("topic_id", topicID),
+			zap.String("parent_id", params.ParentID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	details := make([]*forummodels.MessageDetail, 0, len(rows))
+	for _, row := range rows {
+		details = append(details, &row.MessageDetail)
+	}
+	s.fillMessageHTML(details)
+	if err := s.attachMentions(communityID, details); err != nil {
+		return nil, err
+	}
+	if err := s.attachReferences(communityID, details); err != nil {
+		return nil, err
+	}
+	if err := s.attachMessageAttachments(communityID, details); err != nil {
+		return nil, err
+	}
+
+	roots := buildThread(rows, params.ParentID, query.Sort)
+	page := &forummodels.ThreadPage{Items: roots}
+	if len(roots) == query.Limit {
+		page.Items = roots[:len(roots)-1]
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = encodeCursor(forummodels.PageCursor{
+			Sort:      query.Sort,
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+			Score:     last.Score,
+		})
+	}
+
+	if params.Format == forummodels.ThreadFormatFlat {
+		page.Items = flattenThread(page.Items)
+	}
+	return page, nil
+}
+
+// buildThread собирает дерево из сообщений выборки. Верхний уровень — ответы на parentID
+// в порядке страницы, вложенные ответы всегда идут в хронологическом порядке.
+func buildThread(rows []*forummodels.ThreadMessage, parentID, sortMode string) []*forummodels.ThreadMessage {
+	byID := make(map[string]*forummodels.ThreadMessage, len(rows))
+	for _, message := range rows {
+		message.Replies = nil
+		byID[message.ID] = message
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return chronological(rows[i], rows[j])
+	})
+
+	var roots []*forummodels.ThreadMessage
+	for _, message := range rows {
+		if message.ParentID == parentID {
+			roots = append(roots, message)
+			continue
+		}
+		if parent, ok := byID[message.ParentID]; ok {
+			parent.Replies = append(parent.Replies, message)
+		}
+	}
+
+	switch sortMode {
+	case forummodels.SortNewest:
+		for i, j := 0, len(roots)-1; i < j; i, j = i+1, j-1 {
+			roots[i], roots[j] = roots[j], roots[i]
+		}
+	case forummodels.SortTop:
+		// Тот же порядок, что и в запросе: рейтинг, затем новые сверху
+		sort.Slice(roots, func(i, j int) bool {
+			if roots[i].Score != roots[j].Score {
+				return roots[i].Score > roots[j].Score
+			}
+			return chronological(roots[j], roots[i])
+		})
+	}
+	return roots
+}
+
+// flattenThread разворачивает дерево в список в порядке обхода; вложенность видна по Depth
+func flattenThread(roots []*forummodels.ThreadMessage) []*forummodels.ThreadMessage {
+	var flat []*forummodels.ThreadMessage
+	var walk func([]*forummodels.ThreadMessage)
+	walk = func(messages []*forummodels.ThreadMessage) {
+		for _, message := range messages {
+			replies := message.Replies
+			message.Replies = nil
+			flat = append(flat, message)
+			walk(replies)
+		}
+	}
+	walk(roots)
+	return flat
+}
+
+func chronological(a, b *forummodels.ThreadMessage) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/shared/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func threadMessage(id, parentID string, depth, minute int) *forummodels.ThreadMessage {
+	return &forummodels.ThreadMessage{
+		MessageDetail: forummodels.MessageDetail{
+			Message: models.Message{
+				ID:        id,
+				CreatedAt: time.Date(2024, 1, 1, 0, minute, 0, 0, time.UTC),
+			},
+		},
+		ParentID: parentID,
+		Depth:    depth,
+	}
+}
+
+func TestBuildThread(t *testing.T) {
+	rows := []*forummodels.ThreadMessage{
+		threadMessage("c", "a", 1, 5),
+		threadMessage("a", "", 0, 1),
+		threadMessage("b", "", 0, 2),
+		threadMessage("d", "c", 2, 6),
+		threadMessage("e", "a", 1, 3),
+	}
+
+	roots := buildThread(rows, "", forummodels.SortNewest)
+
+	assert.Equal(t, []string{"b", "a"}, threadIDs(roots))
+	assert.Equal(t, []string{"e", "c"}, threadIDs(roots[1].Replies))
+	assert.Equal(t, []string{"d"}, threadIDs(roots[1].Replies[1].Replies))
+
+	flat := flattenThread(roots)
+	assert.Equal(t, []string{"b", "a", "e", "c", "d"}, threadIDs(flat))
+	assert.Nil(t, flat[1].Replies)
+	assert.Equal(t, 2, flat[4].Depth)
+}
+
+func TestBuildThread_Subtree(t *testing.T) {
+	rows := []*forummodels.ThreadMessage{
+		threadMessage("x", "parent", 3, 2),
+		threadMessage("y", "parent", 3, 1),
+		threadMessage("z", "x", 4, 3),
+	}
+
+	roots := buildThread(rows, "parent", forummodels.SortOldest)
+
+	assert.Equal(t, []string{"y", "x"}, threadIDs(roots))
+	assert.Equal(t, []string{"z"}, threadIDs(roots[1].Replies))
+}
+
+func TestBuildThread_Top(t *testing.T) {
+	low := threadMessage("low", "", 0, 1)
+	high := threadMessage("high", "", 0, 2)
+	high.Score = 5
+	tie := threadMessage("tie", "", 0, 3)
+	tie.Score = 5
+
+	roots := buildThread([]*forummodels.ThreadMessage{low, high, tie}, "", forummodels.SortTop)
+
+	assert.Equal(t, []string{"tie", "high", "low"}, threadIDs(roots))
+}
+
+func threadIDs(messages []*forummodels.ThreadMessage) []string {
+	ids := make([]string, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+	return ids
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/repository"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func (s *forumServiceImpl) GetTopic(communityID, topicID string) (*forummodels.TopicDetail, error) {
+	topic, err := s.repo.GetTopic(communityID, topicID)
+	if errors.Is(err, repository.ErrTopicNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		logger.Log.Error("Failed to get topic",
+			zap.String("community_id", communityID),
+			zap.String("topic_id", topicID),
+			zap.Error(err))
+		return nil, err
+	}
+	topic.ContentHTML = s.contentHTML(topic.Content, topic.ContentHTML)
+	if err := s.attachTopicAttachments(communityID, topic); err != nil {
+		return nil, err
+	}
+	if topic.Poll, err = s.topicPoll(communityID, topicID); err != nil {
+		return nil, err
+	}
+	if err := s.attachTopicReferences(communityID, topic); err != nil {
+		return nil, err
+	}
+	return topic, nil
+}
+
+// UpdateTopic редактирует тему. Править может автор и модераторы сообщества,
+// предыдущая версия остается в истории ревизий.
+func (s *forumServiceImpl) UpdateTopic(communityID, topicID, userID string, req forummodels.TopicUpdateRequest) (*forummodels.TopicDetail, error) {
+	topic, err := s.GetTopic(communityID, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.requireEditor(communityID, userID, topic.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if topic.Archived {
+		return nil, ErrTopicArchived
+	}
+	if topic.Locked && !roleAtLeast(role, "moderator") {
+		return nil, ErrTopicLocked
+	}
+
+	title, content := topic.Title, topic.Content
+	if req.Title != nil {
+		title = *req.Title
+	}
+	if req.Content != nil {
+		content = *req.Content
+	}
+	if title == topic.Title && content == topic.Content {
+		return topic, nil
+	}
+
+	updated, err := s.repo.UpdateTopic(communityID, topicID, title, content, s.markdown.Render(content), userID, time.Now())
+	if errors.Is(err, repository.ErrTopicNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		logger.Log.Error("Failed to update topic",
+			zap.String("topic_id", topicID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+	s.notifyModeration(communityID, userID, topic.UserID, forummodels.ModerationTopicEdited, topicID, "")
+	updated.Attachments = topic.Attachments
+	updated.Poll = topic.Poll
+	refs := s.syncReferences(communityID, forummodels.ReferenceTopic, topicID, topicID, content, false)
+	updated.Entities = referenceEntities(content, refs)
+	return updated, nil
+}
+
+func (s *forumServiceImpl) GetTopicRevisions(communityID, topicID string) ([]*forummodels.TopicRevision, error) {
+	revisions, err := s.repo.GetTopicRevisions(communityID, topicID)
+	if err != nil {
+		logger.Log.Error("Failed to get topic revisions",
+			zap.String("topic_id", topicID),
+			zap.Error(err))
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, ErrNotFound
+	}
+	return revisions, nil
+}
+
+func (s *forumServiceImpl) DiffTopicRevisions(communityID, topicID string, from, to int) (*forummodels.TopicDiff, error) {
+	fromRev, err := s.getTopicRevision(communityID, topicID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := s.getTopicRevision(communityID, topicID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	title, err := DiffLines(fromRev.Title, toRev.Title)
+	if err != nil {
+		return nil, err
+	}
+	content, err := DiffLines(fromRev.Content, toRev.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &forummodels.TopicDiff{
+		TopicID: topicID,
+		From:    from,
+		To:      to,
+		Title:   title,
+		Content: content,
+	}, nil
+}
+
+func (s *forumServiceImpl) getTopicRevision(communityID, topicID string, revision int) (*forummodels.TopicRevision, error) {
+	rev, err := s.repo.GetTopicRevision(communityID, topicID, revision)
+	if errors.Is(err, repository.ErrRevisionNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		logger.Log.Error("Failed to get topic revision",
+			zap.String("topic_id", topicID),
+			zap.Int("revision", revision),
+			zap.Error(err))
+		return nil, err
+	}
+	return rev, nil
+}
+
+// SetTopicState закрепляет, закрывает или архивирует тему (только модераторы)
+func (s *forumServiceImpl) SetTopicState(communityID, topicID, userID string, req forummodels.TopicStateRequest) (*forummodels.TopicDetail, error) {
+	role, err := s.memberRole(communityID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !roleAtLeast(role, "moderator") {
+		return nil, ErrForbidden
+	}
+
+	topic, err := s.GetTopic(communityID, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := topic.TopicState
+	if req.Pinned != nil {
+		state.Pinned = *req.Pinned
+	}
+	if req.Locked != nil {
+		state.Locked = *req.Locked
+	}
+	if req.Archived != nil {
+		state.Archived = *req.Archived
+	}
+
+	updated, err := s.repo.SetTopicState(communityID, topicID, state)
+	if errors.Is(err, repository.ErrTopicNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		logger.Log.Error("Failed to set topic state",
+			zap.String("topic_id", topicID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	logger.Log.Info("Topic state changed",
+		zap.String("topic_id", topicID),
+		zap.String("moderator_id", userID),
+		zap.Bool("pinned", state.Pinned),
+		zap.Bool("locked", state.Locked),
+		zap.Bool("archived", state.Archived))
+	if state != topic.TopicState {
+		s.notifyModeration(communityID, userID, topic.UserID, forummodels.ModerationTopicStateChanged, topicID, "")
+	}
+	return updated, nil
+}
+
+// requireWritableTopic проверяет, что в тему можно добавлять сообщения
+func (s *forumServiceImpl) requireWritableTopic(communityID, topicID string) error {
+	topic, err := s.GetTopic(communityID, topicID)
+	if err != nil {
+		return err
+	}
+	if topic.Archived {
+		return ErrTopicArchived
+	}
+	if topic.Locked {
+		return ErrTopicLocked
+	}
+	return nil
+}
+
+// requireEditor проверяет, что пользователь может редактировать контент автора authorID,
+// и возвращает его роль в сообществе
+func (s *forumServiceImpl) requireEditor(communityID, userID, authorID string) (string, error) {
+	role, err := s.memberRole(communityID, userID)
+	if err != nil {
+		return "", err
+	}
+	if roleAtLeast(role, "moderator") || userID == authorID {
+		return role, nil
+	}
+	return "", ErrForbidden
+}
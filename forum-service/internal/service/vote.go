@@ -0,0 +1,75 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/repository"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Vote сохраняет голос пользователя за тему или сообщение. Значение 0 отменяет голос.
+func (s *forumServiceImpl) Vote(communityID, targetType, targetID, userID string, value int) (*forummodels.VoteResult, error) {
+	if value < -1 || value > 1 {
+		return nil, ErrInvalidVote
+	}
+	if err := s.requireMember(communityID, userID); err != nil {
+		return nil, err
+	}
+
+	var authorID, topicID string
+	switch targetType {
+	case forummodels.VoteTargetTopic:
+		topic, err := s.GetTopic(communityID, targetID)
+		if err != nil {
+			return nil, err
+		}
+		authorID, topicID = topic.UserID, topic.ID
+	case forummodels.VoteTargetMessage:
+		message, err := s.getMessage(communityID, targetID)
+		if err != nil {
+			return nil, err
+		}
+		authorID, topicID = message.UserID, message.TopicID
+	default:
+		return nil, ErrInvalidVote
+	}
+
+	if authorID == userID {
+		return nil, ErrSelfVote
+	}
+	if topicID != "" {
+		if err := s.requireWritableTopic(communityID, topicID); err != nil {
+			return nil, err
+		}
+	}
+
+	score, err := s.repo.SetVote(communityID, targetType, targetID, userID, authorID, value, time.Now())
+	if errors.Is(err, repository.ErrInvalidRequest) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		logger.Log.Error("Failed to save vote",
+			zap.String("target_type", targetType),
+			zap.String("target_id", targetID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	return &forummodels.VoteResult{Score: score, MyVote: value}, nil
+}
+
+// GetUserKarma возвращает карму пользователя по всем сообществам
+func (s *forumServiceImpl) GetUserKarma(userID string) (*forummodels.Karma, error) {
+	karma, err := s.repo.GetUserKarma(userID)
+	if err != nil {
+		logger.Log.Error("Failed to get user karma",
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+	return karma, nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/forum-service/internal/repository"
+	"github.com/luckermt/forum-app/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// SetWatch задает уровень слежения пользователя за темой или разделом
+func (s *forumServiceImpl) SetWatch(communityID, targetType, targetID, userID, level string) (*forummodels.Watch, error) {
+	switch level {
+	case forummodels.WatchWatching, forummodels.WatchTracking, forummodels.WatchMuted:
+	default:
+		return nil, ErrInvalidWatch
+	}
+	if err := s.requireMember(communityID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.watchTarget(communityID, targetType, targetID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetWatch(communityID, userID, targetType, targetID, level, time.Now()); err != nil {
+		logger.Log.Error("Failed to set watch level",
+			zap.String("target_type", targetType),
+			zap.String("target_id", targetID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+	return &forummodels.Watch{Level: level, Source: targetType}, nil
+}
+
+// RemoveWatch сбрасывает выбранный уровень слежения и возвращает действующий после сброса:
+// для темы это может быть уровень ее раздела
+func (s *forumServiceImpl) RemoveWatch(communityID, targetType, targetID, userID string) (*forummodels.Watch, error) {
+	if err := s.watchTarget(communityID, targetType, targetID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.DeleteWatch(communityID, userID, targetType, targetID); err != nil {
+		logger.Log.Error("Failed to remove watch level",
+			zap.String("target_type", targetType),
+			zap.String("target_id", targetID),
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+
+	if targetType == forummodels.WatchTargetTopic {
+		return s.GetTopicWatch(communityID, targetID, userID)
+	}
+	return &forummodels.Watch{Level: forummodels.WatchNormal}, nil
+}
+
+// GetTopicWatch возвращает действующий уровень слежения пользователя за темой
+func (s *forumServiceImpl) GetTopicWatch(communityID, topicID, userID string) (*forummodels.Watch, error) {
+	topic, err := s.GetTopic(communityID, topicID)
+	if err != nil {
+		return nil, err
+	}
+
+	level, err := s.repo.GetWatch(communityID, userID, forummodels.WatchTargetTopic, topicID)
+	if err != nil {
+		return nil, err
+	}
+	if level != "" {
+		return &forummodels.Watch{Level: level, Source: forummodels.WatchTargetTopic}, nil
+	}
+
+	if topic.CategoryID != "" {
+		level, err = s.repo.GetWatch(communityID, userID, forummodels.WatchTargetCategory, topic.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		if level != "" {
+			return &forummodels.Watch{Level: level, Source: forummodels.WatchTargetCategory}, nil
+		}
+	}
+	return &forummodels.Watch{Level: forummodels.WatchNormal}, nil
+}
+
+// watchTarget проверяет, что тема или раздел существуют
+func (s *forumServiceImpl) watchTarget(communityID, targetType, targetID string) error {
+	switch targetType {
+	case forummodels.WatchTargetTopic:
+		_, err := s.GetTopic(communityID, targetID)
+		return err
+	case forummodels.WatchTargetCategory:
+		_, err := s.repo.GetCategory(communityID, targetID)
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return ErrNotFound
+}
+
+// notifyReply рассылает уведомления о новом ответе в теме: упомянутым пользователям,
+// автору темы, автору сообщения, на которое ответили, и следящим за темой с учетом уровня слежения.
+// Автор ответа начинает отслеживать тему, если еще не выбрал уровень сам.
+func (s *forumServiceImpl) notifyReply(communityID string, message *forummodels.MessageDetail, parentAuthorID string, mentions []*forummodels.Notification) {
+	topic, err := s.repo.GetTopic(communityID, message.TopicID)
+	if err != nil {
+		logger.Log.Error("Failed to get topic for reply notifications",
+			zap.String("topic_id", message.TopicID),
+			zap.Error(err))
+		s.notify(communityID, mentions)
+		return
+	}
+
+	if err := s.repo.AddWatch(communityID, message.UserID, forummodels.WatchTargetTopic, topic.ID, forummodels.WatchTracking, time.Now()); err != nil {
+		logger.Log.Error("Failed to track replied topic",
+			zap.String("topic_id", topic.ID),
+			zap.String("user_id", message.UserID),
+			zap.Error(err))
+	}
+
+	watchers, err := s.repo.GetTopicWatchers(communityID, topic.ID, topic.CategoryID)
+	if err != nil {
+		logger.Log.Error("Failed to get topic watchers",
+			zap.String("topic_id", topic.ID),
+			zap.Error(err))
+	}
+
+	notifications := replyNotifications(topic.UserID, message, parentAuthorID, mentions)
+	notifications, trackers := applyWatchLevels(message, notifications, watchers)
+	s.notify(communityID, notifications)
+
+	event := forummodels.TopicActivityEvent{
+		Type:      forummodels.TopicActivityEventType,
+		TopicID:   message.TopicID,
+		MessageID: message.ID,
+		UserID:    message.UserID,
+	}
+	for _, userID := range trackers {
+		s.broadcastChan <- chatBroadcast{communityID: communityID, userID: userID, message: event}
+	}
+}
+
+// applyWatchLevels убирает уведомления для тех, кто заглушил тему, добавляет уведомления
+// следящим (watching) и возвращает отслеживающих (tracking), которым нужно только событие.
+// Автор сообщения ничего не получает.
+func applyWatchLevels(message *forummodels.MessageDetail, notifications []*forummodels.Notification, watchers map[string]string) ([]*forummodels.Notification, []string) {
+	filtered := make([]*forummodels.Notification, 0, len(notifications))
+	notified := map[string]bool{message.UserID: true}
+	for _, n := range notifications {
+		notified[n.UserID] = true
+		if watchers[n.UserID] == forummodels.WatchMuted {
+			continue
+		}
+		filtered = append(filtered, n)
+	}
+
+	userIDs := make([]string, 0, len(watchers))
+	for userID := range watchers {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	var trackers []string
+	for _, userID := range userIDs {
+		if notified[userID] {
+			continue
+		}
+		switch watchers[userID] {
+		case forummodels.WatchWatching:
+			filtered = append(filtered, newNotification(userID, forummodels.NotificationTopicReply,
+				message.UserID, message.TopicID, message.ID))
+		case forummodels.WatchTracking:
+			trackers = append(trackers, userID)
+		}
+	}
+	return filtered, trackers
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"testing"
+
+	forummodels "github.com/luckermt/forum-app/forum-service/internal/models"
+	"github.com/luckermt/forum-app/shared/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyWatchLevels(t *testing.T) {
+	message := &forummodels.MessageDetail{Message: models.Message{ID: "m1", TopicID: "t1", UserID: "author"}}
+	notifications := replyNotifications("owner", message, "parent", nil)
+	watchers := map[string]string{
+		"author":  forummodels.WatchWatching,
+		"owner":   forummodels.WatchMuted,
+		"parent":  forummodels.WatchTracking,
+		"watcher": forummodels.WatchWatching,
+		"tracker": forummodels.WatchTracking,
+		"muted":   forummodels.WatchMuted,
+	}
+
+	result, trackers := applyWatchLevels(message, notifications, watchers)
+
+	assert.Len(t, result, 2)
+	assert.Equal(t, "parent", result[0].UserID)
+	assert.Equal(t, forummodels.NotificationReply, result[0].Type)
+	assert.Equal(t, "watcher", result[1].UserID)
+	assert.Equal(t, forummodels.NotificationTopicReply, result[1].Type)
+	assert.Equal(t, []string{"tracker"}, trackers)
+}
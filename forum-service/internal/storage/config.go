@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+)
+
+// Поддерживаемые хранилища
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// DefaultDir каталог локального хранилища по умолчанию
+const DefaultDir = "data/attachments"
+
+// Config выбор хранилища и его параметры
+type Config struct {
+	Backend string
+	Dir     string
+	S3      S3Config
+}
+
+// LoadConfig читает настройки хранилища из переменных окружения:
+// STORAGE_BACKEND (local или s3), STORAGE_DIR для локального хранилища,
+// S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY и S3_SECRET_KEY для S3.
+func LoadConfig() Config {
+	cfg := Config{
+		Backend: os.Getenv("STORAGE_BACKEND"),
+		Dir:     os.Getenv("STORAGE_DIR"),
+		S3: S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		},
+	}
+	if cfg.Backend == "" {
+		cfg.Backend = BackendLocal
+	}
+	if cfg.Dir == "" {
+		cfg.Dir = DefaultDir
+	}
+	return cfg
+}
+
+// NewBlobStore создает хранилище по настройкам
+func NewBlobStore(cfg Config) (BlobStore, error) {
+	switch cfg.Backend {
+	case BackendLocal:
+		return NewLocalStore(cfg.Dir)
+	case BackendS3:
+		return NewS3Store(cfg.S3)
+	}
+	return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+}
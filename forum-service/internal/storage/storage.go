@@ -0,0 +1,36 @@
+// Package storage хранит содержимое вложений. Объект адресуется ключом,
+// который сервис строит из хэша содержимого, поэтому одинаковые файлы хранятся один раз.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore хранилище содержимого вложений
+type BlobStore interface {
+	// Put сохраняет объект; существующий объект с тем же ключом заменяется
+	Put(key string, content io.Reader, size int64, contentType string) error
+	// Get открывает объект для чтения; для отсутствующего возвращает ErrNotFound
+	Get(key string) (io.ReadCloser, error)
+	Exists(key string) (bool, error)
+	// Delete удаляет объект; отсутствие объекта ошибкой не считается
+	Delete(key string) error
+}
+
+// keyRe допустимые ключи: без разделителей пути, чтобы ключ не мог выйти за пределы хранилища
+var keyRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{1,127}$`)
+
+func validateKey(key string) error {
+	if !keyRe.MatchString(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 заменяет S3 в тестах: хранит объекты в памяти и отклоняет неподписанные запросы
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=key/") || !strings.Contains(auth, "/us-east-1/s3/aws4_request") ||
+		r.Header.Get("X-Amz-Date") == "" || r.Header.Get("X-Amz-Content-Sha256") != unsignedPayload {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = body
+		f.types[r.URL.Path] = r.Header.Get("Content-Type")
+	case http.MethodGet, http.MethodHead:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodGet {
+			w.Write(body)
+		}
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func testBlobStore(t *testing.T, store BlobStore) {
+	exists, err := store.Exists("abc123")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = store.Get("abc123")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, store.Put("abc123", strings.NewReader("hello"), 5, "text/plain"))
+	exists, err = store.Exists("abc123")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	body, err := store.Get("abc123")
+	require.NoError(t, err)
+	content, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, "hello", string(content))
+
+	require.NoError(t, store.Delete("abc123"))
+	require.NoError(t, store.Delete("abc123"))
+	exists, err = store.Exists("abc123")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	assert.ErrorIs(t, store.Put("../etc/passwd", strings.NewReader(""), 0, ""), ErrInvalidKey)
+}
+
+func TestLocalStore(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	testBlobStore(t, store)
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte), types: make(map[string]string)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := NewS3Store(S3Config{Endpoint: server.URL, Bucket: "forum", AccessKey: "key", SecretKey: "secret"})
+	require.NoError(t, err)
+
+	testBlobStore(t, store)
+
+	require.NoError(t, store.Put("def456", strings.NewReader("{}"), 2, "application/json"))
+	assert.Equal(t, "application/json", fake.types["/forum/def456"])
+}
+
+func TestS3Store_RejectedRequest(t *testing.T) {
+	server := httptest.NewServer(&fakeS3{})
+	defer server.Close()
+
+	store, err := NewS3Store(S3Config{Endpoint: server.URL, Bucket: "forum", AccessKey: "other", SecretKey: "secret"})
+	require.NoError(t, err)
+
+	_, err = store.Exists("abc123")
+	assert.Error(t, err)
+}
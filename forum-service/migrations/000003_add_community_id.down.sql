@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_messages_community_topic;
+DROP INDEX IF EXISTS idx_topics_community_created_at;
+ALTER TABLE messages DROP COLUMN IF EXISTS community_id;
+ALTER TABLE topics DROP COLUMN IF EXISTS community_id;
\ No newline at end of file
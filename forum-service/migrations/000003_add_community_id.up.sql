@@ -0,0 +1,5 @@
+ALTER TABLE topics ADD COLUMN community_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE messages ADD COLUMN community_id VARCHAR(36) NOT NULL DEFAULT 'default';
+
+CREATE INDEX idx_topics_community_created_at ON topics(community_id, created_at);
+CREATE INDEX idx_messages_community_topic ON messages(community_id, topic_id);
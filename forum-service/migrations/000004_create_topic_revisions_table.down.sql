@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS topic_revisions;
+ALTER TABLE topics DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE topics DROP COLUMN IF EXISTS revision;
\ No newline at end of file
@@ -0,0 +1,16 @@
+ALTER TABLE topics ADD COLUMN revision INT NOT NULL DEFAULT 1;
+ALTER TABLE topics ADD COLUMN updated_at TIMESTAMP NULL;
+
+CREATE TABLE topic_revisions (
+    topic_id   VARCHAR(36) NOT NULL REFERENCES topics(id) ON DELETE CASCADE,
+    revision   INT NOT NULL,
+    title      VARCHAR(100) NOT NULL,
+    content    TEXT NOT NULL,
+    edited_by  VARCHAR(36) NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (topic_id, revision)
+);
+
+-- Первая ревизия для уже существующих тем
+INSERT INTO topic_revisions (topic_id, revision, title, content, edited_by, created_at)
+SELECT id, 1, title, content, user_id, created_at FROM topics;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_messages_chat_created_id;
+DROP INDEX IF EXISTS idx_messages_topic_created_id;
+DROP INDEX IF EXISTS idx_topics_community_activity;
+DROP INDEX IF EXISTS idx_topics_community_created_id;
+CREATE INDEX idx_topics_community_created_at ON topics(community_id, created_at);
+ALTER TABLE topics DROP COLUMN IF EXISTS message_count;
\ No newline at end of file
@@ -0,0 +1,13 @@
+ALTER TABLE topics ADD COLUMN message_count INT NOT NULL DEFAULT 0;
+
+UPDATE topics t SET message_count = m.cnt
+FROM (SELECT topic_id, COUNT(*) AS cnt FROM messages
+      WHERE is_chat = false AND topic_id IS NOT NULL
+      GROUP BY topic_id) m
+WHERE t.id = m.topic_id;
+
+DROP INDEX IF EXISTS idx_topics_community_created_at;
+CREATE INDEX idx_topics_community_created_id ON topics(community_id, created_at, id) WHERE deleted = false;
+CREATE INDEX idx_topics_community_activity ON topics(community_id, message_count, created_at, id) WHERE deleted = false;
+CREATE INDEX idx_messages_topic_created_id ON messages(topic_id, created_at, id) WHERE is_chat = false;
+CREATE INDEX idx_messages_chat_created_id ON messages(community_id, created_at, id) WHERE is_chat = true;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_messages_search;
+DROP INDEX IF EXISTS idx_topics_search;
+ALTER TABLE messages DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE messages DROP COLUMN IF EXISTS search_config;
+ALTER TABLE topics DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE topics DROP COLUMN IF EXISTS search_config;
\ No newline at end of file
@@ -0,0 +1,14 @@
+-- Конфигурация текстового поиска выбирается по языку текста при записи
+ALTER TABLE topics ADD COLUMN search_config regconfig NOT NULL DEFAULT 'simple';
+ALTER TABLE topics ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector(search_config, coalesce(title, '')), 'A') ||
+    setweight(to_tsvector(search_config, coalesce(content, '')), 'B')
+) STORED;
+
+ALTER TABLE messages ADD COLUMN search_config regconfig NOT NULL DEFAULT 'simple';
+ALTER TABLE messages ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    to_tsvector(search_config, coalesce(content, ''))
+) STORED;
+
+CREATE INDEX idx_topics_search ON topics USING GIN (search_vector) WHERE deleted = false;
+CREATE INDEX idx_messages_search ON messages USING GIN (search_vector) WHERE is_chat = false;
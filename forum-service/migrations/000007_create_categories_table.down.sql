@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_topics_category_created_id;
+ALTER TABLE topics DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS categories;
\ No newline at end of file
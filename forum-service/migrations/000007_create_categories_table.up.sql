@@ -0,0 +1,18 @@
+CREATE TABLE categories (
+    id           VARCHAR(36) PRIMARY KEY,
+    community_id VARCHAR(36) NOT NULL,
+    parent_id    VARCHAR(36) REFERENCES categories(id),
+    name         VARCHAR(100) NOT NULL,
+    slug         VARCHAR(64) NOT NULL,
+    description  TEXT NOT NULL DEFAULT '',
+    sort_order   INT NOT NULL DEFAULT 0,
+    post_role    VARCHAR(20) NOT NULL DEFAULT 'user',
+    created_at   TIMESTAMP NOT NULL,
+    CONSTRAINT categories_community_slug_key UNIQUE (community_id, slug)
+);
+
+CREATE INDEX idx_categories_parent ON categories(parent_id);
+
+-- Раздел нельзя удалить, пока в нем есть темы или подразделы
+ALTER TABLE topics ADD COLUMN category_id VARCHAR(36) REFERENCES categories(id);
+CREATE INDEX idx_topics_category_created_id ON topics(category_id, created_at, id) WHERE deleted = false;
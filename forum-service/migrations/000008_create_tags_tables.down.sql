@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS tag_settings;
+DROP TABLE IF EXISTS topic_tags;
+DROP TABLE IF EXISTS tags;
\ No newline at end of file
@@ -0,0 +1,28 @@
+CREATE TABLE tags (
+    community_id VARCHAR(36) NOT NULL,
+    name         VARCHAR(32) NOT NULL,
+    synonym_of   VARCHAR(32),
+    official     BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at   TIMESTAMP NOT NULL,
+    PRIMARY KEY (community_id, name),
+    FOREIGN KEY (community_id, synonym_of) REFERENCES tags(community_id, name) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_tags_prefix ON tags(community_id, name varchar_pattern_ops);
+CREATE INDEX idx_tags_synonym_of ON tags(community_id, synonym_of);
+
+CREATE TABLE topic_tags (
+    topic_id     VARCHAR(36) NOT NULL REFERENCES topics(id) ON DELETE CASCADE,
+    community_id VARCHAR(36) NOT NULL,
+    tag          VARCHAR(32) NOT NULL,
+    PRIMARY KEY (topic_id, tag),
+    FOREIGN KEY (community_id, tag) REFERENCES tags(community_id, name) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_topic_tags_tag ON topic_tags(community_id, tag);
+
+CREATE TABLE tag_settings (
+    community_id VARCHAR(36) PRIMARY KEY,
+    restricted   BOOLEAN NOT NULL DEFAULT FALSE,
+    max_tags     INT NOT NULL DEFAULT 5
+);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_topics_community_pinned_activity;
+DROP INDEX IF EXISTS idx_topics_community_pinned_created_id;
+CREATE INDEX idx_topics_community_created_id ON topics(community_id, created_at, id) WHERE deleted = false;
+CREATE INDEX idx_topics_community_activity ON topics(community_id, message_count, created_at, id) WHERE deleted = false;
+ALTER TABLE topics DROP COLUMN IF EXISTS archived;
+ALTER TABLE topics DROP COLUMN IF EXISTS locked;
+ALTER TABLE topics DROP COLUMN IF EXISTS pinned;
\ No newline at end of file
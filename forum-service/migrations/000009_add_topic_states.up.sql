@@ -0,0 +1,9 @@
+ALTER TABLE topics ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE topics ADD COLUMN locked BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE topics ADD COLUMN archived BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Списки тем сортируются сначала по закреплению
+DROP INDEX IF EXISTS idx_topics_community_created_id;
+DROP INDEX IF EXISTS idx_topics_community_activity;
+CREATE INDEX idx_topics_community_pinned_created_id ON topics(community_id, pinned, created_at, id) WHERE deleted = false;
+CREATE INDEX idx_topics_community_pinned_activity ON topics(community_id, pinned, message_count, created_at, id) WHERE deleted = false;
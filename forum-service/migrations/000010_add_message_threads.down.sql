@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_messages_parent_created_id;
+DROP INDEX IF EXISTS idx_messages_topic_roots;
+ALTER TABLE messages DROP COLUMN IF EXISTS reply_count;
+ALTER TABLE messages DROP COLUMN IF EXISTS depth;
+ALTER TABLE messages DROP COLUMN IF EXISTS parent_id;
\ No newline at end of file
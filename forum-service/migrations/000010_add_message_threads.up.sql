@@ -0,0 +1,6 @@
+ALTER TABLE messages ADD COLUMN parent_id VARCHAR(36) REFERENCES messages(id) ON DELETE SET NULL;
+ALTER TABLE messages ADD COLUMN depth INT NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN reply_count INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_messages_topic_roots ON messages(topic_id, created_at, id) WHERE is_chat = false AND parent_id IS NULL;
+CREATE INDEX idx_messages_parent_created_id ON messages(parent_id, created_at, id) WHERE parent_id IS NOT NULL;
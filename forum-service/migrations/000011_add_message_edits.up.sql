@@ -0,0 +1,14 @@
+ALTER TABLE messages ADD COLUMN edited_at TIMESTAMP;
+ALTER TABLE messages ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE messages ADD COLUMN deleted_by VARCHAR(36) REFERENCES users(id);
+
+-- Предыдущие версии сообщений; created_at — время правки, заменившей эту версию
+CREATE TABLE message_revisions (
+    id         SERIAL PRIMARY KEY,
+    message_id VARCHAR(36) NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    content    TEXT NOT NULL,
+    edited_by  VARCHAR(36) NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_message_revisions_message ON message_revisions(message_id, created_at);
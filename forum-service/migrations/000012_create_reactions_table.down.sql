@@ -0,0 +1 @@
+DROP TABLE IF EXISTS reactions;
\ No newline at end of file
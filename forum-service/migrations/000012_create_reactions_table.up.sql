@@ -0,0 +1,11 @@
+CREATE TABLE reactions (
+    community_id VARCHAR(36) NOT NULL,
+    target_type  VARCHAR(16) NOT NULL,
+    target_id    VARCHAR(36) NOT NULL,
+    user_id      VARCHAR(36) NOT NULL REFERENCES users(id),
+    emoji        VARCHAR(32) NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    PRIMARY KEY (target_type, target_id, user_id, emoji)
+);
+
+CREATE INDEX idx_reactions_target ON reactions(community_id, target_type, target_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_messages_topic_score;
+DROP INDEX IF EXISTS idx_topics_community_pinned_score;
+ALTER TABLE messages DROP COLUMN IF EXISTS score;
+ALTER TABLE topics DROP COLUMN IF EXISTS score;
+DROP TABLE IF EXISTS votes;
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE TABLE votes (
+    community_id VARCHAR(36) NOT NULL,
+    target_type  VARCHAR(16) NOT NULL,
+    target_id    VARCHAR(36) NOT NULL,
+    user_id      VARCHAR(36) NOT NULL REFERENCES users(id),
+    -- Автор темы или сообщения, которому начисляется карма
+    author_id    VARCHAR(36) NOT NULL REFERENCES users(id),
+    value        SMALLINT NOT NULL CHECK (value IN (-1, 1)),
+    created_at   TIMESTAMP NOT NULL,
+    PRIMARY KEY (target_type, target_id, user_id)
+);
+
+CREATE INDEX idx_votes_author ON votes(author_id);
+
+ALTER TABLE topics ADD COLUMN score INT NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN score INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_topics_community_pinned_score ON topics(community_id, pinned, score, created_at, id) WHERE deleted = false;
+CREATE INDEX idx_messages_topic_score ON messages(topic_id, score, created_at, id) WHERE is_chat = false;
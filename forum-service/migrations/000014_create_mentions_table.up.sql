@@ -0,0 +1,24 @@
+CREATE TABLE mentions (
+    message_id   VARCHAR(36) NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+    user_id      VARCHAR(36) NOT NULL REFERENCES users(id),
+    username     VARCHAR(50) NOT NULL,
+    community_id VARCHAR(36) NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    PRIMARY KEY (message_id, user_id)
+);
+
+CREATE INDEX idx_mentions_user ON mentions(community_id, user_id, created_at DESC);
+
+CREATE TABLE notifications (
+    id           VARCHAR(36) PRIMARY KEY,
+    community_id VARCHAR(36) NOT NULL,
+    user_id      VARCHAR(36) NOT NULL REFERENCES users(id),
+    type         VARCHAR(32) NOT NULL,
+    actor_id     VARCHAR(36),
+    topic_id     VARCHAR(36),
+    message_id   VARCHAR(36),
+    created_at   TIMESTAMP NOT NULL,
+    read_at      TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_user ON notifications(community_id, user_id, created_at DESC, id DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_notifications_unread;
+ALTER TABLE notifications DROP COLUMN IF EXISTS action;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE notifications ADD COLUMN action VARCHAR(32);
+
+CREATE INDEX idx_notifications_unread ON notifications(community_id, user_id) WHERE read_at IS NULL;
@@ -0,0 +1,11 @@
+CREATE TABLE watches (
+    community_id VARCHAR(36) NOT NULL,
+    user_id      VARCHAR(36) NOT NULL REFERENCES users(id),
+    target_type  VARCHAR(16) NOT NULL,
+    target_id    VARCHAR(36) NOT NULL,
+    level        VARCHAR(16) NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, target_type, target_id)
+);
+
+CREATE INDEX idx_watches_target ON watches(community_id, target_type, target_id);
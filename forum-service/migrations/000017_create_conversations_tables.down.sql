@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_blocks;
+DROP TABLE IF EXISTS direct_messages;
+DROP TABLE IF EXISTS conversation_members;
+DROP TABLE IF EXISTS conversations;
\ No newline at end of file
@@ -0,0 +1,36 @@
+CREATE TABLE conversations (
+    id              VARCHAR(36) PRIMARY KEY,
+    community_id    VARCHAR(36) NOT NULL,
+    direct_key      VARCHAR(80),
+    created_by      VARCHAR(36) NOT NULL REFERENCES users(id),
+    created_at      TIMESTAMP NOT NULL,
+    last_message_at TIMESTAMP,
+    UNIQUE (community_id, direct_key)
+);
+
+CREATE TABLE conversation_members (
+    conversation_id VARCHAR(36) NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    user_id         VARCHAR(36) NOT NULL REFERENCES users(id),
+    joined_at       TIMESTAMP NOT NULL,
+    last_read_at    TIMESTAMP,
+    PRIMARY KEY (conversation_id, user_id)
+);
+
+CREATE INDEX idx_conversation_members_user ON conversation_members(user_id);
+
+CREATE TABLE direct_messages (
+    id              VARCHAR(36) PRIMARY KEY,
+    conversation_id VARCHAR(36) NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    user_id         VARCHAR(36) NOT NULL REFERENCES users(id),
+    content         TEXT NOT NULL,
+    created_at      TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_direct_messages_conversation ON direct_messages(conversation_id, created_at DESC, id DESC);
+
+CREATE TABLE user_blocks (
+    user_id         VARCHAR(36) NOT NULL REFERENCES users(id),
+    blocked_user_id VARCHAR(36) NOT NULL REFERENCES users(id),
+    created_at      TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, blocked_user_id)
+);
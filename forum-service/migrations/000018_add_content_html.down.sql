@@ -0,0 +1,3 @@
+ALTER TABLE direct_messages DROP COLUMN IF EXISTS content_html;
+ALTER TABLE messages DROP COLUMN IF EXISTS content_html;
+ALTER TABLE topics DROP COLUMN IF EXISTS content_html;
\ No newline at end of file
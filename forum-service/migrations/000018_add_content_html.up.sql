@@ -0,0 +1,4 @@
+-- HTML, отрендеренный из Markdown. У старых записей NULL: сервис рендерит их при чтении
+ALTER TABLE topics ADD COLUMN content_html TEXT;
+ALTER TABLE messages ADD COLUMN content_html TEXT;
+ALTER TABLE direct_messages ADD COLUMN content_html TEXT;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS attachment_refs;
+DROP TABLE IF EXISTS attachments;
\ No newline at end of file
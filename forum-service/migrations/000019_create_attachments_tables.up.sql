@@ -0,0 +1,23 @@
+-- Содержимое вложений хранится в BlobStore под ключом sha256, одинаковые файлы хранятся один раз
+CREATE TABLE attachments (
+    id           VARCHAR(36) PRIMARY KEY,
+    community_id VARCHAR(36) NOT NULL,
+    user_id      VARCHAR(36) NOT NULL REFERENCES users(id),
+    filename     VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size         BIGINT NOT NULL,
+    sha256       CHAR(64) NOT NULL,
+    created_at   TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_attachments_sha256 ON attachments(sha256);
+
+CREATE TABLE attachment_refs (
+    attachment_id VARCHAR(36) NOT NULL REFERENCES attachments(id) ON DELETE CASCADE,
+    target_type   VARCHAR(16) NOT NULL,
+    target_id     VARCHAR(36) NOT NULL,
+    position      INT NOT NULL,
+    PRIMARY KEY (attachment_id, target_type, target_id)
+);
+
+CREATE INDEX idx_attachment_refs_target ON attachment_refs(target_type, target_id);
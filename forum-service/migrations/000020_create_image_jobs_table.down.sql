@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS image_jobs;
+DROP TABLE IF EXISTS attachment_variants;
+ALTER TABLE attachments DROP COLUMN IF EXISTS height;
+ALTER TABLE attachments DROP COLUMN IF EXISTS width;
+ALTER TABLE attachments DROP COLUMN IF EXISTS status;
\ No newline at end of file
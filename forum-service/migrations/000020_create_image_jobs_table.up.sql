@@ -0,0 +1,35 @@
+ALTER TABLE attachments ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'ready';
+ALTER TABLE attachments ADD COLUMN width INT;
+ALTER TABLE attachments ADD COLUMN height INT;
+
+-- Обработанные копии картинок; blob_key — ключ в BlobStore
+CREATE TABLE attachment_variants (
+    attachment_id VARCHAR(36) NOT NULL REFERENCES attachments(id) ON DELETE CASCADE,
+    name          VARCHAR(16) NOT NULL,
+    blob_key      VARCHAR(128) NOT NULL,
+    content_type  VARCHAR(100) NOT NULL,
+    size          BIGINT NOT NULL,
+    width         INT NOT NULL,
+    height        INT NOT NULL,
+    PRIMARY KEY (attachment_id, name)
+);
+
+-- Очередь обработки картинок. Воркеры забирают задачи через FOR UPDATE SKIP LOCKED,
+-- locked_until защищает задачу, пока ее обрабатывают, и возвращает ее в очередь, если воркер упал
+CREATE TABLE image_jobs (
+    attachment_id VARCHAR(36) PRIMARY KEY REFERENCES attachments(id) ON DELETE CASCADE,
+    attempts      INT NOT NULL DEFAULT 0,
+    run_after     TIMESTAMP NOT NULL,
+    locked_until  TIMESTAMP,
+    last_error    TEXT,
+    created_at    TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_image_jobs_run_after ON image_jobs(run_after);
+
+-- Картинки, загруженные до появления обработки, тоже очищаются от метаданных
+UPDATE attachments SET status = 'pending'
+WHERE content_type IN ('image/png', 'image/jpeg', 'image/gif', 'image/webp');
+
+INSERT INTO image_jobs (attachment_id, run_after, created_at)
+SELECT id, NOW(), NOW() FROM attachments WHERE status = 'pending';
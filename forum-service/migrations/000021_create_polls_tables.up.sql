@@ -0,0 +1,39 @@
+CREATE TABLE polls (
+    id           VARCHAR(36) PRIMARY KEY,
+    topic_id     VARCHAR(36) NOT NULL UNIQUE REFERENCES topics(id) ON DELETE CASCADE,
+    question     TEXT NOT NULL,
+    multiple     BOOLEAN NOT NULL DEFAULT false,
+    anonymous    BOOLEAN NOT NULL DEFAULT false,
+    hide_results BOOLEAN NOT NULL DEFAULT false,
+    closes_at    TIMESTAMP,
+    created_at   TIMESTAMP NOT NULL
+);
+
+CREATE TABLE poll_options (
+    id       VARCHAR(36) PRIMARY KEY,
+    poll_id  VARCHAR(36) NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    text     TEXT NOT NULL,
+    position INT NOT NULL,
+    UNIQUE (poll_id, id)
+);
+
+-- Один бюллетень на пользователя: повторный голос нарушает первичный ключ
+CREATE TABLE poll_ballots (
+    poll_id    VARCHAR(36) NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    user_id    VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (poll_id, user_id)
+);
+
+-- Выбранные варианты бюллетеня; вариант обязан принадлежать тому же опросу
+CREATE TABLE poll_choices (
+    poll_id   VARCHAR(36) NOT NULL,
+    user_id   VARCHAR(36) NOT NULL,
+    option_id VARCHAR(36) NOT NULL,
+    PRIMARY KEY (poll_id, user_id, option_id),
+    FOREIGN KEY (poll_id, user_id) REFERENCES poll_ballots(poll_id, user_id) ON DELETE CASCADE,
+    FOREIGN KEY (poll_id, option_id) REFERENCES poll_options(poll_id, id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_poll_choices_option ON poll_choices(option_id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS content_refs;
\ No newline at end of file
@@ -0,0 +1,18 @@
+-- Цитаты и внутренние ссылки в темах и сообщениях. Для цитат запоминаются автор и тема
+-- цитируемого сообщения на момент цитирования.
+CREATE TABLE content_refs (
+    community_id     VARCHAR(36) NOT NULL,
+    source_type      VARCHAR(16) NOT NULL,
+    source_id        VARCHAR(36) NOT NULL,
+    source_topic_id  VARCHAR(36),
+    position         INT NOT NULL,
+    kind             VARCHAR(16) NOT NULL,
+    target_type      VARCHAR(16) NOT NULL,
+    target_id        VARCHAR(36) NOT NULL,
+    target_topic_id  VARCHAR(36),
+    target_author_id VARCHAR(36) NOT NULL,
+    created_at       TIMESTAMP NOT NULL,
+    PRIMARY KEY (source_type, source_id, position)
+);
+
+CREATE INDEX idx_content_refs_target_topic ON content_refs(community_id, target_topic_id, created_at DESC);
@@ -0,0 +1 @@
+ALTER TABLE tag_settings DROP CONSTRAINT IF EXISTS tag_settings_max_tags_check;
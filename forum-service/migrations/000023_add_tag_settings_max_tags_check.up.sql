@@ -0,0 +1,3 @@
+-- Ноль или слишком большой лимит делал невозможным создание тем с тегами
+UPDATE tag_settings SET max_tags = 5 WHERE max_tags NOT BETWEEN 1 AND 10;
+ALTER TABLE tag_settings ADD CONSTRAINT tag_settings_max_tags_check CHECK (max_tags BETWEEN 1 AND 10);
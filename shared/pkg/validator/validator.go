@@ -0,0 +1,217 @@
+// Package validator проверяет структуры запросов по тегам binding,
+// например `binding:"required,email,min=3,max=50"`.
+// Правила min и max считают длину строки в символах, maxbytes — в байтах.
+package validator
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError описывает нарушение одного правила для одного поля
+type FieldError struct {
+	Field   string `json:"field" example:"email"`
+	Rule    string `json:"rule" example:"email"`
+	Message string `json:"message" example:"email must be a valid email address"`
+}
+
+// Errors список ошибок валидации, возвращаемый Validate
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Validate проверяет поля структуры (или указателя на структуру) по тегам binding.
+// Возвращает Errors, если хотя бы одно правило нарушено.
+func Validate(v interface{}) error {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return fmt.Errorf("validator: nil value")
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return fmt.Errorf("validator: expected struct, got %s", val.Kind())
+	}
+
+	var errs Errors
+	validateStruct(val, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateStruct(val reflect.Value, errs *Errors) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldVal := val.Field(i)
+		if field.Anonymous && fieldVal.Kind() == reflect.Struct {
+			validateStruct(fieldVal, errs)
+			continue
+		}
+
+		tag := field.Tag.Get("binding")
+		if tag == "" || tag == "-" {
+			continue
+		}
+
+		name := fieldName(field)
+		for _, rule := range strings.Split(tag, ",") {
+			rule = strings.TrimSpace(rule)
+			if rule == "" {
+				continue
+			}
+			ruleName, param, _ := strings.Cut(rule, "=")
+
+			// Необязательные пустые поля проверяются только на required
+			if ruleName != "required" && isZero(fieldVal) {
+				continue
+			}
+
+			if msg, ok := check(ruleName, param, fieldVal); !ok {
+				*errs = append(*errs, FieldError{
+					Field:   name,
+					Rule:    ruleName,
+					Message: name + " " + msg,
+				})
+				if ruleName == "required" {
+					break
+				}
+			}
+		}
+	}
+}
+
+func check(rule, param string, val reflect.Value) (string, bool) {
+	switch rule {
+	case "required":
+		return "is required", !isZero(val)
+	case "email":
+		s := val.String()
+		addr, err := mail.ParseAddress(s)
+		return "must be a valid email address", err == nil && addr.Address == s
+	case "url":
+		u, err := url.Parse(val.String())
+		return "must be a valid absolute url", err == nil && u.IsAbs() && u.Host != ""
+	case "min":
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			panic(fmt.Sprintf("validator: invalid min parameter %q", param))
+		}
+		return minMessage(val, n), size(val) >= float64(n)
+	case "max":
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			panic(fmt.Sprintf("validator: invalid max parameter %q", param))
+		}
+		return maxMessage(val, n), size(val) <= float64(n)
+	case "maxbytes":
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			panic(fmt.Sprintf("validator: invalid maxbytes parameter %q", param))
+		}
+		return fmt.Sprintf("must be at most %d bytes long", n), len(indirect(val).String()) <= n
+	case "oneof":
+		options := strings.Fields(param)
+		s := fmt.Sprint(indirect(val).Interface())
+		for _, option := range options {
+			if s == option {
+				return "", true
+			}
+		}
+		return "must be one of: " + strings.Join(options, ", "), false
+	default:
+		panic(fmt.Sprintf("validator: unknown rule %q", rule))
+	}
+}
+
+// size возвращает длину строки в символах, количество элементов или значение числа
+func size(val reflect.Value) float64 {
+	switch val.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(val.String()))
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(val.Len())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint())
+	case reflect.Float32, reflect.Float64:
+		return val.Float()
+	case reflect.Ptr:
+		if val.IsNil() {
+			return 0
+		}
+		return size(val.Elem())
+	default:
+		return 0
+	}
+}
+
+// indirect разыменовывает указатели; пустые указатели до правил не доходят
+func indirect(val reflect.Value) reflect.Value {
+	for val.Kind() == reflect.Ptr && !val.IsNil() {
+		val = val.Elem()
+	}
+	return val
+}
+
+func minMessage(val reflect.Value, n int) string {
+	switch val.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("must be at least %d characters long", n)
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return fmt.Sprintf("must contain at least %d items", n)
+	default:
+		return fmt.Sprintf("must be at least %d", n)
+	}
+}
+
+func maxMessage(val reflect.Value, n int) string {
+	switch val.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("must be at most %d characters long", n)
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return fmt.Sprintf("must contain at most %d items", n)
+	default:
+		return fmt.Sprintf("must be at most %d", n)
+	}
+}
+
+func isZero(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(val.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return val.Len() == 0
+	default:
+		return val.IsZero()
+	}
+}
+
+// fieldName возвращает имя поля из тега json, чтобы ошибки совпадали с телом запроса
+func fieldName(field reflect.StructField) string {
+	if tag := field.Tag.Get("json"); tag != "" {
+		if name, _, _ := strings.Cut(tag, ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
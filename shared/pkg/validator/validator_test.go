@@ -0,0 +1,93 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/luckermt/forum-app/shared/pkg/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+type registerRequest struct {
+	Username string   `json:"username" binding:"required,min=3,max=50"`
+	Email    string   `json:"email" binding:"required,email"`
+	Password string   `json:"password" binding:"required,min=8,maxbytes=72"`
+	Tags     []string `json:"tags" binding:"max=2"`
+	Role     *string  `json:"role" binding:"oneof=user admin"`
+}
+
+func TestValidate_Valid(t *testing.T) {
+	err := validator.Validate(&registerRequest{
+		Username: "john_doe",
+		Email:    "user@example.com",
+		Password: "SecurePass123!",
+	})
+	assert.NoError(t, err)
+}
+
+func TestValidate_FieldErrors(t *testing.T) {
+	err := validator.Validate(registerRequest{
+		Username: "jo",
+		Email:    "not-an-email",
+		Tags:     []string{"a", "b", "c"},
+	})
+
+	var errs validator.Errors
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, validator.Errors{
+		{Field: "username", Rule: "min", Message: "username must be at least 3 characters long"},
+		{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+		{Field: "password", Rule: "required", Message: "password is required"},
+		{Field: "tags", Rule: "max", Message: "tags must contain at most 2 items"},
+	}, errs)
+}
+
+func TestValidate_RequiredRejectsBlank(t *testing.T) {
+	err := validator.Validate(&registerRequest{
+		Username: "   ",
+		Email:    "user@example.com",
+		Password: "SecurePass123!",
+	})
+
+	var errs validator.Errors
+	assert.ErrorAs(t, err, &errs)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, "username", errs[0].Field)
+}
+
+func TestValidate_PasswordLimitCountsBytes(t *testing.T) {
+	// 40 кириллических символов занимают 80 байт и не помещаются в bcrypt
+	err := validator.Validate(&registerRequest{
+		Username: "john_doe",
+		Email:    "user@example.com",
+		Password: strings.Repeat("п", 40),
+	})
+
+	var errs validator.Errors
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, validator.Errors{
+		{Field: "password", Rule: "maxbytes", Message: "password must be at most 72 bytes long"},
+	}, errs)
+}
+
+func TestValidate_OneOfDereferencesPointers(t *testing.T) {
+	admin, owner := "admin", "owner"
+
+	assert.NoError(t, validator.Validate(&registerRequest{
+		Username: "john_doe",
+		Email:    "user@example.com",
+		Password: "SecurePass123!",
+		Role:     &admin,
+	}))
+
+	err := validator.Validate(&registerRequest{
+		Username: "john_doe",
+		Email:    "user@example.com",
+		Password: "SecurePass123!",
+		Role:     &owner,
+	})
+	var errs validator.Errors
+	assert.ErrorAs(t, err, &errs)
+	assert.Equal(t, "role", errs[0].Field)
+	assert.Equal(t, "oneof", errs[0].Rule)
+}
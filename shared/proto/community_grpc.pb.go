@@ -0,0 +1,165 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: community.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CommunityService_ResolveCommunity_FullMethodName = "/auth.CommunityService/ResolveCommunity"
+	CommunityService_GetMemberRole_FullMethodName    = "/auth.CommunityService/GetMemberRole"
+)
+
+// CommunityServiceClient is the client API for CommunityService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CommunityService отдает другим сервисам данные о сообществах
+// и ролях пользователей в них
+type CommunityServiceClient interface {
+	ResolveCommunity(ctx context.Context, in *ResolveCommunityRequest, opts ...grpc.CallOption) (*CommunityResponse, error)
+	GetMemberRole(ctx context.Context, in *MemberRoleRequest, opts ...grpc.CallOption) (*MemberRoleResponse, error)
+}
+
+type communityServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCommunityServiceClient(cc grpc.ClientConnInterface) CommunityServiceClient {
+	return &communityServiceClient{cc}
+}
+
+func (c *communityServiceClient) ResolveCommunity(ctx context.Context, in *ResolveCommunityRequest, opts ...grpc.CallOption) (*CommunityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommunityResponse)
+	err := c.cc.Invoke(ctx, CommunityService_ResolveCommunity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *communityServiceClient) GetMemberRole(ctx context.Context, in *MemberRoleRequest, opts ...grpc.CallOption) (*MemberRoleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MemberRoleResponse)
+	err := c.cc.Invoke(ctx, CommunityService_GetMemberRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CommunityServiceServer is the server API for CommunityService service.
+// All implementations must embed UnimplementedCommunityServiceServer
+// for forward compatibility.
+//
+// CommunityService отдает другим сервисам данные о сообществах
+// и ролях пользователей в них
+type CommunityServiceServer interface {
+	ResolveCommunity(context.Context, *ResolveCommunityRequest) (*CommunityResponse, error)
+	GetMemberRole(context.Context, *MemberRoleRequest) (*MemberRoleResponse, error)
+	mustEmbedUnimplementedCommunityServiceServer()
+}
+
+// UnimplementedCommunityServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCommunityServiceServer struct{}
+
+func (UnimplementedCommunityServiceServer) ResolveCommunity(context.Context, *ResolveCommunityRequest) (*CommunityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveCommunity not implemented")
+}
+func (UnimplementedCommunityServiceServer) GetMemberRole(context.Context, *MemberRoleRequest) (*MemberRoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMemberRole not implemented")
+}
+func (UnimplementedCommunityServiceServer) mustEmbedUnimplementedCommunityServiceServer() {}
+func (UnimplementedCommunityServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeCommunityServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CommunityServiceServer will
+// result in compilation errors.
+type UnsafeCommunityServiceServer interface {
+	mustEmbedUnimplementedCommunityServiceServer()
+}
+
+func RegisterCommunityServiceServer(s grpc.ServiceRegistrar, srv CommunityServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCommunityServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CommunityService_ServiceDesc, srv)
+}
+
+func _CommunityService_ResolveCommunity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveCommunityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommunityServiceServer).ResolveCommunity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommunityService_ResolveCommunity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommunityServiceServer).ResolveCommunity(ctx, req.(*ResolveCommunityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommunityService_GetMemberRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MemberRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommunityServiceServer).GetMemberRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommunityService_GetMemberRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommunityServiceServer).GetMemberRole(ctx, req.(*MemberRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CommunityService_ServiceDesc is the grpc.ServiceDesc for CommunityService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CommunityService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.CommunityService",
+	HandlerType: (*CommunityServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ResolveCommunity",
+			Handler:    _CommunityService_ResolveCommunity_Handler,
+		},
+		{
+			MethodName: "GetMemberRole",
+			Handler:    _CommunityService_GetMemberRole_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "community.proto",
+}
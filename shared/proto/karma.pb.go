@@ -0,0 +1,202 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        v5.29.3
+// source: karma.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type UserKarmaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserKarmaRequest) Reset() {
+	*x = UserKarmaRequest{}
+	mi := &file_karma_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserKarmaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserKarmaRequest) ProtoMessage() {}
+
+func (x *UserKarmaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_karma_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserKarmaRequest.ProtoReflect.Descriptor instead.
+func (*UserKarmaRequest) Descriptor() ([]byte, []int) {
+	return file_karma_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UserKarmaRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type UserKarmaResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Сумма голосов за темы и сообщения пользователя во всех сообществах
+	Karma         int64 `protobuf:"varint,1,opt,name=karma,proto3" json:"karma,omitempty"`
+	Upvotes       int64 `protobuf:"varint,2,opt,name=upvotes,proto3" json:"upvotes,omitempty"`
+	Downvotes     int64 `protobuf:"varint,3,opt,name=downvotes,proto3" json:"downvotes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserKarmaResponse) Reset() {
+	*x = UserKarmaResponse{}
+	mi := &file_karma_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserKarmaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserKarmaResponse) ProtoMessage() {}
+
+func (x *UserKarmaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_karma_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserKarmaResponse.ProtoReflect.Descriptor instead.
+func (*UserKarmaResponse) Descriptor() ([]byte, []int) {
+	return file_karma_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UserKarmaResponse) GetKarma() int64 {
+	if x != nil {
+		return x.Karma
+	}
+	return 0
+}
+
+func (x *UserKarmaResponse) GetUpvotes() int64 {
+	if x != nil {
+		return x.Upvotes
+	}
+	return 0
+}
+
+func (x *UserKarmaResponse) GetDownvotes() int64 {
+	if x != nil {
+		return x.Downvotes
+	}
+	return 0
+}
+
+var File_karma_proto protoreflect.FileDescriptor
+
+var file_karma_proto_rawDesc = string([]byte{
+	0x0a, 0x0b, 0x6b, 0x61, 0x72, 0x6d, 0x61, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x66,
+	0x6f, 0x72, 0x75, 0x6d, 0x22, 0x2b, 0x0a, 0x10, 0x55, 0x73, 0x65, 0x72, 0x4b, 0x61, 0x72, 0x6d,
+	0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
+	0x64, 0x22, 0x61, 0x0a, 0x11, 0x55, 0x73, 0x65, 0x72, 0x4b, 0x61, 0x72, 0x6d, 0x61, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6b, 0x61, 0x72, 0x6d, 0x61, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x6b, 0x61, 0x72, 0x6d, 0x61, 0x12, 0x18, 0x0a, 0x07,
+	0x75, 0x70, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x75,
+	0x70, 0x76, 0x6f, 0x74, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x6f, 0x77, 0x6e, 0x76, 0x6f,
+	0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x64, 0x6f, 0x77, 0x6e, 0x76,
+	0x6f, 0x74, 0x65, 0x73, 0x32, 0x51, 0x0a, 0x0c, 0x4b, 0x61, 0x72, 0x6d, 0x61, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x12, 0x41, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x4b,
+	0x61, 0x72, 0x6d, 0x61, 0x12, 0x17, 0x2e, 0x66, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x55, 0x73, 0x65,
+	0x72, 0x4b, 0x61, 0x72, 0x6d, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e,
+	0x66, 0x6f, 0x72, 0x75, 0x6d, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x4b, 0x61, 0x72, 0x6d, 0x61, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x2c, 0x5a, 0x2a, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x75, 0x63, 0x6b, 0x65, 0x72, 0x6d, 0x74, 0x2f, 0x66,
+	0x6f, 0x72, 0x75, 0x6d, 0x2d, 0x61, 0x70, 0x70, 0x2f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_karma_proto_rawDescOnce sync.Once
+	file_karma_proto_rawDescData []byte
+)
+
+func file_karma_proto_rawDescGZIP() []byte {
+	file_karma_proto_rawDescOnce.Do(func() {
+		file_karma_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_karma_proto_rawDesc), len(file_karma_proto_rawDesc)))
+	})
+	return file_karma_proto_rawDescData
+}
+
+var file_karma_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_karma_proto_goTypes = []any{
+	(*UserKarmaRequest)(nil),  // 0: forum.UserKarmaRequest
+	(*UserKarmaResponse)(nil), // 1: forum.UserKarmaResponse
+}
+var file_karma_proto_depIdxs = []int32{
+	0, // 0: forum.KarmaService.GetUserKarma:input_type -> forum.UserKarmaRequest
+	1, // 1: forum.KarmaService.GetUserKarma:output_type -> forum.UserKarmaResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_karma_proto_init() }
+func file_karma_proto_init() {
+	if File_karma_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_karma_proto_rawDesc), len(file_karma_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_karma_proto_goTypes,
+		DependencyIndexes: file_karma_proto_depIdxs,
+		MessageInfos:      file_karma_proto_msgTypes,
+	}.Build()
+	File_karma_proto = out.File
+	file_karma_proto_goTypes = nil
+	file_karma_proto_depIdxs = nil
+}
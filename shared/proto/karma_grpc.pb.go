@@ -0,0 +1,125 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: karma.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	KarmaService_GetUserKarma_FullMethodName = "/forum.KarmaService/GetUserKarma"
+)
+
+// KarmaServiceClient is the client API for KarmaService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// KarmaService отдает другим сервисам репутацию пользователей форума
+type KarmaServiceClient interface {
+	GetUserKarma(ctx context.Context, in *UserKarmaRequest, opts ...grpc.CallOption) (*UserKarmaResponse, error)
+}
+
+type karmaServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKarmaServiceClient(cc grpc.ClientConnInterface) KarmaServiceClient {
+	return &karmaServiceClient{cc}
+}
+
+func (c *karmaServiceClient) GetUserKarma(ctx context.Context, in *UserKarmaRequest, opts ...grpc.CallOption) (*UserKarmaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserKarmaResponse)
+	err := c.cc.Invoke(ctx, KarmaService_GetUserKarma_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// KarmaServiceServer is the server API for KarmaService service.
+// All implementations must embed UnimplementedKarmaServiceServer
+// for forward compatibility.
+//
+// KarmaService отдает другим сервисам репутацию пользователей форума
+type KarmaServiceServer interface {
+	GetUserKarma(context.Context, *UserKarmaRequest) (*UserKarmaResponse, error)
+	mustEmbedUnimplementedKarmaServiceServer()
+}
+
+// UnimplementedKarmaServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedKarmaServiceServer struct{}
+
+func (UnimplementedKarmaServiceServer) GetUserKarma(context.Context, *UserKarmaRequest) (*UserKarmaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserKarma not implemented")
+}
+func (UnimplementedKarmaServiceServer) mustEmbedUnimplementedKarmaServiceServer() {}
+func (UnimplementedKarmaServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeKarmaServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KarmaServiceServer will
+// result in compilation errors.
+type UnsafeKarmaServiceServer interface {
+	mustEmbedUnimplementedKarmaServiceServer()
+}
+
+func RegisterKarmaServiceServer(s grpc.ServiceRegistrar, srv KarmaServiceServer) {
+	// If the following call pancis, it indicates UnimplementedKarmaServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&KarmaService_ServiceDesc, srv)
+}
+
+func _KarmaService_GetUserKarma_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserKarmaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KarmaServiceServer).GetUserKarma(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KarmaService_GetUserKarma_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KarmaServiceServer).GetUserKarma(ctx, req.(*UserKarmaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// KarmaService_ServiceDesc is the grpc.ServiceDesc for KarmaService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var KarmaService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "forum.KarmaService",
+	HandlerType: (*KarmaServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetUserKarma",
+			Handler:    _KarmaService_GetUserKarma_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "karma.proto",
+}
@@ -0,0 +1,125 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: users.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	UserDirectoryService_ResolveUsernames_FullMethodName = "/auth.UserDirectoryService/ResolveUsernames"
+)
+
+// UserDirectoryServiceClient is the client API for UserDirectoryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// UserDirectoryService отдает другим сервисам публичные данные пользователей
+type UserDirectoryServiceClient interface {
+	ResolveUsernames(ctx context.Context, in *ResolveUsernamesRequest, opts ...grpc.CallOption) (*ResolveUsernamesResponse, error)
+}
+
+type userDirectoryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUserDirectoryServiceClient(cc grpc.ClientConnInterface) UserDirectoryServiceClient {
+	return &userDirectoryServiceClient{cc}
+}
+
+func (c *userDirectoryServiceClient) ResolveUsernames(ctx context.Context, in *ResolveUsernamesRequest, opts ...grpc.CallOption) (*ResolveUsernamesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveUsernamesResponse)
+	err := c.cc.Invoke(ctx, UserDirectoryService_ResolveUsernames_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UserDirectoryServiceServer is the server API for UserDirectoryService service.
+// All implementations must embed UnimplementedUserDirectoryServiceServer
+// for forward compatibility.
+//
+// UserDirectoryService отдает другим сервисам публичные данные пользователей
+type UserDirectoryServiceServer interface {
+	ResolveUsernames(context.Context, *ResolveUsernamesRequest) (*ResolveUsernamesResponse, error)
+	mustEmbedUnimplementedUserDirectoryServiceServer()
+}
+
+// UnimplementedUserDirectoryServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUserDirectoryServiceServer struct{}
+
+func (UnimplementedUserDirectoryServiceServer) ResolveUsernames(context.Context, *ResolveUsernamesRequest) (*ResolveUsernamesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveUsernames not implemented")
+}
+func (UnimplementedUserDirectoryServiceServer) mustEmbedUnimplementedUserDirectoryServiceServer() {}
+func (UnimplementedUserDirectoryServiceServer) testEmbeddedByValue()                              {}
+
+// UnsafeUserDirectoryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UserDirectoryServiceServer will
+// result in compilation errors.
+type UnsafeUserDirectoryServiceServer interface {
+	mustEmbedUnimplementedUserDirectoryServiceServer()
+}
+
+func RegisterUserDirectoryServiceServer(s grpc.ServiceRegistrar, srv UserDirectoryServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUserDirectoryServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UserDirectoryService_ServiceDesc, srv)
+}
+
+func _UserDirectoryService_ResolveUsernames_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveUsernamesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserDirectoryServiceServer).ResolveUsernames(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UserDirectoryService_ResolveUsernames_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserDirectoryServiceServer).ResolveUsernames(ctx, req.(*ResolveUsernamesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UserDirectoryService_ServiceDesc is the grpc.ServiceDesc for UserDirectoryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UserDirectoryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.UserDirectoryService",
+	HandlerType: (*UserDirectoryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ResolveUsernames",
+			Handler:    _UserDirectoryService_ResolveUsernames_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "users.proto",
+}